@@ -2,7 +2,12 @@ package config
 
 import (
 	"encoding/base64"
+	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
 )
 
 type Config struct {
@@ -10,6 +15,21 @@ type Config struct {
 
 	// JWTConfig is the JWT configuration.
 	JWTConfig *JWTConfig
+
+	// GitHubConfig is the GitHub configuration.
+	GitHubConfig *GitHubConfig
+
+	// GitLabConfig is the GitLab configuration.
+	GitLabConfig *GitLabConfig
+
+	// GiteaConfig is the Gitea and Forgejo configuration.
+	GiteaConfig *GiteaConfig
+
+	// LocalConfig is the configuration of the analysis of local repositories.
+	LocalConfig *LocalConfig
+
+	// MetricsConfig is the metrics configuration.
+	MetricsConfig *MetricsConfig
 }
 
 type DBConfig struct {
@@ -26,9 +46,39 @@ func New() (*Config, error) {
 		return nil, err
 	}
 
+	gitHubConfig, err := NewGitHubConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	gitLabConfig, err := NewGitLabConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	giteaConfig, err := NewGiteaConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	localConfig, err := NewLocalConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	metricsConfig, err := NewMetricsConfig()
+	if err != nil {
+		return nil, err
+	}
+
 	return &Config{
-		Port:      os.Getenv("PORT"),
-		JWTConfig: jwtConfig,
+		Port:          os.Getenv("PORT"),
+		JWTConfig:     jwtConfig,
+		GitHubConfig:  gitHubConfig,
+		GitLabConfig:  gitLabConfig,
+		GiteaConfig:   giteaConfig,
+		LocalConfig:   localConfig,
+		MetricsConfig: metricsConfig,
 	}, nil
 }
 
@@ -69,3 +119,427 @@ func NewJWTConfig() (*JWTConfig, error) {
 		AppRsaPub: []byte(appRsaPub),
 	}, nil
 }
+
+// GitHubConfig represents a GitHub configuration.
+type GitHubConfig struct {
+	// Token is the GitHub personal access token.
+	Token string
+
+	// MaxPages is the maximum number of pages fetched when paginating pull requests, zero means unbounded.
+	MaxPages int
+
+	// SyncDir is the directory where the incrementally synced pull requests are persisted.
+	SyncDir string
+
+	// Timeout is the timeout of each GitHub request.
+	Timeout time.Duration
+
+	// WebhookSecret is the secret the GitHub webhook payloads are signed with.
+	WebhookSecret string
+
+	// WebhookRegenerateGantt reports whether the Gantt artifacts are regenerated on GitHub webhook events.
+	WebhookRegenerateGantt bool
+
+	// Hosts are the GitHub Enterprise Server hosts, github.com repositories use the token above.
+	Hosts []*GitHubHostConfig
+
+	// AppID is the ID of the GitHub App the github.com requests are authenticated as, zero means the
+	// personal access token is used instead.
+	AppID int64
+
+	// AppPrivateKey is the PEM encoded RSA private key of the GitHub App.
+	AppPrivateKey []byte
+
+	// FixturesMode records the GitHub exchanges to fixture files when `record`, or replays them without
+	// any request to GitHub when `replay`, disabled when empty.
+	FixturesMode string
+
+	// FixturesDir is the directory where the GitHub fixtures are recorded.
+	FixturesDir string
+}
+
+// Host returns the configuration of the given GitHub Enterprise Server host, nil when it is not configured.
+func (c *GitHubConfig) Host(host string) *GitHubHostConfig {
+	for _, h := range c.Hosts {
+		if strings.EqualFold(h.Host, host) {
+			return h
+		}
+	}
+
+	return nil
+}
+
+// GitHubHostConfig represents the configuration of a GitHub Enterprise Server host.
+type GitHubHostConfig struct {
+	// Host is the host name of the repository URLs, e.g. `github.example.com`.
+	Host string
+
+	// GraphQLURL is the GraphQL API endpoint, defaults to `https://<host>/api/graphql`.
+	GraphQLURL string
+
+	// Token is the personal access token of the host.
+	Token string
+}
+
+// NewGitHubHostConfigs returns the configuration of the comma separated `GITHUB_HOSTS` hosts, each configured
+// by the `GITHUB_<HOST>_TOKEN` and `GITHUB_<HOST>_GRAPHQL_URL` variables, where
+// `<HOST>` is the upper-cased host with every other character than letters and digits replaced by `_`.
+func NewGitHubHostConfigs() []*GitHubHostConfig {
+	hosts := []*GitHubHostConfig{}
+
+	for _, host := range strings.Split(os.Getenv("GITHUB_HOSTS"), ",") {
+		host = strings.TrimSpace(host)
+		if host == "" {
+			continue
+		}
+
+		prefix := "GITHUB_" + envHost(host)
+
+		graphQLURL := os.Getenv(prefix + "_GRAPHQL_URL")
+		if graphQLURL == "" {
+			graphQLURL = fmt.Sprintf("https://%s/api/graphql", host)
+		}
+
+		hosts = append(hosts, &GitHubHostConfig{
+			Host:       host,
+			GraphQLURL: graphQLURL,
+			Token:      os.Getenv(prefix + "_TOKEN"),
+		})
+	}
+
+	return hosts
+}
+
+func NewGitHubConfig() (*GitHubConfig, error) {
+	maxPages := 0
+	if v := os.Getenv("GITHUB_MAX_PAGES"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, err
+		}
+		maxPages = n
+	}
+
+	timeout := 30 * time.Second
+	if v := os.Getenv("GITHUB_TIMEOUT"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, err
+		}
+		timeout = d
+	}
+
+	webhookRegenerateGantt := false
+	if v := os.Getenv("GITHUB_WEBHOOK_REGENERATE_GANTT"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, err
+		}
+		webhookRegenerateGantt = b
+	}
+
+	syncDir := os.Getenv("GITHUB_SYNC_DIR")
+	if syncDir == "" {
+		syncDir = "assets/generated/sync"
+	}
+
+	var appID int64
+	if v := os.Getenv("GITHUB_APP_ID"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		appID = n
+	}
+
+	// base64 -w 0 app.private-key.pem
+	appPrivateKey, err := base64.StdEncoding.DecodeString(os.Getenv("GITHUB_APP_PRIVATE_KEY"))
+	if err != nil {
+		return nil, err
+	}
+
+	fixturesMode := os.Getenv("GITHUB_FIXTURES_MODE")
+	if fixturesMode != "" && fixturesMode != "record" && fixturesMode != "replay" {
+		return nil, fmt.Errorf("invalid GITHUB_FIXTURES_MODE %q, expected record or replay", fixturesMode)
+	}
+
+	fixturesDir := os.Getenv("GITHUB_FIXTURES_DIR")
+	if fixturesDir == "" {
+		fixturesDir = "assets/fixtures/github"
+	}
+
+	return &GitHubConfig{
+		Token:    os.Getenv("GITHUB_TOKEN"),
+		MaxPages: maxPages,
+		SyncDir:  syncDir,
+		Timeout:  timeout,
+
+		WebhookSecret:          os.Getenv("GITHUB_WEBHOOK_SECRET"),
+		WebhookRegenerateGantt: webhookRegenerateGantt,
+
+		Hosts: NewGitHubHostConfigs(),
+
+		AppID:         appID,
+		AppPrivateKey: appPrivateKey,
+
+		FixturesMode: fixturesMode,
+		FixturesDir:  fixturesDir,
+	}, nil
+}
+
+// GitLabConfig represents a GitLab configuration.
+type GitLabConfig struct {
+	// Hosts are the GitLab hosts, e.g. `gitlab.com` or a self-managed instance.
+	Hosts []*GitLabHostConfig
+
+	// Timeout is the timeout of each GitLab request.
+	Timeout time.Duration
+}
+
+// GitLabHostConfig represents the configuration of a GitLab host.
+type GitLabHostConfig struct {
+	// Host is the host name of the project URLs, e.g. `gitlab.com`.
+	Host string
+
+	// APIURL is the REST API endpoint, defaults to `https://<host>/api/v4`.
+	APIURL string
+
+	// Token is the access token of the host.
+	Token string
+
+	// MaxPages is the maximum number of pages fetched when paginating merge requests, zero means unbounded.
+	MaxPages int
+}
+
+// DefaultGitLabMaxPages is the default maximum number of pages of merge requests fetched from GitLab, each
+// merge request costing a couple more requests.
+const DefaultGitLabMaxPages = 10
+
+// NewGitLabConfig returns the configuration of the comma separated `GITLAB_HOSTS` hosts, each configured by
+// the `GITLAB_<HOST>_TOKEN` and `GITLAB_<HOST>_API_URL` variables, where `<HOST>` is the upper-cased host
+// with every other character than letters and digits replaced by `_`.
+func NewGitLabConfig() (*GitLabConfig, error) {
+	maxPages := DefaultGitLabMaxPages
+	if v := os.Getenv("GITLAB_MAX_PAGES"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, err
+		}
+		maxPages = n
+	}
+
+	timeout := 30 * time.Second
+	if v := os.Getenv("GITLAB_TIMEOUT"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, err
+		}
+		timeout = d
+	}
+
+	hosts := []*GitLabHostConfig{}
+
+	for _, host := range strings.Split(os.Getenv("GITLAB_HOSTS"), ",") {
+		host = strings.TrimSpace(host)
+		if host == "" {
+			continue
+		}
+
+		prefix := "GITLAB_" + envHost(host)
+
+		apiURL := os.Getenv(prefix + "_API_URL")
+		if apiURL == "" {
+			apiURL = fmt.Sprintf("https://%s/api/v4", host)
+		}
+
+		hosts = append(hosts, &GitLabHostConfig{
+			Host:     host,
+			APIURL:   apiURL,
+			Token:    os.Getenv(prefix + "_TOKEN"),
+			MaxPages: maxPages,
+		})
+	}
+
+	return &GitLabConfig{
+		Hosts:   hosts,
+		Timeout: timeout,
+	}, nil
+}
+
+// GiteaConfig represents a Gitea and Forgejo configuration.
+type GiteaConfig struct {
+	// Hosts are the Gitea or Forgejo hosts.
+	Hosts []*GiteaHostConfig
+
+	// Timeout is the timeout of each Gitea request.
+	Timeout time.Duration
+}
+
+// GiteaHostConfig represents the configuration of a Gitea or Forgejo host.
+type GiteaHostConfig struct {
+	// Host is the host name of the repository URLs, e.g. `gitea.example.com`.
+	Host string
+
+	// APIURL is the REST API endpoint, defaults to `https://<host>/api/v1`.
+	APIURL string
+
+	// Token is the access token of the host.
+	Token string
+
+	// MaxPages is the maximum number of pages fetched when paginating pull requests, zero means unbounded.
+	MaxPages int
+}
+
+// NewGiteaConfig returns the configuration of the comma separated `GITEA_HOSTS` hosts, each configured by
+// the `GITEA_<HOST>_TOKEN` and `GITEA_<HOST>_API_URL` variables, where `<HOST>` is the upper-cased host
+// with every other character than letters and digits replaced by `_`.
+func NewGiteaConfig() (*GiteaConfig, error) {
+	maxPages := 0
+	if v := os.Getenv("GITEA_MAX_PAGES"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, err
+		}
+		maxPages = n
+	}
+
+	timeout := 30 * time.Second
+	if v := os.Getenv("GITEA_TIMEOUT"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, err
+		}
+		timeout = d
+	}
+
+	hosts := []*GiteaHostConfig{}
+
+	for _, host := range strings.Split(os.Getenv("GITEA_HOSTS"), ",") {
+		host = strings.TrimSpace(host)
+		if host == "" {
+			continue
+		}
+
+		prefix := "GITEA_" + envHost(host)
+
+		apiURL := os.Getenv(prefix + "_API_URL")
+		if apiURL == "" {
+			apiURL = fmt.Sprintf("https://%s/api/v1", host)
+		}
+
+		hosts = append(hosts, &GiteaHostConfig{
+			Host:     host,
+			APIURL:   apiURL,
+			Token:    os.Getenv(prefix + "_TOKEN"),
+			MaxPages: maxPages,
+		})
+	}
+
+	return &GiteaConfig{
+		Hosts:   hosts,
+		Timeout: timeout,
+	}, nil
+}
+
+// LocalConfig represents the configuration of the analysis of the repositories cloned on the local filesystem.
+type LocalConfig struct {
+	// RepositoriesDir is the directory the analyzed repositories must be in, local analysis is disabled when empty.
+	RepositoriesDir string
+
+	// Timeout is the timeout of each git command.
+	Timeout time.Duration
+}
+
+func NewLocalConfig() (*LocalConfig, error) {
+	timeout := time.Minute
+	if v := os.Getenv("LOCAL_GIT_TIMEOUT"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, err
+		}
+		timeout = d
+	}
+
+	return &LocalConfig{
+		RepositoriesDir: os.Getenv("LOCAL_REPOSITORIES_DIR"),
+		Timeout:         timeout,
+	}, nil
+}
+
+// envHost returns the given host as part of an environment variable name.
+func envHost(host string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToUpper(r)
+		}
+		return '_'
+	}, host)
+}
+
+// MetricsConfig represents a metrics configuration.
+type MetricsConfig struct {
+	// SyncInterval is the time between two background syncs of the tracked repositories, zero disables them.
+	SyncInterval time.Duration
+
+	// Components are the glob patterns of the paths of the named components of monorepos.
+	Components map[string][]string
+
+	// MaxOrganizationRepositories is the maximum number of repositories of an organization analyzed at once, zero
+	// means every repository.
+	MaxOrganizationRepositories int
+}
+
+// DefaultMaxOrganizationRepositories is the default maximum number of repositories of an organization analyzed
+// at once, each costing the fetch of its pull requests.
+const DefaultMaxOrganizationRepositories = 50
+
+// NewMetricsConfig returns the metrics configuration, with the comma separated `METRICS_COMPONENTS` components
+// each configured by the comma separated glob patterns of the `METRICS_COMPONENT_<NAME>_PATHS` variable, where
+// `<NAME>` is the upper-cased name with every other character than letters and digits replaced by `_`.
+func NewMetricsConfig() (*MetricsConfig, error) {
+	syncInterval := time.Hour
+	if v := os.Getenv("METRICS_SYNC_INTERVAL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, err
+		}
+		syncInterval = d
+	}
+
+	maxOrganizationRepositories := DefaultMaxOrganizationRepositories
+	if v := os.Getenv("METRICS_MAX_ORGANIZATION_REPOSITORIES"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, err
+		}
+		maxOrganizationRepositories = n
+	}
+
+	components := map[string][]string{}
+
+	for _, name := range strings.Split(os.Getenv("METRICS_COMPONENTS"), ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		paths := []string{}
+		for _, path := range strings.Split(os.Getenv("METRICS_COMPONENT_"+envHost(name)+"_PATHS"), ",") {
+			if path = strings.TrimSpace(path); path != "" {
+				paths = append(paths, path)
+			}
+		}
+
+		if len(paths) == 0 {
+			return nil, fmt.Errorf("component %q has no paths", name)
+		}
+
+		components[name] = paths
+	}
+
+	return &MetricsConfig{
+		SyncInterval:                syncInterval,
+		Components:                  components,
+		MaxOrganizationRepositories: maxOrganizationRepositories,
+	}, nil
+}
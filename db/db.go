@@ -58,6 +58,21 @@ func (db *db) Queries() *models.Queries {
 	return db.queries
 }
 
+// WithTx runs the given function within a transaction, rolled back when the function fails.
+func (db *db) WithTx(ctx context.Context, fn func(queries *models.Queries) error) error {
+	tx, err := db.sqlDB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(db.queries.WithTx(tx)); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
 func New(dbConfig *config.DBConfig) (*db, error) {
 	connStr := fmt.Sprintf("user=%s password=%s host=%s dbname=%s sslmode=%s",
 		dbConfig.User, dbConfig.PWD, dbConfig.Host, dbConfig.Name, dbConfig.SSLMode)
@@ -80,4 +95,5 @@ type DB interface {
 	Close() error
 	Migrate() error
 	Queries() *models.Queries
+	WithTx(ctx context.Context, fn func(queries *models.Queries) error) error
 }
@@ -0,0 +1,5 @@
+ALTER TABLE repositories
+    ADD COLUMN synced_at timestamp with time zone NULL;
+ALTER TABLE repositories
+    ADD COLUMN synced_states TEXT[] DEFAULT '{}' NOT NULL;
+DROP TABLE repository_syncs;
@@ -0,0 +1,11 @@
+CREATE TABLE repository_syncs
+(
+    repository_id INTEGER                  NOT NULL REFERENCES repositories (id) ON DELETE CASCADE,
+    state         TEXT                     NOT NULL,
+    synced_at     timestamp with time zone NOT NULL,
+    PRIMARY KEY (repository_id, state)
+);
+ALTER TABLE repositories
+    DROP COLUMN synced_at;
+ALTER TABLE repositories
+    DROP COLUMN synced_states;
@@ -0,0 +1,12 @@
+DROP INDEX contributors_host_email_key;
+DROP INDEX contributors_host_login_key;
+DELETE
+FROM contributors c
+    USING contributors k
+WHERE c.login = k.login
+  AND c.email = k.email
+  AND (c.updated_at, c.id) < (k.updated_at, k.id);
+ALTER TABLE contributors
+    DROP COLUMN host;
+ALTER TABLE contributors
+    ADD CONSTRAINT contributors_login_email_key UNIQUE (login, email);
@@ -0,0 +1,23 @@
+ALTER TABLE contributors
+    ADD COLUMN host TEXT DEFAULT 'github.com' NOT NULL;
+UPDATE contributors
+SET host = repositories.host
+FROM pull_request_participants
+         JOIN pull_requests ON pull_requests.id = pull_request_participants.pull_request_id
+         JOIN repositories ON repositories.id = pull_requests.repository_id
+WHERE pull_request_participants.contributor_id = contributors.id;
+DELETE
+FROM contributors c
+    USING contributors k
+WHERE c.login <> ''
+  AND c.host = k.host
+  AND c.login = k.login
+  AND (c.updated_at, c.id) < (k.updated_at, k.id);
+ALTER TABLE contributors
+    DROP CONSTRAINT contributors_login_email_key;
+CREATE UNIQUE INDEX contributors_host_login_key ON contributors (host, login) WHERE login <> '';
+CREATE UNIQUE INDEX contributors_host_email_key ON contributors (host, email) WHERE login = '';
+-- Resyncs every repository, whose persisted pull requests miss the columns added since they were synced and
+-- the participants of the contributors deduplicated above.
+DELETE
+FROM repository_syncs;
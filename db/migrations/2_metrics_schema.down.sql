@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS
+    pull_request_participants,
+    contributors,
+    pull_requests,
+    repositories CASCADE;
@@ -0,0 +1,55 @@
+CREATE TABLE repositories
+(
+    id            SERIAL PRIMARY KEY,
+    owner         TEXT                                               NOT NULL,
+    name          TEXT                                               NOT NULL,
+    synced_at     timestamp with time zone                           NULL,
+    synced_states TEXT[]                   DEFAULT '{}'              NOT NULL,
+    created_at    timestamp with time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at    timestamp with time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    UNIQUE (owner, name)
+);
+
+CREATE TABLE pull_requests
+(
+    id                  SERIAL PRIMARY KEY,
+    repository_id       INTEGER                                            NOT NULL REFERENCES repositories (id) ON DELETE CASCADE,
+    number              INTEGER                                            NOT NULL,
+    url                 TEXT                                               NOT NULL,
+    title               TEXT                                               NOT NULL,
+    body                TEXT                                               NOT NULL,
+    state               TEXT                                               NOT NULL,
+    is_draft            BOOLEAN                  DEFAULT FALSE             NOT NULL,
+    head_ref_name       TEXT                                               NOT NULL,
+    review_decision     TEXT                     DEFAULT ''                NOT NULL,
+    ci_status           TEXT                     DEFAULT ''                NOT NULL,
+    requested_reviewers TEXT[]                   DEFAULT '{}'              NOT NULL,
+    opened_at           timestamp with time zone                           NOT NULL,
+    merged_at           timestamp with time zone                           NULL,
+    closed_at           timestamp with time zone                           NULL,
+    last_activity_at    timestamp with time zone                           NULL,
+    created_at          timestamp with time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at          timestamp with time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    UNIQUE (repository_id, number)
+);
+
+CREATE TABLE contributors
+(
+    id          SERIAL PRIMARY KEY,
+    login       TEXT                                               NOT NULL,
+    email       TEXT                                               NOT NULL,
+    name        TEXT                     DEFAULT ''                NOT NULL,
+    profile_url TEXT                     DEFAULT ''                NOT NULL,
+    created_at  timestamp with time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at  timestamp with time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    UNIQUE (login, email)
+);
+
+CREATE TABLE pull_request_participants
+(
+    pull_request_id INTEGER NOT NULL REFERENCES pull_requests (id) ON DELETE CASCADE,
+    contributor_id  INTEGER NOT NULL REFERENCES contributors (id) ON DELETE CASCADE,
+    role            TEXT    NOT NULL,
+    position        INTEGER NOT NULL,
+    PRIMARY KEY (pull_request_id, contributor_id)
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS
+    tracked_repositories CASCADE;
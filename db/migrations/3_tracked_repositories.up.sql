@@ -0,0 +1,11 @@
+CREATE TABLE tracked_repositories
+(
+    id               SERIAL PRIMARY KEY,
+    repository_id    INTEGER                                            NOT NULL UNIQUE REFERENCES repositories (id) ON DELETE CASCADE,
+    url              TEXT                                               NOT NULL,
+    last_synced_at   timestamp with time zone                           NULL,
+    last_sync_status TEXT                     DEFAULT 'PENDING'         NOT NULL,
+    last_sync_error  TEXT                     DEFAULT ''                NOT NULL,
+    created_at       timestamp with time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at       timestamp with time zone DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
@@ -0,0 +1,9 @@
+ALTER TABLE repositories
+    DROP CONSTRAINT repositories_host_owner_name_key;
+DELETE
+FROM repositories
+WHERE host <> 'github.com';
+ALTER TABLE repositories
+    DROP COLUMN host;
+ALTER TABLE repositories
+    ADD CONSTRAINT repositories_owner_name_key UNIQUE (owner, name);
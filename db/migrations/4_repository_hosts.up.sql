@@ -0,0 +1,6 @@
+ALTER TABLE repositories
+    ADD COLUMN host TEXT DEFAULT 'github.com' NOT NULL;
+ALTER TABLE repositories
+    DROP CONSTRAINT repositories_owner_name_key;
+ALTER TABLE repositories
+    ADD CONSTRAINT repositories_host_owner_name_key UNIQUE (host, owner, name);
@@ -0,0 +1,16 @@
+CREATE TABLE pull_request_linked_issues
+(
+    pull_request_id INTEGER                  NOT NULL REFERENCES pull_requests (id) ON DELETE CASCADE,
+    position        INTEGER                  NOT NULL,
+    owner           TEXT                     NOT NULL,
+    repo            TEXT                     NOT NULL,
+    number          INTEGER                  NOT NULL,
+    url             TEXT                     NOT NULL,
+    title           TEXT                     NOT NULL,
+    body            TEXT                     NOT NULL,
+    state           TEXT                     NOT NULL,
+    author_login    TEXT DEFAULT ''          NOT NULL,
+    opened_at       timestamp with time zone NOT NULL,
+    closed_at       timestamp with time zone NULL,
+    PRIMARY KEY (pull_request_id, position)
+);
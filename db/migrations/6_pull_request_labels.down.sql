@@ -0,0 +1,6 @@
+ALTER TABLE pull_requests
+    DROP COLUMN assignees;
+ALTER TABLE pull_requests
+    DROP COLUMN milestone;
+ALTER TABLE pull_requests
+    DROP COLUMN labels;
@@ -0,0 +1,6 @@
+ALTER TABLE pull_requests
+    ADD COLUMN labels TEXT[] DEFAULT '{}' NOT NULL;
+ALTER TABLE pull_requests
+    ADD COLUMN milestone TEXT DEFAULT '' NOT NULL;
+ALTER TABLE pull_requests
+    ADD COLUMN assignees TEXT[] DEFAULT '{}' NOT NULL;
@@ -0,0 +1,2 @@
+ALTER TABLE pull_requests
+    DROP COLUMN files;
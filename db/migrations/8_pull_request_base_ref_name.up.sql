@@ -0,0 +1,2 @@
+ALTER TABLE pull_requests
+    ADD COLUMN base_ref_name TEXT DEFAULT '' NOT NULL;
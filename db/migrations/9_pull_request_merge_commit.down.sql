@@ -0,0 +1,9 @@
+ALTER TABLE pull_requests
+    DROP CONSTRAINT pull_requests_repository_id_number_merge_commit_key;
+DELETE
+FROM pull_requests
+WHERE merge_commit <> '';
+ALTER TABLE pull_requests
+    ADD CONSTRAINT pull_requests_repository_id_number_key UNIQUE (repository_id, number);
+ALTER TABLE pull_requests
+    DROP COLUMN merge_commit;
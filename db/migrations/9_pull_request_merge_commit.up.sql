@@ -0,0 +1,9 @@
+ALTER TABLE pull_requests
+    ADD COLUMN merge_commit TEXT DEFAULT '' NOT NULL;
+ALTER TABLE pull_requests
+    DROP CONSTRAINT pull_requests_repository_id_number_key;
+ALTER TABLE pull_requests
+    ADD CONSTRAINT pull_requests_repository_id_number_merge_commit_key UNIQUE (repository_id, number, merge_commit);
+DELETE
+FROM pull_requests
+WHERE number = 0;
@@ -9,6 +9,92 @@ import (
 	"time"
 )
 
+type Contributor struct {
+	ID         int32
+	Login      string
+	Email      string
+	Name       string
+	ProfileUrl string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	Host       string
+}
+
+type PullRequest struct {
+	ID                 int32
+	RepositoryID       int32
+	Number             int32
+	Url                string
+	Title              string
+	Body               string
+	State              string
+	IsDraft            bool
+	HeadRefName        string
+	ReviewDecision     string
+	CiStatus           string
+	RequestedReviewers []string
+	OpenedAt           time.Time
+	MergedAt           sql.NullTime
+	ClosedAt           sql.NullTime
+	LastActivityAt     sql.NullTime
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+	Labels             []string
+	Milestone          string
+	Assignees          []string
+	Files              []string
+	BaseRefName        string
+	MergeCommit        string
+}
+
+type PullRequestLinkedIssue struct {
+	PullRequestID int32
+	Position      int32
+	Owner         string
+	Repo          string
+	Number        int32
+	Url           string
+	Title         string
+	Body          string
+	State         string
+	AuthorLogin   string
+	OpenedAt      time.Time
+	ClosedAt      sql.NullTime
+}
+
+type PullRequestParticipant struct {
+	PullRequestID int32
+	ContributorID int32
+	Role          string
+	Position      int32
+}
+
+type Repository struct {
+	ID        int32
+	Owner     string
+	Name      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Host      string
+}
+
+type RepositorySync struct {
+	RepositoryID int32
+	State        string
+	SyncedAt     time.Time
+}
+
+type TrackedRepository struct {
+	ID             int32
+	RepositoryID   int32
+	Url            string
+	LastSyncedAt   sql.NullTime
+	LastSyncStatus string
+	LastSyncError  string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
 type User struct {
 	ID        int32
 	Uuid      string
@@ -7,8 +7,390 @@ package models
 
 import (
 	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
 )
 
+const createPullRequestLinkedIssue = `-- name: CreatePullRequestLinkedIssue :exec
+INSERT INTO pull_request_linked_issues (pull_request_id, position, owner, repo, number, url, title, body, state,
+                                        author_login, opened_at, closed_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+`
+
+type CreatePullRequestLinkedIssueParams struct {
+	PullRequestID int32
+	Position      int32
+	Owner         string
+	Repo          string
+	Number        int32
+	Url           string
+	Title         string
+	Body          string
+	State         string
+	AuthorLogin   string
+	OpenedAt      time.Time
+	ClosedAt      sql.NullTime
+}
+
+func (q *Queries) CreatePullRequestLinkedIssue(ctx context.Context, arg CreatePullRequestLinkedIssueParams) error {
+	_, err := q.db.ExecContext(ctx, createPullRequestLinkedIssue,
+		arg.PullRequestID,
+		arg.Position,
+		arg.Owner,
+		arg.Repo,
+		arg.Number,
+		arg.Url,
+		arg.Title,
+		arg.Body,
+		arg.State,
+		arg.AuthorLogin,
+		arg.OpenedAt,
+		arg.ClosedAt,
+	)
+	return err
+}
+
+const createPullRequestParticipant = `-- name: CreatePullRequestParticipant :exec
+INSERT INTO pull_request_participants (pull_request_id, contributor_id, role, position)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (pull_request_id, contributor_id) DO NOTHING
+`
+
+type CreatePullRequestParticipantParams struct {
+	PullRequestID int32
+	ContributorID int32
+	Role          string
+	Position      int32
+}
+
+func (q *Queries) CreatePullRequestParticipant(ctx context.Context, arg CreatePullRequestParticipantParams) error {
+	_, err := q.db.ExecContext(ctx, createPullRequestParticipant,
+		arg.PullRequestID,
+		arg.ContributorID,
+		arg.Role,
+		arg.Position,
+	)
+	return err
+}
+
+const deletePullRequestLinkedIssues = `-- name: DeletePullRequestLinkedIssues :exec
+DELETE
+FROM pull_request_linked_issues
+WHERE pull_request_id = $1
+`
+
+func (q *Queries) DeletePullRequestLinkedIssues(ctx context.Context, pullRequestID int32) error {
+	_, err := q.db.ExecContext(ctx, deletePullRequestLinkedIssues, pullRequestID)
+	return err
+}
+
+const deletePullRequestParticipants = `-- name: DeletePullRequestParticipants :exec
+DELETE
+FROM pull_request_participants
+WHERE pull_request_id = $1
+`
+
+func (q *Queries) DeletePullRequestParticipants(ctx context.Context, pullRequestID int32) error {
+	_, err := q.db.ExecContext(ctx, deletePullRequestParticipants, pullRequestID)
+	return err
+}
+
+const deleteStalePullRequests = `-- name: DeleteStalePullRequests :exec
+DELETE
+FROM pull_requests
+WHERE repository_id = $1
+  AND state = ANY ($2::text[])
+  AND NOT id = ANY ($3::int[])
+`
+
+type DeleteStalePullRequestsParams struct {
+	RepositoryID int32
+	States       []string
+	Ids          []int32
+}
+
+func (q *Queries) DeleteStalePullRequests(ctx context.Context, arg DeleteStalePullRequestsParams) error {
+	_, err := q.db.ExecContext(ctx, deleteStalePullRequests, arg.RepositoryID, pq.Array(arg.States), pq.Array(arg.Ids))
+	return err
+}
+
+const getRepository = `-- name: GetRepository :one
+SELECT id, owner, name, created_at, updated_at, host
+FROM repositories
+WHERE host = $1
+  AND owner = $2
+  AND name = $3
+`
+
+type GetRepositoryParams struct {
+	Host  string
+	Owner string
+	Name  string
+}
+
+func (q *Queries) GetRepository(ctx context.Context, arg GetRepositoryParams) (Repository, error) {
+	row := q.db.QueryRowContext(ctx, getRepository, arg.Host, arg.Owner, arg.Name)
+	var i Repository
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Name,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Host,
+	)
+	return i, err
+}
+
+const listPullRequestLinkedIssues = `-- name: ListPullRequestLinkedIssues :many
+SELECT pull_request_linked_issues.pull_request_id, pull_request_linked_issues.position, pull_request_linked_issues.owner, pull_request_linked_issues.repo, pull_request_linked_issues.number, pull_request_linked_issues.url, pull_request_linked_issues.title, pull_request_linked_issues.body, pull_request_linked_issues.state, pull_request_linked_issues.author_login, pull_request_linked_issues.opened_at, pull_request_linked_issues.closed_at
+FROM pull_request_linked_issues
+         JOIN pull_requests ON pull_requests.id = pull_request_linked_issues.pull_request_id
+WHERE pull_requests.repository_id = $1
+ORDER BY pull_request_linked_issues.pull_request_id, pull_request_linked_issues.position
+`
+
+func (q *Queries) ListPullRequestLinkedIssues(ctx context.Context, repositoryID int32) ([]PullRequestLinkedIssue, error) {
+	rows, err := q.db.QueryContext(ctx, listPullRequestLinkedIssues, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PullRequestLinkedIssue
+	for rows.Next() {
+		var i PullRequestLinkedIssue
+		if err := rows.Scan(
+			&i.PullRequestID,
+			&i.Position,
+			&i.Owner,
+			&i.Repo,
+			&i.Number,
+			&i.Url,
+			&i.Title,
+			&i.Body,
+			&i.State,
+			&i.AuthorLogin,
+			&i.OpenedAt,
+			&i.ClosedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPullRequestParticipants = `-- name: ListPullRequestParticipants :many
+SELECT pull_request_participants.pull_request_id,
+       pull_request_participants.role,
+       contributors.login,
+       contributors.email,
+       contributors.name,
+       contributors.profile_url
+FROM pull_request_participants
+         JOIN contributors ON contributors.id = pull_request_participants.contributor_id
+         JOIN pull_requests ON pull_requests.id = pull_request_participants.pull_request_id
+WHERE pull_requests.repository_id = $1
+ORDER BY pull_request_participants.pull_request_id, pull_request_participants.position
+`
+
+type ListPullRequestParticipantsRow struct {
+	PullRequestID int32
+	Role          string
+	Login         string
+	Email         string
+	Name          string
+	ProfileUrl    string
+}
+
+func (q *Queries) ListPullRequestParticipants(ctx context.Context, repositoryID int32) ([]ListPullRequestParticipantsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listPullRequestParticipants, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListPullRequestParticipantsRow
+	for rows.Next() {
+		var i ListPullRequestParticipantsRow
+		if err := rows.Scan(
+			&i.PullRequestID,
+			&i.Role,
+			&i.Login,
+			&i.Email,
+			&i.Name,
+			&i.ProfileUrl,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPullRequests = `-- name: ListPullRequests :many
+SELECT id, repository_id, number, url, title, body, state, is_draft, head_ref_name, review_decision, ci_status, requested_reviewers, opened_at, merged_at, closed_at, last_activity_at, created_at, updated_at, labels, milestone, assignees, files, base_ref_name, merge_commit
+FROM pull_requests
+WHERE repository_id = $1
+  AND state = ANY ($2::text[])
+ORDER BY opened_at DESC
+`
+
+type ListPullRequestsParams struct {
+	RepositoryID int32
+	States       []string
+}
+
+func (q *Queries) ListPullRequests(ctx context.Context, arg ListPullRequestsParams) ([]PullRequest, error) {
+	rows, err := q.db.QueryContext(ctx, listPullRequests, arg.RepositoryID, pq.Array(arg.States))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PullRequest
+	for rows.Next() {
+		var i PullRequest
+		if err := rows.Scan(
+			&i.ID,
+			&i.RepositoryID,
+			&i.Number,
+			&i.Url,
+			&i.Title,
+			&i.Body,
+			&i.State,
+			&i.IsDraft,
+			&i.HeadRefName,
+			&i.ReviewDecision,
+			&i.CiStatus,
+			pq.Array(&i.RequestedReviewers),
+			&i.OpenedAt,
+			&i.MergedAt,
+			&i.ClosedAt,
+			&i.LastActivityAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			pq.Array(&i.Labels),
+			&i.Milestone,
+			pq.Array(&i.Assignees),
+			pq.Array(&i.Files),
+			&i.BaseRefName,
+			&i.MergeCommit,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRepositorySyncs = `-- name: ListRepositorySyncs :many
+SELECT repository_id, state, synced_at
+FROM repository_syncs
+WHERE repository_id = $1
+`
+
+func (q *Queries) ListRepositorySyncs(ctx context.Context, repositoryID int32) ([]RepositorySync, error) {
+	rows, err := q.db.QueryContext(ctx, listRepositorySyncs, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []RepositorySync
+	for rows.Next() {
+		var i RepositorySync
+		if err := rows.Scan(&i.RepositoryID, &i.State, &i.SyncedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTrackedRepositories = `-- name: ListTrackedRepositories :many
+SELECT tracked_repositories.id,
+       repositories.host,
+       repositories.owner,
+       repositories.name,
+       tracked_repositories.url,
+       tracked_repositories.last_synced_at,
+       tracked_repositories.last_sync_status,
+       tracked_repositories.last_sync_error,
+       tracked_repositories.created_at
+FROM tracked_repositories
+         JOIN repositories ON repositories.id = tracked_repositories.repository_id
+ORDER BY tracked_repositories.created_at
+`
+
+type ListTrackedRepositoriesRow struct {
+	ID             int32
+	Host           string
+	Owner          string
+	Name           string
+	Url            string
+	LastSyncedAt   sql.NullTime
+	LastSyncStatus string
+	LastSyncError  string
+	CreatedAt      time.Time
+}
+
+func (q *Queries) ListTrackedRepositories(ctx context.Context) ([]ListTrackedRepositoriesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listTrackedRepositories)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListTrackedRepositoriesRow
+	for rows.Next() {
+		var i ListTrackedRepositoriesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Host,
+			&i.Owner,
+			&i.Name,
+			&i.Url,
+			&i.LastSyncedAt,
+			&i.LastSyncStatus,
+			&i.LastSyncError,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listUsers = `-- name: ListUsers :many
 SELECT id, uuid, username, first_name, last_name, created_at, updated_at
 FROM users
@@ -51,3 +433,264 @@ func (q *Queries) ListUsers(ctx context.Context, arg ListUsersParams) ([]User, e
 	}
 	return items, nil
 }
+
+const trackRepository = `-- name: TrackRepository :one
+INSERT INTO tracked_repositories (repository_id, url)
+VALUES ($1, $2)
+ON CONFLICT (repository_id) DO UPDATE SET url        = EXCLUDED.url,
+                                          updated_at = CURRENT_TIMESTAMP
+RETURNING id, repository_id, url, last_synced_at, last_sync_status, last_sync_error, created_at, updated_at
+`
+
+type TrackRepositoryParams struct {
+	RepositoryID int32
+	Url          string
+}
+
+func (q *Queries) TrackRepository(ctx context.Context, arg TrackRepositoryParams) (TrackedRepository, error) {
+	row := q.db.QueryRowContext(ctx, trackRepository, arg.RepositoryID, arg.Url)
+	var i TrackedRepository
+	err := row.Scan(
+		&i.ID,
+		&i.RepositoryID,
+		&i.Url,
+		&i.LastSyncedAt,
+		&i.LastSyncStatus,
+		&i.LastSyncError,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const untrackRepository = `-- name: UntrackRepository :execrows
+DELETE
+FROM tracked_repositories USING repositories
+WHERE tracked_repositories.repository_id = repositories.id
+  AND repositories.host = $1
+  AND repositories.owner = $2
+  AND repositories.name = $3
+`
+
+type UntrackRepositoryParams struct {
+	Host  string
+	Owner string
+	Name  string
+}
+
+func (q *Queries) UntrackRepository(ctx context.Context, arg UntrackRepositoryParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, untrackRepository, arg.Host, arg.Owner, arg.Name)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updateTrackedRepositorySync = `-- name: UpdateTrackedRepositorySync :exec
+UPDATE tracked_repositories
+SET last_synced_at   = $2,
+    last_sync_status = $3,
+    last_sync_error  = $4,
+    updated_at       = CURRENT_TIMESTAMP
+WHERE id = $1
+`
+
+type UpdateTrackedRepositorySyncParams struct {
+	ID             int32
+	LastSyncedAt   sql.NullTime
+	LastSyncStatus string
+	LastSyncError  string
+}
+
+func (q *Queries) UpdateTrackedRepositorySync(ctx context.Context, arg UpdateTrackedRepositorySyncParams) error {
+	_, err := q.db.ExecContext(ctx, updateTrackedRepositorySync,
+		arg.ID,
+		arg.LastSyncedAt,
+		arg.LastSyncStatus,
+		arg.LastSyncError,
+	)
+	return err
+}
+
+const upsertContributorByEmail = `-- name: UpsertContributorByEmail :one
+INSERT INTO contributors (host, login, email, name, profile_url)
+VALUES ($1, '', $2, $3, $4)
+ON CONFLICT (host, email) WHERE login = '' DO UPDATE SET name        = EXCLUDED.name,
+                                                         profile_url = EXCLUDED.profile_url,
+                                                         updated_at  = CURRENT_TIMESTAMP
+RETURNING id
+`
+
+type UpsertContributorByEmailParams struct {
+	Host       string
+	Email      string
+	Name       string
+	ProfileUrl string
+}
+
+func (q *Queries) UpsertContributorByEmail(ctx context.Context, arg UpsertContributorByEmailParams) (int32, error) {
+	row := q.db.QueryRowContext(ctx, upsertContributorByEmail,
+		arg.Host,
+		arg.Email,
+		arg.Name,
+		arg.ProfileUrl,
+	)
+	var id int32
+	err := row.Scan(&id)
+	return id, err
+}
+
+const upsertContributorByLogin = `-- name: UpsertContributorByLogin :one
+INSERT INTO contributors (host, login, email, name, profile_url)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (host, login) WHERE login <> '' DO UPDATE SET email       = COALESCE(NULLIF(EXCLUDED.email, ''), contributors.email),
+                                                          name        = COALESCE(NULLIF(EXCLUDED.name, ''), contributors.name),
+                                                          profile_url = EXCLUDED.profile_url,
+                                                          updated_at  = CURRENT_TIMESTAMP
+RETURNING id
+`
+
+type UpsertContributorByLoginParams struct {
+	Host       string
+	Login      string
+	Email      string
+	Name       string
+	ProfileUrl string
+}
+
+func (q *Queries) UpsertContributorByLogin(ctx context.Context, arg UpsertContributorByLoginParams) (int32, error) {
+	row := q.db.QueryRowContext(ctx, upsertContributorByLogin,
+		arg.Host,
+		arg.Login,
+		arg.Email,
+		arg.Name,
+		arg.ProfileUrl,
+	)
+	var id int32
+	err := row.Scan(&id)
+	return id, err
+}
+
+const upsertPullRequest = `-- name: UpsertPullRequest :one
+INSERT INTO pull_requests (repository_id, number, url, title, body, state, is_draft, head_ref_name, review_decision,
+                           ci_status, requested_reviewers, opened_at, merged_at, closed_at, last_activity_at, labels,
+                           milestone, assignees, files, base_ref_name, merge_commit)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21)
+ON CONFLICT (repository_id, number, merge_commit) DO UPDATE SET url                 = EXCLUDED.url,
+                                                                title               = EXCLUDED.title,
+                                                                body                = EXCLUDED.body,
+                                                                state               = EXCLUDED.state,
+                                                                is_draft            = EXCLUDED.is_draft,
+                                                                head_ref_name       = EXCLUDED.head_ref_name,
+                                                                review_decision     = EXCLUDED.review_decision,
+                                                                ci_status           = EXCLUDED.ci_status,
+                                                                requested_reviewers = EXCLUDED.requested_reviewers,
+                                                                opened_at           = EXCLUDED.opened_at,
+                                                                merged_at           = EXCLUDED.merged_at,
+                                                                closed_at           = EXCLUDED.closed_at,
+                                                                last_activity_at    = EXCLUDED.last_activity_at,
+                                                                labels              = EXCLUDED.labels,
+                                                                milestone           = EXCLUDED.milestone,
+                                                                assignees           = EXCLUDED.assignees,
+                                                                files               = EXCLUDED.files,
+                                                                base_ref_name       = EXCLUDED.base_ref_name,
+                                                                updated_at          = CURRENT_TIMESTAMP
+RETURNING id
+`
+
+type UpsertPullRequestParams struct {
+	RepositoryID       int32
+	Number             int32
+	Url                string
+	Title              string
+	Body               string
+	State              string
+	IsDraft            bool
+	HeadRefName        string
+	ReviewDecision     string
+	CiStatus           string
+	RequestedReviewers []string
+	OpenedAt           time.Time
+	MergedAt           sql.NullTime
+	ClosedAt           sql.NullTime
+	LastActivityAt     sql.NullTime
+	Labels             []string
+	Milestone          string
+	Assignees          []string
+	Files              []string
+	BaseRefName        string
+	MergeCommit        string
+}
+
+func (q *Queries) UpsertPullRequest(ctx context.Context, arg UpsertPullRequestParams) (int32, error) {
+	row := q.db.QueryRowContext(ctx, upsertPullRequest,
+		arg.RepositoryID,
+		arg.Number,
+		arg.Url,
+		arg.Title,
+		arg.Body,
+		arg.State,
+		arg.IsDraft,
+		arg.HeadRefName,
+		arg.ReviewDecision,
+		arg.CiStatus,
+		pq.Array(arg.RequestedReviewers),
+		arg.OpenedAt,
+		arg.MergedAt,
+		arg.ClosedAt,
+		arg.LastActivityAt,
+		pq.Array(arg.Labels),
+		arg.Milestone,
+		pq.Array(arg.Assignees),
+		pq.Array(arg.Files),
+		arg.BaseRefName,
+		arg.MergeCommit,
+	)
+	var id int32
+	err := row.Scan(&id)
+	return id, err
+}
+
+const upsertRepository = `-- name: UpsertRepository :one
+INSERT INTO repositories (host, owner, name)
+VALUES ($1, $2, $3)
+ON CONFLICT (host, owner, name) DO UPDATE SET updated_at = CURRENT_TIMESTAMP
+RETURNING id, owner, name, created_at, updated_at, host
+`
+
+type UpsertRepositoryParams struct {
+	Host  string
+	Owner string
+	Name  string
+}
+
+func (q *Queries) UpsertRepository(ctx context.Context, arg UpsertRepositoryParams) (Repository, error) {
+	row := q.db.QueryRowContext(ctx, upsertRepository, arg.Host, arg.Owner, arg.Name)
+	var i Repository
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Name,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Host,
+	)
+	return i, err
+}
+
+const upsertRepositorySync = `-- name: UpsertRepositorySync :exec
+INSERT INTO repository_syncs (repository_id, state, synced_at)
+VALUES ($1, $2, $3)
+ON CONFLICT (repository_id, state) DO UPDATE SET synced_at = EXCLUDED.synced_at
+`
+
+type UpsertRepositorySyncParams struct {
+	RepositoryID int32
+	State        string
+	SyncedAt     time.Time
+}
+
+func (q *Queries) UpsertRepositorySync(ctx context.Context, arg UpsertRepositorySyncParams) error {
+	_, err := q.db.ExecContext(ctx, upsertRepositorySync, arg.RepositoryID, arg.State, arg.SyncedAt)
+	return err
+}
@@ -3,3 +3,160 @@ SELECT *
 FROM users
 ORDER BY created_at DESC
 LIMIT $1 OFFSET $2;
+
+-- name: UpsertRepository :one
+INSERT INTO repositories (host, owner, name)
+VALUES ($1, $2, $3)
+ON CONFLICT (host, owner, name) DO UPDATE SET updated_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: GetRepository :one
+SELECT *
+FROM repositories
+WHERE host = $1
+  AND owner = $2
+  AND name = $3;
+
+-- name: ListRepositorySyncs :many
+SELECT *
+FROM repository_syncs
+WHERE repository_id = $1;
+
+-- name: UpsertRepositorySync :exec
+INSERT INTO repository_syncs (repository_id, state, synced_at)
+VALUES ($1, $2, $3)
+ON CONFLICT (repository_id, state) DO UPDATE SET synced_at = EXCLUDED.synced_at;
+
+-- name: DeleteStalePullRequests :exec
+DELETE
+FROM pull_requests
+WHERE repository_id = sqlc.arg(repository_id)
+  AND state = ANY (sqlc.arg(states)::text[])
+  AND NOT id = ANY (sqlc.arg(ids)::int[]);
+
+-- name: UpsertPullRequest :one
+INSERT INTO pull_requests (repository_id, number, url, title, body, state, is_draft, head_ref_name, review_decision,
+                           ci_status, requested_reviewers, opened_at, merged_at, closed_at, last_activity_at, labels,
+                           milestone, assignees, files, base_ref_name, merge_commit)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21)
+ON CONFLICT (repository_id, number, merge_commit) DO UPDATE SET url                 = EXCLUDED.url,
+                                                                title               = EXCLUDED.title,
+                                                                body                = EXCLUDED.body,
+                                                                state               = EXCLUDED.state,
+                                                                is_draft            = EXCLUDED.is_draft,
+                                                                head_ref_name       = EXCLUDED.head_ref_name,
+                                                                review_decision     = EXCLUDED.review_decision,
+                                                                ci_status           = EXCLUDED.ci_status,
+                                                                requested_reviewers = EXCLUDED.requested_reviewers,
+                                                                opened_at           = EXCLUDED.opened_at,
+                                                                merged_at           = EXCLUDED.merged_at,
+                                                                closed_at           = EXCLUDED.closed_at,
+                                                                last_activity_at    = EXCLUDED.last_activity_at,
+                                                                labels              = EXCLUDED.labels,
+                                                                milestone           = EXCLUDED.milestone,
+                                                                assignees           = EXCLUDED.assignees,
+                                                                files               = EXCLUDED.files,
+                                                                base_ref_name       = EXCLUDED.base_ref_name,
+                                                                updated_at          = CURRENT_TIMESTAMP
+RETURNING id;
+
+-- name: ListPullRequests :many
+SELECT *
+FROM pull_requests
+WHERE repository_id = sqlc.arg(repository_id)
+  AND state = ANY (sqlc.arg(states)::text[])
+ORDER BY opened_at DESC;
+
+-- name: UpsertContributorByLogin :one
+INSERT INTO contributors (host, login, email, name, profile_url)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (host, login) WHERE login <> '' DO UPDATE SET email       = COALESCE(NULLIF(EXCLUDED.email, ''), contributors.email),
+                                                          name        = COALESCE(NULLIF(EXCLUDED.name, ''), contributors.name),
+                                                          profile_url = EXCLUDED.profile_url,
+                                                          updated_at  = CURRENT_TIMESTAMP
+RETURNING id;
+
+-- name: UpsertContributorByEmail :one
+INSERT INTO contributors (host, login, email, name, profile_url)
+VALUES ($1, '', $2, $3, $4)
+ON CONFLICT (host, email) WHERE login = '' DO UPDATE SET name        = EXCLUDED.name,
+                                                         profile_url = EXCLUDED.profile_url,
+                                                         updated_at  = CURRENT_TIMESTAMP
+RETURNING id;
+
+-- name: DeletePullRequestParticipants :exec
+DELETE
+FROM pull_request_participants
+WHERE pull_request_id = $1;
+
+-- name: CreatePullRequestParticipant :exec
+INSERT INTO pull_request_participants (pull_request_id, contributor_id, role, position)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (pull_request_id, contributor_id) DO NOTHING;
+
+-- name: ListPullRequestParticipants :many
+SELECT pull_request_participants.pull_request_id,
+       pull_request_participants.role,
+       contributors.login,
+       contributors.email,
+       contributors.name,
+       contributors.profile_url
+FROM pull_request_participants
+         JOIN contributors ON contributors.id = pull_request_participants.contributor_id
+         JOIN pull_requests ON pull_requests.id = pull_request_participants.pull_request_id
+WHERE pull_requests.repository_id = $1
+ORDER BY pull_request_participants.pull_request_id, pull_request_participants.position;
+
+-- name: DeletePullRequestLinkedIssues :exec
+DELETE
+FROM pull_request_linked_issues
+WHERE pull_request_id = $1;
+
+-- name: CreatePullRequestLinkedIssue :exec
+INSERT INTO pull_request_linked_issues (pull_request_id, position, owner, repo, number, url, title, body, state,
+                                        author_login, opened_at, closed_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12);
+
+-- name: ListPullRequestLinkedIssues :many
+SELECT pull_request_linked_issues.*
+FROM pull_request_linked_issues
+         JOIN pull_requests ON pull_requests.id = pull_request_linked_issues.pull_request_id
+WHERE pull_requests.repository_id = $1
+ORDER BY pull_request_linked_issues.pull_request_id, pull_request_linked_issues.position;
+
+-- name: TrackRepository :one
+INSERT INTO tracked_repositories (repository_id, url)
+VALUES ($1, $2)
+ON CONFLICT (repository_id) DO UPDATE SET url        = EXCLUDED.url,
+                                          updated_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: UntrackRepository :execrows
+DELETE
+FROM tracked_repositories USING repositories
+WHERE tracked_repositories.repository_id = repositories.id
+  AND repositories.host = $1
+  AND repositories.owner = $2
+  AND repositories.name = $3;
+
+-- name: ListTrackedRepositories :many
+SELECT tracked_repositories.id,
+       repositories.host,
+       repositories.owner,
+       repositories.name,
+       tracked_repositories.url,
+       tracked_repositories.last_synced_at,
+       tracked_repositories.last_sync_status,
+       tracked_repositories.last_sync_error,
+       tracked_repositories.created_at
+FROM tracked_repositories
+         JOIN repositories ON repositories.id = tracked_repositories.repository_id
+ORDER BY tracked_repositories.created_at;
+
+-- name: UpdateTrackedRepositorySync :exec
+UPDATE tracked_repositories
+SET last_synced_at   = $2,
+    last_sync_status = $3,
+    last_sync_error  = $4,
+    updated_at       = CURRENT_TIMESTAMP
+WHERE id = $1;
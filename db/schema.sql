@@ -8,3 +8,104 @@ CREATE TABLE users
     created_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
     updated_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP NOT NULL
 );
+
+CREATE TABLE repositories
+(
+    id         SERIAL PRIMARY KEY,
+    owner      TEXT                                               NOT NULL,
+    name       TEXT                                               NOT NULL,
+    created_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    host       TEXT                     DEFAULT 'github.com'      NOT NULL,
+    CONSTRAINT repositories_host_owner_name_key UNIQUE (host, owner, name)
+);
+
+CREATE TABLE pull_requests
+(
+    id                  SERIAL PRIMARY KEY,
+    repository_id       INTEGER                                            NOT NULL REFERENCES repositories (id) ON DELETE CASCADE,
+    number              INTEGER                                            NOT NULL,
+    url                 TEXT                                               NOT NULL,
+    title               TEXT                                               NOT NULL,
+    body                TEXT                                               NOT NULL,
+    state               TEXT                                               NOT NULL,
+    is_draft            BOOLEAN                  DEFAULT FALSE             NOT NULL,
+    head_ref_name       TEXT                                               NOT NULL,
+    review_decision     TEXT                     DEFAULT ''                NOT NULL,
+    ci_status           TEXT                     DEFAULT ''                NOT NULL,
+    requested_reviewers TEXT[]                   DEFAULT '{}'              NOT NULL,
+    opened_at           timestamp with time zone                           NOT NULL,
+    merged_at           timestamp with time zone                           NULL,
+    closed_at           timestamp with time zone                           NULL,
+    last_activity_at    timestamp with time zone                           NULL,
+    created_at          timestamp with time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at          timestamp with time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    labels              TEXT[]                   DEFAULT '{}'              NOT NULL,
+    milestone           TEXT                     DEFAULT ''                NOT NULL,
+    assignees           TEXT[]                   DEFAULT '{}'              NOT NULL,
+    files               TEXT[]                   DEFAULT '{}'              NOT NULL,
+    base_ref_name       TEXT                     DEFAULT ''                NOT NULL,
+    merge_commit        TEXT                     DEFAULT ''                NOT NULL,
+    CONSTRAINT pull_requests_repository_id_number_merge_commit_key UNIQUE (repository_id, number, merge_commit)
+);
+
+CREATE TABLE contributors
+(
+    id          SERIAL PRIMARY KEY,
+    login       TEXT                                               NOT NULL,
+    email       TEXT                                               NOT NULL,
+    name        TEXT                     DEFAULT ''                NOT NULL,
+    profile_url TEXT                     DEFAULT ''                NOT NULL,
+    created_at  timestamp with time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at  timestamp with time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    host        TEXT                     DEFAULT 'github.com'      NOT NULL
+);
+
+CREATE UNIQUE INDEX contributors_host_login_key ON contributors (host, login) WHERE login <> '';
+CREATE UNIQUE INDEX contributors_host_email_key ON contributors (host, email) WHERE login = '';
+
+CREATE TABLE pull_request_participants
+(
+    pull_request_id INTEGER NOT NULL REFERENCES pull_requests (id) ON DELETE CASCADE,
+    contributor_id  INTEGER NOT NULL REFERENCES contributors (id) ON DELETE CASCADE,
+    role            TEXT    NOT NULL,
+    position        INTEGER NOT NULL,
+    PRIMARY KEY (pull_request_id, contributor_id)
+);
+
+CREATE TABLE tracked_repositories
+(
+    id               SERIAL PRIMARY KEY,
+    repository_id    INTEGER                                            NOT NULL UNIQUE REFERENCES repositories (id) ON DELETE CASCADE,
+    url              TEXT                                               NOT NULL,
+    last_synced_at   timestamp with time zone                           NULL,
+    last_sync_status TEXT                     DEFAULT 'PENDING'         NOT NULL,
+    last_sync_error  TEXT                     DEFAULT ''                NOT NULL,
+    created_at       timestamp with time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at       timestamp with time zone DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE TABLE pull_request_linked_issues
+(
+    pull_request_id INTEGER                  NOT NULL REFERENCES pull_requests (id) ON DELETE CASCADE,
+    position        INTEGER                  NOT NULL,
+    owner           TEXT                     NOT NULL,
+    repo            TEXT                     NOT NULL,
+    number          INTEGER                  NOT NULL,
+    url             TEXT                     NOT NULL,
+    title           TEXT                     NOT NULL,
+    body            TEXT                     NOT NULL,
+    state           TEXT                     NOT NULL,
+    author_login    TEXT DEFAULT ''          NOT NULL,
+    opened_at       timestamp with time zone NOT NULL,
+    closed_at       timestamp with time zone NULL,
+    PRIMARY KEY (pull_request_id, position)
+);
+
+CREATE TABLE repository_syncs
+(
+    repository_id INTEGER                  NOT NULL REFERENCES repositories (id) ON DELETE CASCADE,
+    state         TEXT                     NOT NULL,
+    synced_at     timestamp with time zone NOT NULL,
+    PRIMARY KEY (repository_id, state)
+);
@@ -6,6 +6,8 @@ import (
 	"github.com/chris-ramon/golang-scaffolding/domain/auth/mappers"
 	"github.com/chris-ramon/golang-scaffolding/domain/gql/types"
 	"github.com/chris-ramon/golang-scaffolding/domain/gql/util"
+	"github.com/chris-ramon/golang-scaffolding/domain/metrics"
+	metricsMappers "github.com/chris-ramon/golang-scaffolding/domain/metrics/mappers"
 	solutionsMappers "github.com/chris-ramon/golang-scaffolding/domain/solutions/mappers"
 	usersMappers "github.com/chris-ramon/golang-scaffolding/domain/users/mappers"
 	"github.com/chris-ramon/golang-scaffolding/pkg/ctxutil"
@@ -119,3 +121,104 @@ var SolutionsField = &graphql.Field{
 		return solutionsAPI, nil
 	},
 }
+
+var GitHubRateLimitField = &graphql.Field{
+	Name:        "GitHubRateLimit",
+	Type:        types.RateLimitType,
+	Description: "The remaining GitHub API rate limit budget.",
+	Args: graphql.FieldConfigArgument{
+		"host": &graphql.ArgumentConfig{
+			Type:        graphql.String,
+			Description: "The GitHub host of the budget, github.com when omitted.",
+		},
+	},
+	Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+		srvs, err := util.ServicesFromResolveParams(p)
+		if err != nil {
+			return nil, err
+		}
+
+		host, _ := p.Args["host"].(string)
+
+		rateLimit, err := srvs.MetricsService.GitHubRateLimit(p.Context, host)
+		if err != nil {
+			return nil, err
+		}
+
+		return metricsMappers.RateLimitFromTypeToAPI(rateLimit), nil
+	},
+}
+
+var TrackedRepositoriesField = &graphql.Field{
+	Name:        "TrackedRepositories",
+	Type:        graphql.NewList(types.TrackedRepositoryType),
+	Description: "The repositories synced in the background with the outcome of their last sync.",
+	Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+		srvs, err := util.ServicesFromResolveParams(p)
+		if err != nil {
+			return nil, err
+		}
+
+		trackedRepositories, err := srvs.MetricsService.TrackedRepositories(p.Context)
+		if err != nil {
+			return nil, err
+		}
+
+		return metricsMappers.TrackedRepositoriesFromTypeToAPI(trackedRepositories), nil
+	},
+}
+
+var TrackRepositoryField = &graphql.Field{
+	Type:        types.TrackedRepositoryType,
+	Description: "Tracks a repository so it is synced in the background.",
+	Args: graphql.FieldConfigArgument{
+		"url": &graphql.ArgumentConfig{
+			Type: graphql.NewNonNull(graphql.String),
+		},
+	},
+	Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+		srvs, err := util.ServicesFromResolveParams(p)
+		if err != nil {
+			return nil, err
+		}
+
+		url, err := util.FieldFromArgs[string](p.Args, "url")
+		if err != nil {
+			return nil, err
+		}
+
+		trackedRepository, err := srvs.MetricsService.TrackRepository(p.Context, metrics.TrackRepositoryParams{
+			RepositoryURL: url,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return metricsMappers.TrackedRepositoryFromTypeToAPI(trackedRepository), nil
+	},
+}
+
+var UntrackRepositoryField = &graphql.Field{
+	Type:        graphql.Boolean,
+	Description: "Stops syncing a repository in the background, false when it was not tracked.",
+	Args: graphql.FieldConfigArgument{
+		"url": &graphql.ArgumentConfig{
+			Type: graphql.NewNonNull(graphql.String),
+		},
+	},
+	Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+		srvs, err := util.ServicesFromResolveParams(p)
+		if err != nil {
+			return nil, err
+		}
+
+		url, err := util.FieldFromArgs[string](p.Args, "url")
+		if err != nil {
+			return nil, err
+		}
+
+		return srvs.MetricsService.UntrackRepository(p.Context, metrics.UntrackRepositoryParams{
+			RepositoryURL: url,
+		})
+	},
+}
@@ -9,16 +9,20 @@ import (
 var Query = graphql.NewObject(graphql.ObjectConfig{
 	Name: "Query",
 	Fields: graphql.Fields{
-		"ping":        fields.PingField,
-		"currentUser": fields.CurrentUserField,
-		"users":       fields.UsersField,
-		"solutions":   fields.SolutionsField,
+		"ping":                fields.PingField,
+		"currentUser":         fields.CurrentUserField,
+		"users":               fields.UsersField,
+		"solutions":           fields.SolutionsField,
+		"githubRateLimit":     fields.GitHubRateLimitField,
+		"trackedRepositories": fields.TrackedRepositoriesField,
 	},
 })
 
 var Mutation = graphql.NewObject(graphql.ObjectConfig{
 	Name: "Mutation",
 	Fields: graphql.Fields{
-		"authUser": fields.AuthUserField,
+		"authUser":          fields.AuthUserField,
+		"trackRepository":   fields.TrackRepositoryField,
+		"untrackRepository": fields.UntrackRepositoryField,
 	},
 })
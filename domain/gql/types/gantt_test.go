@@ -2,12 +2,12 @@ package types
 
 import (
 	"context"
-	"net/http"
 	"testing"
 
 	"github.com/graphql-go/graphql"
 
 	cachePkg "github.com/chris-ramon/golang-scaffolding/cache"
+	"github.com/chris-ramon/golang-scaffolding/config"
 	"github.com/chris-ramon/golang-scaffolding/domain/internal/services"
 	"github.com/chris-ramon/golang-scaffolding/domain/metrics"
 )
@@ -178,8 +178,7 @@ func TestGitHubTypePullRequests(t *testing.T) {
 func TestGanttResolver(t *testing.T) {
 	// Create mock services
 	cache := cachePkg.New()
-	httpClient := &http.Client{}
-	metricsService, err := metrics.NewService(cache, httpClient)
+	metricsService, err := metrics.NewService(cache, nil, &config.Config{GitHubConfig: &config.GitHubConfig{}})
 	if err != nil {
 		t.Fatalf("Failed to create metrics service: %v", err)
 	}
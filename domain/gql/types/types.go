@@ -1,15 +1,18 @@
 package types
 
 import (
+	"crypto/sha256"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/graphql-go/graphql"
 
 	"github.com/chris-ramon/golang-scaffolding/domain/gql/util"
+	"github.com/chris-ramon/golang-scaffolding/domain/internal/services"
 	"github.com/chris-ramon/golang-scaffolding/domain/metrics"
 	"github.com/chris-ramon/golang-scaffolding/domain/metrics/api"
 	"github.com/chris-ramon/golang-scaffolding/domain/metrics/github"
@@ -79,11 +82,30 @@ var InformationType = graphql.NewObject(graphql.ObjectConfig{
 				"url": &graphql.ArgumentConfig{
 					Type: graphql.String,
 				},
+				"repositories": &graphql.ArgumentConfig{
+					Type:        graphql.NewList(graphql.NewNonNull(graphql.String)),
+					Description: "The URLs of the repositories whose pull requests are aggregated, along with the url one.",
+				},
+				"organization": &graphql.ArgumentConfig{
+					Type:        graphql.String,
+					Description: "The URL of the organization, or user, whose repositories pull requests are aggregated, e.g. `https://github.com/graphql-go`.",
+				},
 			},
 			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
-				// The URL parameter will be passed down to the metrics resolver
+				var repositories []string
+				if p.Args["repositories"] != nil {
+					r, err := util.FieldsFromArgs[string](p.Args, "repositories")
+					if err != nil {
+						return nil, err
+					}
+					repositories = r
+				}
+
+				// The URL parameters will be passed down to the metrics resolver
 				return map[string]interface{}{
-					"url": p.Args["url"],
+					"url":          p.Args["url"],
+					"repositories": repositories,
+					"organization": p.Args["organization"],
 				}, nil
 			},
 		},
@@ -124,7 +146,27 @@ var GitHubType = graphql.NewObject(graphql.ObjectConfig{
 			Args: graphql.FieldConfigArgument{
 				"limit": &graphql.ArgumentConfig{
 					Type:         graphql.Int,
-					DefaultValue: 25,
+					DefaultValue: metrics.DefaultGanttLimit,
+				},
+				"states": &graphql.ArgumentConfig{
+					Type:        graphql.NewList(graphql.NewNonNull(PullRequestStateType)),
+					Description: "The pull request states to include, defaults to merged pull requests.",
+				},
+				"labels": &graphql.ArgumentConfig{
+					Type:        graphql.NewList(graphql.NewNonNull(graphql.String)),
+					Description: "The labels the pull requests must all have, case-insensitive.",
+				},
+				"milestone": &graphql.ArgumentConfig{
+					Type:        graphql.String,
+					Description: "The milestone title of the pull requests, case-insensitive.",
+				},
+				"paths": &graphql.ArgumentConfig{
+					Type:        graphql.NewList(graphql.NewNonNull(graphql.String)),
+					Description: "The glob patterns of the files the pull requests must change one of, e.g. `services/billing/**`.",
+				},
+				"component": &graphql.ArgumentConfig{
+					Type:        graphql.String,
+					Description: "The name of the configured component whose paths the pull requests must change one of.",
 				},
 			},
 			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
@@ -133,23 +175,53 @@ var GitHubType = graphql.NewObject(graphql.ObjectConfig{
 					return nil, err
 				}
 
-				// Get the repository URL from the parent
+				// Get the repository URLs from the parent
 				parent, ok := p.Source.(map[string]interface{})
 				if !ok {
 					return nil, fmt.Errorf("invalid parent source")
 				}
 
-				repoURL, exists := parent["url"]
-				if !exists || repoURL == nil {
+				repoURL, _ := parent["url"].(string)
+				repositoryURLs, organizationURL, multipleRepositories := repositoriesFromSource(parent)
+				if repoURL == "" && !multipleRepositories {
 					return nil, fmt.Errorf("repository URL is required")
 				}
 
 				// Get the limit parameter (GraphQL guarantees default value is applied)
 				limit := p.Args["limit"].(int)
 
+				states, err := pullRequestStatesFromArgs(p.Args)
+				if err != nil {
+					return nil, err
+				}
+
+				labels, err := pullRequestLabelsFromArgs(p.Args)
+				if err != nil {
+					return nil, err
+				}
+
+				milestone, _ := p.Args["milestone"].(string)
+
+				paths, err := pullRequestPathsFromArgs(p.Args)
+				if err != nil {
+					return nil, err
+				}
+
+				component, _ := p.Args["component"].(string)
+
 				params := metrics.GeneratePullRequestsGanttParams{
-					RepositoryURL: repoURL.(string),
+					RepositoryURL: repoURL,
 					Limit:         limit,
+					States:        states,
+					Labels:        labels,
+					Milestone:     milestone,
+					Paths:         paths,
+					Component:     component,
+				}
+				if multipleRepositories {
+					params.RepositoryURL = ""
+					params.RepositoryURLs = repositoryURLs
+					params.OrganizationURL = organizationURL
 				}
 
 				results, err := srvs.MetricsService.GeneratePullRequestsGantt(p.Context, params)
@@ -191,6 +263,26 @@ var MetricsType = graphql.NewObject(graphql.ObjectConfig{
 				"urls": &graphql.ArgumentConfig{
 					Type: graphql.NewList(graphql.String),
 				},
+				"states": &graphql.ArgumentConfig{
+					Type:        graphql.NewList(graphql.NewNonNull(PullRequestStateType)),
+					Description: "The pull request states to include, defaults to merged pull requests.",
+				},
+				"labels": &graphql.ArgumentConfig{
+					Type:        graphql.NewList(graphql.NewNonNull(graphql.String)),
+					Description: "The labels the pull requests must all have, case-insensitive.",
+				},
+				"milestone": &graphql.ArgumentConfig{
+					Type:        graphql.String,
+					Description: "The milestone title of the pull requests, case-insensitive.",
+				},
+				"paths": &graphql.ArgumentConfig{
+					Type:        graphql.NewList(graphql.NewNonNull(graphql.String)),
+					Description: "The glob patterns of the files the pull requests must change one of, e.g. `services/billing/**`.",
+				},
+				"component": &graphql.ArgumentConfig{
+					Type:        graphql.String,
+					Description: "The name of the configured component whose paths the pull requests must change one of.",
+				},
 			},
 			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
 				srvs, err := util.ServicesFromResolveParams(p)
@@ -198,12 +290,55 @@ var MetricsType = graphql.NewObject(graphql.ObjectConfig{
 					return nil, err
 				}
 
-				// Check if we have a repository URL from the parent github field
+				// Check if we have repository URLs from the parent github field
 				if parent, ok := p.Source.(map[string]interface{}); ok {
-					if repoURL, exists := parent["url"]; exists && repoURL != nil {
+					repositoryURLs, organizationURL, multipleRepositories := repositoriesFromSource(parent)
+					if repoURL, exists := parent["url"]; multipleRepositories || (exists && repoURL != nil) {
+						states, err := pullRequestStatesFromArgs(p.Args)
+						if err != nil {
+							return nil, err
+						}
+
+						labels, err := pullRequestLabelsFromArgs(p.Args)
+						if err != nil {
+							return nil, err
+						}
+
+						milestone, _ := p.Args["milestone"].(string)
+
+						paths, err := pullRequestPathsFromArgs(p.Args)
+						if err != nil {
+							return nil, err
+						}
+
+						component, _ := p.Args["component"].(string)
+
+						// Use FindRepositoriesPullRequests for several repositories
+						if multipleRepositories {
+							result, err := srvs.MetricsService.FindRepositoriesPullRequests(p.Context, metrics.FindRepositoriesPullRequestsParams{
+								RepositoryURLs:  repositoryURLs,
+								OrganizationURL: organizationURL,
+								States:          states,
+								Labels:          labels,
+								Milestone:       milestone,
+								Paths:           paths,
+								Component:       component,
+							})
+							if err != nil {
+								return nil, err
+							}
+
+							return mappers.PullRequestsFromTypeToAPI(result.PullRequests), nil
+						}
+
 						// Use FindAllPullRequests for repository URL
 						params := metrics.FindAllPullRequestsParams{
 							RepositoryURL: repoURL.(string),
+							States:        states,
+							Labels:        labels,
+							Milestone:     milestone,
+							Paths:         paths,
+							Component:     component,
 						}
 
 						findAllPullRequestsResult, err := srvs.MetricsService.FindAllPullRequests(p.Context, params)
@@ -222,20 +357,231 @@ var MetricsType = graphql.NewObject(graphql.ObjectConfig{
 					return nil, err
 				}
 
-				prs, err := github.PullRequestsFromURLs(urls)
+				return pullRequestsFromURLs(p, srvs.MetricsService, urls)
+			},
+		},
+		"pullRequestsInfo": &graphql.Field{
+			Description: "The information of the repository pull requests fetch.",
+			Type:        PullRequestsInfoType,
+			Args: graphql.FieldConfigArgument{
+				"states": &graphql.ArgumentConfig{
+					Type:        graphql.NewList(graphql.NewNonNull(PullRequestStateType)),
+					Description: "The pull request states to include, defaults to merged pull requests.",
+				},
+			},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				srvs, err := util.ServicesFromResolveParams(p)
 				if err != nil {
 					return nil, err
 				}
-				params := mappers.PullRequestsFromTypeToFindParam(prs)
 
-				findPullRequestsResult, err := srvs.MetricsService.FindPullRequests(p.Context, params)
+				parent, ok := p.Source.(map[string]interface{})
+				if !ok {
+					return nil, fmt.Errorf("invalid parent source")
+				}
+
+				states, err := pullRequestStatesFromArgs(p.Args)
 				if err != nil {
 					return nil, err
 				}
 
-				pullRequests := mappers.PullRequestsFromTypeToAPI(findPullRequestsResult.PullRequests)
+				if repositoryURLs, organizationURL, ok := repositoriesFromSource(parent); ok {
+					result, err := srvs.MetricsService.FindRepositoriesPullRequests(p.Context, metrics.FindRepositoriesPullRequestsParams{
+						RepositoryURLs:  repositoryURLs,
+						OrganizationURL: organizationURL,
+						States:          states,
+					})
+					if err != nil {
+						return nil, err
+					}
+
+					return map[string]interface{}{
+						"totalCount": len(result.PullRequests),
+						"truncated":  result.Truncated,
+					}, nil
+				}
+
+				repoURL, exists := parent["url"]
+				if !exists || repoURL == nil {
+					return nil, fmt.Errorf("repository URL is required")
+				}
 
-				return pullRequests, nil
+				params := metrics.FindAllPullRequestsParams{
+					RepositoryURL: repoURL.(string),
+					States:        states,
+				}
+
+				result, err := srvs.MetricsService.FindAllPullRequests(p.Context, params)
+				if err != nil {
+					return nil, err
+				}
+
+				return map[string]interface{}{
+					"totalCount": len(result.PullRequests),
+					"truncated":  result.Truncated,
+				}, nil
+			},
+		},
+		"statistics": &graphql.Field{
+			Description: "The pull requests statistics of each repository and of every repository combined.",
+			Type:        StatisticsType,
+			Args: graphql.FieldConfigArgument{
+				"states": &graphql.ArgumentConfig{
+					Type:        graphql.NewList(graphql.NewNonNull(PullRequestStateType)),
+					Description: "The pull request states to include, defaults to merged pull requests.",
+				},
+			},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				srvs, err := util.ServicesFromResolveParams(p)
+				if err != nil {
+					return nil, err
+				}
+
+				parent, ok := p.Source.(map[string]interface{})
+				if !ok {
+					return nil, fmt.Errorf("invalid parent source")
+				}
+
+				repositoryURLs, organizationURL, _ := repositoriesFromSource(parent)
+				if len(repositoryURLs) == 0 && organizationURL == "" {
+					return nil, fmt.Errorf("repository URL is required")
+				}
+
+				states, err := pullRequestStatesFromArgs(p.Args)
+				if err != nil {
+					return nil, err
+				}
+
+				params := metrics.FindRepositoriesPullRequestsParams{
+					RepositoryURLs:  repositoryURLs,
+					OrganizationURL: organizationURL,
+					States:          states,
+				}
+
+				statistics, err := srvs.MetricsService.PullRequestsStatistics(p.Context, params)
+				if err != nil {
+					return nil, err
+				}
+
+				return mappers.StatisticsFromTypeToAPI(statistics), nil
+			},
+		},
+		"issues": &graphql.Field{
+			Description: "The list of issues of the repository, most recently created first.",
+			Type:        graphql.NewList(IssueType),
+			Args: graphql.FieldConfigArgument{
+				"states": &graphql.ArgumentConfig{
+					Type:        graphql.NewList(graphql.NewNonNull(IssueStateType)),
+					Description: "The issue states to include, defaults to every state.",
+				},
+			},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				srvs, err := util.ServicesFromResolveParams(p)
+				if err != nil {
+					return nil, err
+				}
+
+				parent, ok := p.Source.(map[string]interface{})
+				if !ok {
+					return nil, fmt.Errorf("invalid parent source")
+				}
+
+				repoURL, exists := parent["url"]
+				if !exists || repoURL == nil {
+					return nil, fmt.Errorf("repository URL is required")
+				}
+
+				states, err := issueStatesFromArgs(p.Args)
+				if err != nil {
+					return nil, err
+				}
+
+				params := metrics.FindAllIssuesParams{
+					RepositoryURL: repoURL.(string),
+					States:        states,
+				}
+
+				result, err := srvs.MetricsService.FindAllIssues(p.Context, params)
+				if err != nil {
+					return nil, err
+				}
+
+				return mappers.IssuesFromTypeToAPI(result.Issues), nil
+			},
+		},
+		"releases": &graphql.Field{
+			Description: "The list of published releases of the repository, most recently created first.",
+			Type:        graphql.NewList(ReleaseType),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				srvs, err := util.ServicesFromResolveParams(p)
+				if err != nil {
+					return nil, err
+				}
+
+				parent, ok := p.Source.(map[string]interface{})
+				if !ok {
+					return nil, fmt.Errorf("invalid parent source")
+				}
+
+				repoURL, exists := parent["url"]
+				if !exists || repoURL == nil {
+					return nil, fmt.Errorf("repository URL is required")
+				}
+
+				params := metrics.FindAllReleasesParams{
+					RepositoryURL: repoURL.(string),
+				}
+
+				result, err := srvs.MetricsService.FindAllReleases(p.Context, params)
+				if err != nil {
+					return nil, err
+				}
+
+				return mappers.ReleasesFromTypeToAPI(result.Releases), nil
+			},
+		},
+		"openWork": &graphql.Field{
+			Description: "The open pull requests with their age, flagging the ones older than a percentile of the historical cycle time.",
+			Type:        OpenWorkType,
+			Args: graphql.FieldConfigArgument{
+				"percentile": &graphql.ArgumentConfig{
+					Type:         graphql.Float,
+					DefaultValue: float64(metrics.DefaultOpenWorkPercentile),
+					Description:  "The historical cycle time percentile past which open pull requests are flagged.",
+				},
+			},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				srvs, err := util.ServicesFromResolveParams(p)
+				if err != nil {
+					return nil, err
+				}
+
+				parent, ok := p.Source.(map[string]interface{})
+				if !ok {
+					return nil, fmt.Errorf("invalid parent source")
+				}
+
+				repoURL, exists := parent["url"]
+				if !exists || repoURL == nil {
+					return nil, fmt.Errorf("repository URL is required")
+				}
+
+				percentile, err := util.FieldFromArgs[float64](p.Args, "percentile")
+				if err != nil {
+					return nil, err
+				}
+
+				params := metrics.OpenWorkParams{
+					RepositoryURL: repoURL.(string),
+					Percentile:    percentile,
+				}
+
+				openWork, err := srvs.MetricsService.OpenWork(p.Context, params)
+				if err != nil {
+					return nil, err
+				}
+
+				return mappers.OpenWorkFromTypeToAPI(openWork), nil
 			},
 		},
 	},
@@ -307,6 +653,31 @@ var PullRequestType = graphql.NewObject(graphql.ObjectConfig{
 				return nil, nil
 			},
 		},
+		"closedAt": &graphql.Field{
+			Description: "The pull request closed at date in Day.Month.Year format",
+			Type:        graphql.String,
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				switch v := p.Source.(type) {
+				case api.PullRequest:
+					if v.ClosedAt != nil {
+						return v.ClosedAt.Format("2.1.2006"), nil
+					}
+				case *api.PullRequest:
+					if v.ClosedAt != nil {
+						return v.ClosedAt.Format("2.1.2006"), nil
+					}
+				}
+				return nil, nil
+			},
+		},
+		"state": &graphql.Field{
+			Description: "The pull request state",
+			Type:        PullRequestStateType,
+		},
+		"isDraft": &graphql.Field{
+			Description: "Whether the pull request is a draft",
+			Type:        graphql.Boolean,
+		},
 		"url": &graphql.Field{
 			Description: "The pull request url",
 			Type:        graphql.String,
@@ -331,6 +702,572 @@ var PullRequestType = graphql.NewObject(graphql.ObjectConfig{
 			Description: "The formatted contributors of the pull request.",
 			Type:        graphql.String,
 		},
+		"repository": &graphql.Field{
+			Description: "The repository of the pull request, e.g. `graphql-go/graphql`.",
+			Type:        graphql.String,
+		},
+		"headRefName": &graphql.Field{
+			Description: "The pull request head reference name.",
+			Type:        graphql.String,
+		},
+		"baseRefName": &graphql.Field{
+			Description: "The name of the reference the pull request is merged into.",
+			Type:        graphql.String,
+		},
+		"stack": &graphql.Field{
+			Description: "The stack of dependent pull requests the pull request belongs to, null when it is not stacked.",
+			Type:        PullRequestStackType,
+		},
+		"labels": &graphql.Field{
+			Description: "The names of the pull request labels.",
+			Type:        graphql.NewList(graphql.String),
+		},
+		"milestone": &graphql.Field{
+			Description: "The title of the pull request milestone, empty when there is none.",
+			Type:        graphql.String,
+		},
+		"assignees": &graphql.Field{
+			Description: "The logins of the users assigned to the pull request.",
+			Type:        graphql.NewList(graphql.String),
+		},
+		"files": &graphql.Field{
+			Description: "The paths of the files changed by the pull request.",
+			Type:        graphql.NewList(graphql.String),
+		},
+		"linkedIssues": &graphql.Field{
+			Description: "The issues the pull request closes when merged.",
+			Type:        graphql.NewList(IssueType),
+		},
+		"error": &graphql.Field{
+			Description: "The error of the pull request when it could not be fetched.",
+			Type:        PullRequestErrorType,
+		},
+	},
+})
+
+var PullRequestStackType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "PullRequestStackType",
+	Fields: graphql.Fields{
+		"root": &graphql.Field{
+			Description: "The number of the bottom pull request of the stack.",
+			Type:        graphql.Int,
+		},
+		"position": &graphql.Field{
+			Description: "The position of the pull request in the stack, 1 for the root.",
+			Type:        graphql.Int,
+		},
+		"parent": &graphql.Field{
+			Description: "The number of the pull request the pull request is based on, null for the root.",
+			Type:        graphql.Int,
+		},
+		"size": &graphql.Field{
+			Description: "The number of pull requests of the stack.",
+			Type:        graphql.Int,
+		},
+	},
+})
+
+var PullRequestErrorType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "PullRequestErrorType",
+	Description: "The error of a pull request that could not be fetched.",
+	Fields: graphql.Fields{
+		"code": &graphql.Field{
+			Description: "The machine-readable code of the error.",
+			Type:        PullRequestErrorCodeType,
+		},
+		"message": &graphql.Field{
+			Description: "The human-readable message of the error.",
+			Type:        graphql.String,
+		},
+	},
+})
+
+var PullRequestErrorCodeType = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "PullRequestErrorCodeType",
+	Description: "The machine-readable code of a pull request error.",
+	Values: graphql.EnumValueConfigMap{
+		"NOT_FOUND": &graphql.EnumValueConfig{
+			Value:       string(metricTypes.NotFoundPullRequestErrorCode),
+			Description: "The pull request does not exist on GitHub.",
+		},
+		"NOT_MERGED": &graphql.EnumValueConfig{
+			Value:       string(metricTypes.NotMergedPullRequestErrorCode),
+			Description: "The pull request is not merged.",
+		},
+		"RATE_LIMITED": &graphql.EnumValueConfig{
+			Value:       string(metricTypes.RateLimitedPullRequestErrorCode),
+			Description: "The pull request was not fetched because of the GitHub rate limit.",
+		},
+		"INVALID_URL": &graphql.EnumValueConfig{
+			Value:       string(metricTypes.InvalidURLPullRequestErrorCode),
+			Description: "The pull request URL could not be parsed.",
+		},
+		"INTERNAL": &graphql.EnumValueConfig{
+			Value:       string(metricTypes.InternalPullRequestErrorCode),
+			Description: "The pull request could not be fetched for another reason.",
+		},
+	},
+})
+
+var PullRequestStateType = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "PullRequestStateType",
+	Description: "The state of a pull request.",
+	Values: graphql.EnumValueConfigMap{
+		"OPEN": &graphql.EnumValueConfig{
+			Value:       string(metricTypes.OpenPullRequestState),
+			Description: "A pull request still in progress.",
+		},
+		"CLOSED": &graphql.EnumValueConfig{
+			Value:       string(metricTypes.ClosedPullRequestState),
+			Description: "A pull request closed without being merged.",
+		},
+		"MERGED": &graphql.EnumValueConfig{
+			Value:       string(metricTypes.MergedPullRequestState),
+			Description: "A merged pull request.",
+		},
+	},
+})
+
+var IssueType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "IssueType",
+	Fields: graphql.Fields{
+		"number": &graphql.Field{
+			Description: "The issue number.",
+			Type:        graphql.Int,
+		},
+		"owner": &graphql.Field{
+			Description: "The owner of the issue repository.",
+			Type:        graphql.String,
+		},
+		"repo": &graphql.Field{
+			Description: "The repository name of the issue.",
+			Type:        graphql.String,
+		},
+		"url": &graphql.Field{
+			Description: "The issue url.",
+			Type:        graphql.String,
+		},
+		"title": &graphql.Field{
+			Description: "The issue title.",
+			Type:        graphql.String,
+		},
+		"body": &graphql.Field{
+			Description: "The issue body.",
+			Type:        graphql.String,
+		},
+		"state": &graphql.Field{
+			Description: "The issue state.",
+			Type:        IssueStateType,
+		},
+		"author": &graphql.Field{
+			Description: "The login of the issue author.",
+			Type:        graphql.String,
+		},
+		"createdAt": &graphql.Field{
+			Description: "The issue created at time in RFC3339 format.",
+			Type:        graphql.String,
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				issue, ok := p.Source.(api.Issue)
+				if !ok || issue.CreatedAt == nil {
+					return nil, nil
+				}
+				return issue.CreatedAt.Format(time.RFC3339), nil
+			},
+		},
+		"closedAt": &graphql.Field{
+			Description: "The issue closed at time in RFC3339 format, null when it is open.",
+			Type:        graphql.String,
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				issue, ok := p.Source.(api.Issue)
+				if !ok || issue.ClosedAt == nil {
+					return nil, nil
+				}
+				return issue.ClosedAt.Format(time.RFC3339), nil
+			},
+		},
+	},
+})
+
+var ReleaseType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "ReleaseType",
+	Fields: graphql.Fields{
+		"tagName": &graphql.Field{
+			Description: "The name of the git tag of the release.",
+			Type:        graphql.String,
+		},
+		"owner": &graphql.Field{
+			Description: "The owner of the release repository.",
+			Type:        graphql.String,
+		},
+		"repo": &graphql.Field{
+			Description: "The repository name of the release.",
+			Type:        graphql.String,
+		},
+		"name": &graphql.Field{
+			Description: "The release name.",
+			Type:        graphql.String,
+		},
+		"description": &graphql.Field{
+			Description: "The release description.",
+			Type:        graphql.String,
+		},
+		"url": &graphql.Field{
+			Description: "The release url.",
+			Type:        graphql.String,
+		},
+		"isPrerelease": &graphql.Field{
+			Description: "Whether the release is a pre-release.",
+			Type:        graphql.Boolean,
+		},
+		"author": &graphql.Field{
+			Description: "The login of the release author.",
+			Type:        graphql.String,
+		},
+		"createdAt": &graphql.Field{
+			Description: "The release created at time in RFC3339 format.",
+			Type:        graphql.String,
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				release, ok := p.Source.(api.Release)
+				if !ok || release.CreatedAt == nil {
+					return nil, nil
+				}
+				return release.CreatedAt.Format(time.RFC3339), nil
+			},
+		},
+		"publishedAt": &graphql.Field{
+			Description: "The release published at time in RFC3339 format.",
+			Type:        graphql.String,
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				release, ok := p.Source.(api.Release)
+				if !ok || release.PublishedAt == nil {
+					return nil, nil
+				}
+				return release.PublishedAt.Format(time.RFC3339), nil
+			},
+		},
+	},
+})
+
+var IssueStateType = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "IssueStateType",
+	Description: "The state of an issue.",
+	Values: graphql.EnumValueConfigMap{
+		"OPEN": &graphql.EnumValueConfig{
+			Value:       string(metricTypes.OpenIssueState),
+			Description: "An issue still in progress.",
+		},
+		"CLOSED": &graphql.EnumValueConfig{
+			Value:       string(metricTypes.ClosedIssueState),
+			Description: "A closed issue.",
+		},
+	},
+})
+
+// issueStatesFromArgs returns the issue states from the given arguments, nil when not given.
+func issueStatesFromArgs(args map[string]any) ([]metricTypes.IssueState, error) {
+	if args["states"] == nil {
+		return nil, nil
+	}
+
+	values, err := util.FieldsFromArgs[string](args, "states")
+	if err != nil {
+		return nil, err
+	}
+
+	states := []metricTypes.IssueState{}
+	for _, v := range values {
+		states = append(states, metricTypes.IssueState(v))
+	}
+
+	return states, nil
+}
+
+// pullRequestStatesFromArgs returns the pull request states from the given arguments, nil when not given.
+func pullRequestStatesFromArgs(args map[string]any) ([]metricTypes.PullRequestState, error) {
+	if args["states"] == nil {
+		return nil, nil
+	}
+
+	values, err := util.FieldsFromArgs[string](args, "states")
+	if err != nil {
+		return nil, err
+	}
+
+	states := []metricTypes.PullRequestState{}
+	for _, v := range values {
+		states = append(states, metricTypes.PullRequestState(v))
+	}
+
+	return states, nil
+}
+
+// pullRequestLabelsFromArgs returns the pull request labels from the given arguments, nil when not given.
+func pullRequestLabelsFromArgs(args map[string]any) ([]string, error) {
+	if args["labels"] == nil {
+		return nil, nil
+	}
+
+	return util.FieldsFromArgs[string](args, "labels")
+}
+
+// pullRequestPathsFromArgs returns the pull request path patterns from the given arguments, nil when not given.
+func pullRequestPathsFromArgs(args map[string]any) ([]string, error) {
+	if args["paths"] == nil {
+		return nil, nil
+	}
+
+	return util.FieldsFromArgs[string](args, "paths")
+}
+
+// repositoriesFromSource returns the repository URLs, led by the url one, and the organization URL of the given
+// `github` field source, along with whether they are of several repositories.
+func repositoriesFromSource(parent map[string]interface{}) ([]string, string, bool) {
+	repositoryURLs := []string{}
+	if repoURL, _ := parent["url"].(string); repoURL != "" {
+		repositoryURLs = append(repositoryURLs, repoURL)
+	}
+
+	repositories, _ := parent["repositories"].([]string)
+	repositoryURLs = append(repositoryURLs, repositories...)
+
+	organizationURL, _ := parent["organization"].(string)
+
+	return repositoryURLs, organizationURL, len(repositoryURLs) > 1 || organizationURL != ""
+}
+
+// exportFileName returns the name of the export file of the pull requests of the given paths and component,
+// suffixed by a hash of the filter when there is one so each filter has its own export.
+func exportFileName(paths []string, component string) string {
+	if len(paths) == 0 && component == "" {
+		return "data.txt"
+	}
+
+	hash := sha256.Sum256([]byte(strings.Join(append([]string{component}, paths...), "\x00")))
+	return fmt.Sprintf("data-%x.txt", hash[:4])
+}
+
+// pullRequestsFromURLs returns the pull requests of the given URLs in the same order. The pull requests that
+// could not be fetched carry their error, the field only fails when none was fetched, with the code of the
+// first error in the GraphQL error extensions.
+func pullRequestsFromURLs(p graphql.ResolveParams, metricsService services.MetricsService, urls []string) (interface{}, error) {
+	pullRequests := make([]*metricTypes.PullRequest, len(urls))
+
+	params := metricTypes.FindPullRequestsParams{}
+	indexes := []int{}
+
+	for i, url := range urls {
+		pr, err := github.PullRequestFromURL(url)
+		if err != nil {
+			pullRequests[i] = &metricTypes.PullRequest{
+				URL:   url,
+				Error: &metricTypes.PullRequestError{Code: metricTypes.InvalidURLPullRequestErrorCode, Message: err.Error()},
+			}
+			continue
+		}
+
+		params = append(params, mappers.PullRequestFromTypeToFindParam(*pr))
+		indexes = append(indexes, i)
+	}
+
+	if len(params) > 0 {
+		findPullRequestsResult, err := metricsService.FindPullRequests(p.Context, params)
+		if err != nil {
+			return nil, err
+		}
+
+		for j, i := range indexes {
+			pullRequests[i] = findPullRequestsResult.PullRequests[j]
+		}
+	}
+
+	if len(pullRequests) > 0 && !slices.ContainsFunc(pullRequests, func(pr *metricTypes.PullRequest) bool { return pr.Error == nil }) {
+		messages := []string{}
+		for _, pr := range pullRequests {
+			messages = append(messages, fmt.Sprintf("%s: %s", pr.URL, pr.Error.Message))
+		}
+
+		return nil, &api.PullRequestError{
+			Code:    string(pullRequests[0].Error.Code),
+			Message: strings.Join(messages, "; "),
+		}
+	}
+
+	return mappers.PullRequestsFromTypeToAPI(pullRequests), nil
+}
+
+var PullRequestsInfoType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "PullRequestsInfoType",
+	Fields: graphql.Fields{
+		"totalCount": &graphql.Field{
+			Description: "The number of fetched pull requests.",
+			Type:        graphql.Int,
+		},
+		"truncated": &graphql.Field{
+			Description: "Whether pull requests were left out by the maximum number of fetched pages.",
+			Type:        graphql.Boolean,
+		},
+	},
+})
+
+var StatisticsType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "StatisticsType",
+	Fields: graphql.Fields{
+		"combined": &graphql.Field{
+			Description: "The statistics of the pull requests of every repository.",
+			Type:        RepositoryStatisticsType,
+		},
+		"repositories": &graphql.Field{
+			Description: "The statistics of each repository.",
+			Type:        graphql.NewList(RepositoryStatisticsType),
+		},
+		"truncated": &graphql.Field{
+			Description: "Whether repositories or pull requests were left out by the maximum number of fetched pages.",
+			Type:        graphql.Boolean,
+		},
+	},
+})
+
+var RepositoryStatisticsType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "RepositoryStatisticsType",
+	Fields: graphql.Fields{
+		"repository": &graphql.Field{
+			Description: "The repository of the statistics, e.g. `graphql-go/graphql`, null for the combined statistics.",
+			Type:        graphql.String,
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				statistics, ok := p.Source.(api.RepositoryStatistics)
+				if !ok || statistics.Repository == "" {
+					return nil, nil
+				}
+				return statistics.Repository, nil
+			},
+		},
+		"url": &graphql.Field{
+			Description: "The URL of the repository of the statistics, null for the combined statistics.",
+			Type:        graphql.String,
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				statistics, ok := p.Source.(api.RepositoryStatistics)
+				if !ok || statistics.URL == "" {
+					return nil, nil
+				}
+				return statistics.URL, nil
+			},
+		},
+		"count": &graphql.Field{
+			Description: "The number of pull requests.",
+			Type:        graphql.Int,
+		},
+		"openCount": &graphql.Field{
+			Description: "The number of open pull requests.",
+			Type:        graphql.Int,
+		},
+		"mergedCount": &graphql.Field{
+			Description: "The number of merged pull requests.",
+			Type:        graphql.Int,
+		},
+		"closedCount": &graphql.Field{
+			Description: "The number of pull requests closed without being merged.",
+			Type:        graphql.Int,
+		},
+		"averageCycleTimeInDays": &graphql.Field{
+			Description: "The average time from creation to merge of the merged pull requests in days.",
+			Type:        graphql.Float,
+		},
+		"medianCycleTimeInDays": &graphql.Field{
+			Description: "The median time from creation to merge of the merged pull requests in days.",
+			Type:        graphql.Float,
+		},
+	},
+})
+
+var OpenWorkType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "OpenWorkType",
+	Fields: graphql.Fields{
+		"percentile": &graphql.Field{
+			Description: "The historical cycle time percentile used as threshold.",
+			Type:        graphql.Float,
+		},
+		"thresholdInDays": &graphql.Field{
+			Description: "The merged pull requests cycle time at the percentile in days.",
+			Type:        graphql.Float,
+		},
+		"items": &graphql.Field{
+			Description: "The open pull requests, oldest first.",
+			Type:        graphql.NewList(OpenWorkItemType),
+		},
+	},
+})
+
+var OpenWorkItemType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "OpenWorkItemType",
+	Fields: graphql.Fields{
+		"pullRequest": &graphql.Field{
+			Description: "The open pull request.",
+			Type:        PullRequestType,
+		},
+		"ageInDays": &graphql.Field{
+			Description: "The time elapsed since the pull request was created in days.",
+			Type:        graphql.Float,
+		},
+		"lastActivityAt": &graphql.Field{
+			Description: "The pull request last activity time in RFC3339 format.",
+			Type:        graphql.String,
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				item, ok := p.Source.(api.OpenWorkItem)
+				if !ok || item.LastActivityAt == nil {
+					return nil, nil
+				}
+				return item.LastActivityAt.Format(time.RFC3339), nil
+			},
+		},
+		"requestedReviewers": &graphql.Field{
+			Description: "The users and teams with pending review requests.",
+			Type:        graphql.NewList(graphql.String),
+		},
+		"reviewState": &graphql.Field{
+			Description: "The pull request review decision: APPROVED, CHANGES_REQUESTED or REVIEW_REQUIRED.",
+			Type:        graphql.String,
+		},
+		"ciStatus": &graphql.Field{
+			Description: "The combined CI status of the pull request last commit: SUCCESS, FAILURE, PENDING, ERROR or EXPECTED.",
+			Type:        graphql.String,
+		},
+		"exceedsThreshold": &graphql.Field{
+			Description: "Whether the pull request age exceeds the historical cycle time threshold.",
+			Type:        graphql.Boolean,
+		},
+	},
+})
+
+var RateLimitType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "RateLimitType",
+	Fields: graphql.Fields{
+		"limit": &graphql.Field{
+			Description: "The maximum number of points per hour.",
+			Type:        graphql.Int,
+		},
+		"cost": &graphql.Field{
+			Description: "The cost of the last query.",
+			Type:        graphql.Int,
+		},
+		"remaining": &graphql.Field{
+			Description: "The number of points remaining in the current window.",
+			Type:        graphql.Int,
+		},
+		"used": &graphql.Field{
+			Description: "The number of points used in the current window.",
+			Type:        graphql.Int,
+		},
+		"resetAt": &graphql.Field{
+			Description: "The time the current window resets in RFC3339 format.",
+			Type:        graphql.String,
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				rateLimit, ok := p.Source.(api.RateLimit)
+				if !ok || rateLimit.ResetAt.IsZero() {
+					return nil, nil
+				}
+				return rateLimit.ResetAt.Format(time.RFC3339), nil
+			},
+		},
 	},
 })
 
@@ -355,6 +1292,41 @@ var ContributorType = graphql.NewObject(graphql.ObjectConfig{
 			Description: "The profile url of a contributor.",
 			Type:        graphql.String,
 		},
+		"login": &graphql.Field{
+			Description: "The login of a contributor.",
+			Type:        graphql.String,
+		},
+		"name": &graphql.Field{
+			Description: "The name of a contributor, set for co-authors.",
+			Type:        graphql.String,
+		},
+		"role": &graphql.Field{
+			Description: "The role of a contributor in the pull request.",
+			Type:        ContributorRoleType,
+		},
+	},
+})
+
+var ContributorRoleType = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "ContributorRoleType",
+	Description: "The role of a contributor in a pull request.",
+	Values: graphql.EnumValueConfigMap{
+		"AUTHOR": &graphql.EnumValueConfig{
+			Value:       string(metricTypes.AuthorContributorRole),
+			Description: "The pull request author.",
+		},
+		"CO_AUTHOR": &graphql.EnumValueConfig{
+			Value:       string(metricTypes.CoAuthorContributorRole),
+			Description: "A contributor credited by a `Co-authored-by` commit trailer.",
+		},
+		"REVIEWER": &graphql.EnumValueConfig{
+			Value:       string(metricTypes.ReviewerContributorRole),
+			Description: "A contributor that reviewed the pull request.",
+		},
+		"COMMENTER": &graphql.EnumValueConfig{
+			Value:       string(metricTypes.CommenterContributorRole),
+			Description: "A contributor that commented on the pull request.",
+		},
 	},
 })
 
@@ -399,6 +1371,14 @@ var GitHubPullRequestType = graphql.NewObject(graphql.ObjectConfig{
 					Type:        graphql.NewNonNull(graphql.String),
 					Description: "The GitHub repository URL to export pull requests from",
 				},
+				"paths": &graphql.ArgumentConfig{
+					Type:        graphql.NewList(graphql.NewNonNull(graphql.String)),
+					Description: "The glob patterns of the files the exported pull requests must change one of.",
+				},
+				"component": &graphql.ArgumentConfig{
+					Type:        graphql.String,
+					Description: "The name of the configured component whose paths the exported pull requests must change one of.",
+				},
 			},
 			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
 				srvs, err := util.ServicesFromResolveParams(p)
@@ -417,9 +1397,18 @@ var GitHubPullRequestType = graphql.NewObject(graphql.ObjectConfig{
 					return nil, err
 				}
 
+				paths, err := pullRequestPathsFromArgs(p.Args)
+				if err != nil {
+					return nil, err
+				}
+
+				component, _ := p.Args["component"].(string)
+
 				// Fetch all pull requests
 				params := metrics.FindAllPullRequestsParams{
 					RepositoryURL: repositoryURL,
+					Paths:         paths,
+					Component:     component,
 				}
 
 				result, err := srvs.MetricsService.FindAllPullRequests(p.Context, params)
@@ -429,7 +1418,7 @@ var GitHubPullRequestType = graphql.NewObject(graphql.ObjectConfig{
 
 				// Create export file path
 				exportDir := filepath.Join("assets", "generated", repo, "exports", "pull_requests")
-				exportPath := filepath.Join(exportDir, "data.txt")
+				exportPath := filepath.Join(exportDir, exportFileName(paths, component))
 
 				// Check if file already exists
 				if _, err := os.Stat(exportPath); err == nil {
@@ -474,3 +1463,74 @@ var GitHubPullRequestType = graphql.NewObject(graphql.ObjectConfig{
 		},
 	},
 })
+
+var SyncStatusType = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "SyncStatusType",
+	Description: "The status of a repository sync.",
+	Values: graphql.EnumValueConfigMap{
+		"PENDING": &graphql.EnumValueConfig{
+			Value:       string(metricTypes.PendingSyncStatus),
+			Description: "A repository not synced yet.",
+		},
+		"SUCCEEDED": &graphql.EnumValueConfig{
+			Value:       string(metricTypes.SucceededSyncStatus),
+			Description: "A successful sync.",
+		},
+		"FAILED": &graphql.EnumValueConfig{
+			Value:       string(metricTypes.FailedSyncStatus),
+			Description: "A failed sync.",
+		},
+	},
+})
+
+var TrackedRepositoryType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "TrackedRepositoryType",
+	Fields: graphql.Fields{
+		"id": &graphql.Field{
+			Description: "The tracked repository ID.",
+			Type:        graphql.String,
+		},
+		"owner": &graphql.Field{
+			Description: "The repository owner.",
+			Type:        graphql.String,
+		},
+		"repo": &graphql.Field{
+			Description: "The repository name.",
+			Type:        graphql.String,
+		},
+		"url": &graphql.Field{
+			Description: "The repository URL.",
+			Type:        graphql.String,
+		},
+		"trackedAt": &graphql.Field{
+			Description: "The time the repository was tracked in RFC3339 format.",
+			Type:        graphql.String,
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				trackedRepository, ok := p.Source.(api.TrackedRepository)
+				if !ok || trackedRepository.TrackedAt.IsZero() {
+					return nil, nil
+				}
+				return trackedRepository.TrackedAt.Format(time.RFC3339), nil
+			},
+		},
+		"lastSyncedAt": &graphql.Field{
+			Description: "The time of the last sync in RFC3339 format.",
+			Type:        graphql.String,
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				trackedRepository, ok := p.Source.(api.TrackedRepository)
+				if !ok || trackedRepository.LastSyncedAt == nil {
+					return nil, nil
+				}
+				return trackedRepository.LastSyncedAt.Format(time.RFC3339), nil
+			},
+		},
+		"lastSyncStatus": &graphql.Field{
+			Description: "The status of the last sync.",
+			Type:        SyncStatusType,
+		},
+		"lastSyncError": &graphql.Field{
+			Description: "The error of the last sync, empty unless it failed.",
+			Type:        graphql.String,
+		},
+	},
+})
@@ -0,0 +1,97 @@
+package types
+
+import (
+	"context"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+
+	"github.com/chris-ramon/golang-scaffolding/domain/internal/services"
+	"github.com/chris-ramon/golang-scaffolding/domain/metrics"
+	"github.com/chris-ramon/golang-scaffolding/domain/metrics/api"
+	metricTypes "github.com/chris-ramon/golang-scaffolding/domain/metrics/types"
+)
+
+type mockMetricsService struct {
+	services.MetricsService
+}
+
+func (m *mockMetricsService) FindPullRequests(ctx context.Context, params metricTypes.FindPullRequestsParams) (*metrics.FindPullRequestsResult, error) {
+	result := &metrics.FindPullRequestsResult{}
+
+	for _, param := range params {
+		pr := &metricTypes.PullRequest{Number: param.Number, URL: param.URL, Title: "Title"}
+		if param.Number == 404 {
+			pr = &metricTypes.PullRequest{
+				Number: param.Number,
+				URL:    param.URL,
+				Error:  &metricTypes.PullRequestError{Code: metricTypes.NotFoundPullRequestErrorCode, Message: "pull request not found"},
+			}
+		}
+		result.PullRequests = append(result.PullRequests, pr)
+	}
+
+	return result, nil
+}
+
+func TestPullRequestsFromURLs(t *testing.T) {
+	params := graphql.ResolveParams{Context: context.Background()}
+
+	testCases := []struct {
+		name          string
+		urls          []string
+		expectedCodes []string
+		expectedError string
+	}{
+		{
+			name:          "partial result",
+			urls:          []string{"https://github.com/owner/repo/pull/1", "not a url", "https://github.com/owner/repo/pull/404"},
+			expectedCodes: []string{"", "INVALID_URL", "NOT_FOUND"},
+		},
+		{
+			name:          "every pull request failed",
+			urls:          []string{"https://github.com/owner/repo/pull/404", "https://github.com/owner/repo"},
+			expectedError: "NOT_FOUND",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := pullRequestsFromURLs(params, &mockMetricsService{}, tc.urls)
+
+			if tc.expectedError != "" {
+				formatted := gqlerrors.FormatError(&gqlerrors.Error{Message: "failed", OriginalError: err})
+				if formatted.Extensions["code"] != tc.expectedError {
+					t.Errorf("expected error with code %s in the extensions, got %v", tc.expectedError, formatted.Extensions)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			pullRequests := result.(api.PullRequests)
+			if len(pullRequests) != len(tc.urls) {
+				t.Fatalf("expected %d pull requests, got %d", len(tc.urls), len(pullRequests))
+			}
+
+			for i, expectedCode := range tc.expectedCodes {
+				pr := pullRequests[i]
+
+				if pr.URL != tc.urls[i] {
+					t.Errorf("expected pull request %d of %s, got %s", i, tc.urls[i], pr.URL)
+				}
+
+				code := ""
+				if pr.Error != nil {
+					code = pr.Error.Code
+				}
+				if code != expectedCode {
+					t.Errorf("expected pull request %d error code %q, got %q", i, expectedCode, code)
+				}
+			}
+		})
+	}
+}
@@ -26,6 +26,15 @@ type MetricsService interface {
 	FindPullRequests(ctx context.Context, params metricTypes.FindPullRequestsParams) (*metrics.FindPullRequestsResult, error)
 	FindAllPullRequests(ctx context.Context, params metrics.FindAllPullRequestsParams) (*metrics.FindAllPullRequestsResult, error)
 	GeneratePullRequestsGantt(ctx context.Context, params metrics.GeneratePullRequestsGanttParams) (*metrics.GeneratePullRequestsGanttResult, error)
+	FindRepositoriesPullRequests(ctx context.Context, params metrics.FindRepositoriesPullRequestsParams) (*metrics.FindRepositoriesPullRequestsResult, error)
+	PullRequestsStatistics(ctx context.Context, params metrics.FindRepositoriesPullRequestsParams) (*metrics.PullRequestsStatisticsResult, error)
+	FindAllIssues(ctx context.Context, params metrics.FindAllIssuesParams) (*metrics.FindAllIssuesResult, error)
+	FindAllReleases(ctx context.Context, params metrics.FindAllReleasesParams) (*metrics.FindAllReleasesResult, error)
+	OpenWork(ctx context.Context, params metrics.OpenWorkParams) (*metrics.OpenWorkResult, error)
+	GitHubRateLimit(ctx context.Context, host string) (*metricTypes.RateLimit, error)
+	TrackRepository(ctx context.Context, params metrics.TrackRepositoryParams) (*metricTypes.TrackedRepository, error)
+	UntrackRepository(ctx context.Context, params metrics.UntrackRepositoryParams) (bool, error)
+	TrackedRepositories(ctx context.Context) ([]*metricTypes.TrackedRepository, error)
 }
 
 type Services struct {
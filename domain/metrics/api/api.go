@@ -18,6 +18,15 @@ type PullRequest struct {
 	// MergedAt is the pull request merged at time.
 	MergedAt *time.Time
 
+	// ClosedAt is the pull request closed at time.
+	ClosedAt *time.Time
+
+	// State is the pull request state.
+	State string `json:"state"`
+
+	// IsDraft reports whether the pull request is a draft.
+	IsDraft bool `json:"isDraft"`
+
 	// URL is the pull request url.
 	URL string
 
@@ -32,12 +41,157 @@ type PullRequest struct {
 
 	// FormattedContributors are the pull request's formatted contributors.
 	FormattedContributors string `json:"formattedContributors"`
+
+	// Repository is the owner and name of the pull request repository, e.g. `graphql-go/graphql`.
+	Repository string `json:"repository"`
+
+	// HeadRefName is the pull request head reference name.
+	HeadRefName string `json:"headRefName"`
+
+	// BaseRefName is the name of the reference the pull request is merged into.
+	BaseRefName string `json:"baseRefName"`
+
+	// Stack is the stack of dependent pull requests the pull request belongs to, nil when it is not stacked.
+	Stack *PullRequestStack `json:"stack"`
+
+	// Labels are the names of the pull request labels.
+	Labels []string `json:"labels"`
+
+	// Milestone is the title of the pull request milestone.
+	Milestone string `json:"milestone"`
+
+	// Assignees are the logins of the users assigned to the pull request.
+	Assignees []string `json:"assignees"`
+
+	// Files are the paths of the files changed by the pull request.
+	Files []string `json:"files"`
+
+	// LinkedIssues are the issues the pull request closes when merged.
+	LinkedIssues Issues `json:"linkedIssues"`
+
+	// Error is the error of the pull request when it could not be fetched.
+	Error *PullRequestError `json:"error"`
+}
+
+// PullRequestStack represents the position of a pull request in a stack of pull requests.
+type PullRequestStack struct {
+	// Root is the number of the bottom pull request of the stack.
+	Root int `json:"root"`
+
+	// Position is the position of the pull request in the stack, 1 for the root.
+	Position int `json:"position"`
+
+	// Parent is the number of the pull request the pull request is based on, nil for the root.
+	Parent *int `json:"parent"`
+
+	// Size is the number of pull requests of the stack.
+	Size int `json:"size"`
+}
+
+// Issue represents an issue.
+type Issue struct {
+	// Number is the unique number of the issue.
+	Number int `json:"number"`
+
+	// Owner is the owner of the issue repository.
+	Owner string `json:"owner"`
+
+	// Repo is the repository name of the issue.
+	Repo string `json:"repo"`
+
+	// URL is the issue url.
+	URL string `json:"url"`
+
+	// Title is the issue title.
+	Title string `json:"title"`
+
+	// Body is the issue body.
+	Body string `json:"body"`
+
+	// State is the issue state.
+	State string `json:"state"`
+
+	// Author is the issue author login.
+	Author string `json:"author"`
+
+	// CreatedAt is the issue created at time.
+	CreatedAt *time.Time `json:"createdAt"`
+
+	// ClosedAt is the issue closed at time.
+	ClosedAt *time.Time `json:"closedAt"`
+}
+
+// Issues are a slice of issues.
+type Issues []Issue
+
+// Release represents a release.
+type Release struct {
+	// TagName is the name of the git tag of the release.
+	TagName string `json:"tagName"`
+
+	// Owner is the owner of the release repository.
+	Owner string `json:"owner"`
+
+	// Repo is the repository name of the release.
+	Repo string `json:"repo"`
+
+	// Name is the release name.
+	Name string `json:"name"`
+
+	// Description is the release description.
+	Description string `json:"description"`
+
+	// URL is the release url.
+	URL string `json:"url"`
+
+	// IsPrerelease reports whether the release is a pre-release.
+	IsPrerelease bool `json:"isPrerelease"`
+
+	// Author is the release author login.
+	Author string `json:"author"`
+
+	// CreatedAt is the release created at time.
+	CreatedAt *time.Time `json:"createdAt"`
+
+	// PublishedAt is the release published at time.
+	PublishedAt *time.Time `json:"publishedAt"`
+}
+
+// Releases are a slice of releases.
+type Releases []Release
+
+// PullRequestError represents the error of a pull request that could not be fetched.
+type PullRequestError struct {
+	// Code is the machine-readable code of the error.
+	Code string `json:"code"`
+
+	// Message is the human-readable message of the error.
+	Message string `json:"message"`
+}
+
+// Error implements the error interface.
+func (e *PullRequestError) Error() string {
+	return e.Message
+}
+
+// Extensions returns the GraphQL error extensions of the error, holding its code.
+func (e *PullRequestError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": e.Code}
 }
 
 // Contributor represents the pull request contributor.
 type Contributor struct {
 	// ProfileURL is the contributor profile URL.
 	ProfileURL string
+
+	// Login is the contributor login.
+	Login string `json:"login"`
+
+	// Name is the contributor name.
+	Name string `json:"name"`
+
+	// Role is the contributor role in the pull request.
+	Role string `json:"role"`
 }
 
 // Contributors represents slice of Contributors.
@@ -54,3 +208,122 @@ type Duration struct {
 
 // PullRequests are a slice of pull requests.
 type PullRequests []PullRequest
+
+// OpenWork represents the open pull requests aging report.
+type OpenWork struct {
+	// Percentile is the historical cycle time percentile used as threshold.
+	Percentile float64 `json:"percentile"`
+
+	// ThresholdInDays is the historical cycle time threshold in days.
+	ThresholdInDays float64 `json:"thresholdInDays"`
+
+	// Items are the open pull requests, oldest first.
+	Items []OpenWorkItem `json:"items"`
+}
+
+// OpenWorkItem represents an open pull request of the aging report.
+type OpenWorkItem struct {
+	// PullRequest is the open pull request.
+	PullRequest PullRequest `json:"pullRequest"`
+
+	// AgeInDays is the time elapsed since the pull request was created in days.
+	AgeInDays float64 `json:"ageInDays"`
+
+	// LastActivityAt is the pull request last activity time.
+	LastActivityAt *time.Time `json:"lastActivityAt"`
+
+	// RequestedReviewers are the users and teams with pending review requests.
+	RequestedReviewers []string `json:"requestedReviewers"`
+
+	// ReviewState is the pull request review decision.
+	ReviewState string `json:"reviewState"`
+
+	// CIStatus is the combined CI status of the pull request last commit.
+	CIStatus string `json:"ciStatus"`
+
+	// ExceedsThreshold reports whether the age exceeds the historical cycle time threshold.
+	ExceedsThreshold bool `json:"exceedsThreshold"`
+}
+
+// Statistics represents the pull requests statistics of several repositories.
+type Statistics struct {
+	// Combined are the statistics of the pull requests of every repository.
+	Combined RepositoryStatistics `json:"combined"`
+
+	// Repositories are the statistics of each repository.
+	Repositories []RepositoryStatistics `json:"repositories"`
+
+	// Truncated reports whether repositories or pull requests were left out by the maximum number of fetched pages.
+	Truncated bool `json:"truncated"`
+}
+
+// RepositoryStatistics represents the pull requests statistics of a repository.
+type RepositoryStatistics struct {
+	// Repository is the owner and name of the repository, empty for the combined statistics.
+	Repository string `json:"repository"`
+
+	// URL is the repository URL, empty for the combined statistics.
+	URL string `json:"url"`
+
+	// Count is the number of pull requests.
+	Count int `json:"count"`
+
+	// OpenCount is the number of open pull requests.
+	OpenCount int `json:"openCount"`
+
+	// MergedCount is the number of merged pull requests.
+	MergedCount int `json:"mergedCount"`
+
+	// ClosedCount is the number of pull requests closed without being merged.
+	ClosedCount int `json:"closedCount"`
+
+	// AverageCycleTimeInDays is the average time from creation to merge of the merged pull requests in days.
+	AverageCycleTimeInDays float64 `json:"averageCycleTimeInDays"`
+
+	// MedianCycleTimeInDays is the median time from creation to merge of the merged pull requests in days.
+	MedianCycleTimeInDays float64 `json:"medianCycleTimeInDays"`
+}
+
+// RateLimit represents the GitHub API rate limit status.
+type RateLimit struct {
+	// Limit is the maximum number of points per hour.
+	Limit int `json:"limit"`
+
+	// Cost is the cost of the last query.
+	Cost int `json:"cost"`
+
+	// Remaining is the number of points remaining in the current window.
+	Remaining int `json:"remaining"`
+
+	// Used is the number of points used in the current window.
+	Used int `json:"used"`
+
+	// ResetAt is the time the current window resets.
+	ResetAt time.Time `json:"resetAt"`
+}
+
+type TrackedRepository struct {
+	// ID is the tracked repository ID.
+	ID string `json:"id"`
+
+	// Owner is the repository owner.
+	Owner string `json:"owner"`
+
+	// Repo is the repository name.
+	Repo string `json:"repo"`
+
+	// URL is the repository URL.
+	URL string `json:"url"`
+
+	// TrackedAt is the time the repository was tracked.
+	TrackedAt time.Time `json:"trackedAt"`
+
+	// LastSyncedAt is the time of the last sync.
+	LastSyncedAt *time.Time `json:"lastSyncedAt"`
+
+	// LastSyncStatus is the status of the last sync.
+	LastSyncStatus string `json:"lastSyncStatus"`
+
+	// LastSyncError is the error of the last sync.
+	LastSyncError string `json:"lastSyncError"`
+}
@@ -0,0 +1,88 @@
+package metrics
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/chris-ramon/golang-scaffolding/domain/metrics/types"
+	"github.com/chris-ramon/golang-scaffolding/pkg/glob"
+)
+
+// pullRequestsFilter represents the filters narrowing down the pull requests of a repository.
+type pullRequestsFilter struct {
+	// labels are the labels the pull requests must all have, compared case-insensitively.
+	labels []string
+
+	// milestone is the milestone title of the pull requests, compared case-insensitively.
+	milestone string
+
+	// paths are the glob patterns of the files the pull requests must change one of.
+	paths []string
+}
+
+// pullRequestsFilter returns the filter of the given parameters, whose paths include the ones of the given
+// component.
+func (s *service) pullRequestsFilter(params FindAllPullRequestsParams) (pullRequestsFilter, error) {
+	filter := pullRequestsFilter{
+		labels:    params.Labels,
+		milestone: params.Milestone,
+		paths:     params.Paths,
+	}
+
+	if params.Component != "" {
+		paths, ok := s.Components[params.Component]
+		if !ok {
+			return filter, fmt.Errorf("unknown component %q", params.Component)
+		}
+		filter.paths = append(slices.Clone(params.Paths), paths...)
+	}
+
+	for _, path := range filter.paths {
+		if err := glob.Validate(path); err != nil {
+			return filter, fmt.Errorf("invalid path %q: %w", path, err)
+		}
+	}
+
+	return filter, nil
+}
+
+// empty reports whether the filter keeps every pull request.
+func (f pullRequestsFilter) empty() bool {
+	return len(f.labels) == 0 && f.milestone == "" && len(f.paths) == 0
+}
+
+// matches reports whether the given pull request passes the filter.
+func (f pullRequestsFilter) matches(pr *types.PullRequest) bool {
+	if f.milestone != "" && !strings.EqualFold(pr.Milestone, f.milestone) {
+		return false
+	}
+
+	for _, label := range f.labels {
+		if !slices.ContainsFunc(pr.Labels, func(l string) bool { return strings.EqualFold(l, label) }) {
+			return false
+		}
+	}
+
+	if len(f.paths) > 0 && !glob.MatchAny(f.paths, pr.Files) {
+		return false
+	}
+
+	return true
+}
+
+// apply returns the given result with only the pull requests passing the filter.
+func (f pullRequestsFilter) apply(result *FindAllPullRequestsResult) *FindAllPullRequestsResult {
+	filtered := &FindAllPullRequestsResult{
+		PullRequests: []*types.PullRequest{},
+		Truncated:    result.Truncated,
+	}
+
+	for _, pr := range result.PullRequests {
+		if f.matches(pr) {
+			filtered.PullRequests = append(filtered.PullRequests, pr)
+		}
+	}
+
+	return filtered
+}
@@ -3,13 +3,13 @@ package metrics
 import (
 	"context"
 	"encoding/xml"
-	"net/http"
 	"os"
 	"path/filepath"
 	"testing"
 	"time"
 
 	cachePkg "github.com/chris-ramon/golang-scaffolding/cache"
+	"github.com/chris-ramon/golang-scaffolding/config"
 	"github.com/chris-ramon/golang-scaffolding/domain/metrics/types"
 	"github.com/chris-ramon/golang-scaffolding/drawio/gantt"
 )
@@ -17,8 +17,7 @@ import (
 func TestGenerateGanttDrawIOFromPullRequests(t *testing.T) {
 	// Create a test service
 	cache := cachePkg.New()
-	httpClient := &http.Client{}
-	service, err := NewService(cache, httpClient)
+	service, err := NewService(cache, nil, &config.Config{GitHubConfig: &config.GitHubConfig{}})
 	if err != nil {
 		t.Fatalf("Failed to create service: %v", err)
 	}
@@ -42,7 +41,7 @@ func TestGenerateGanttDrawIOFromPullRequests(t *testing.T) {
 	}
 
 	// Generate DrawIO content
-	drawioContent, err := service.generateGanttDrawIOFromPullRequests(testPRs)
+	drawioContent, err := service.generateGanttDrawIOFromPullRequests(testPRs, false)
 	if err != nil {
 		t.Fatalf("Failed to generate DrawIO content: %v", err)
 	}
@@ -94,9 +93,14 @@ func TestGeneratePullRequestsGanttIntegration(t *testing.T) {
 	}
 
 	// Create a test service
+	gitHubConfig, err := config.NewGitHubConfig()
+	if err != nil {
+		t.Fatalf("Failed to create GitHub config: %v", err)
+	}
+	gitHubConfig.SyncDir = t.TempDir()
+
 	cache := cachePkg.New()
-	httpClient := &http.Client{}
-	service, err := NewService(cache, httpClient)
+	service, err := NewService(cache, nil, &config.Config{GitHubConfig: gitHubConfig})
 	if err != nil {
 		t.Fatalf("Failed to create service: %v", err)
 	}
@@ -0,0 +1,461 @@
+package gitea
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/chris-ramon/golang-scaffolding/config"
+	"github.com/chris-ramon/golang-scaffolding/domain/metrics/provider"
+	"github.com/chris-ramon/golang-scaffolding/domain/metrics/rest"
+	"github.com/chris-ramon/golang-scaffolding/domain/metrics/types"
+)
+
+// Gitea represents the Gitea component, fetching the pull requests of a Gitea or Forgejo host from its REST API.
+type Gitea struct {
+	// Client is the HTTP client of the Gitea requests.
+	Client *http.Client
+
+	// APIURL is the REST API endpoint, e.g. `https://gitea.example.com/api/v1`.
+	APIURL string
+
+	// Token is the access token the requests are authenticated with, anonymous when empty.
+	Token string
+
+	// MaxPages is the maximum number of pages to fetch when paginating, zero means unbounded.
+	MaxPages int
+
+	// Concurrency is the number of pull requests whose reviews and files are fetched at once, defaults to
+	// `rest.DefaultConcurrency`.
+	Concurrency int
+}
+
+// limit is the number of items requested per page, the default maximum allowed by Gitea. Servers configured
+// with a lower `MAX_RESPONSE_ITEMS` return fewer, so the pages are followed from the pagination headers.
+const limit = 50
+
+// pullRequest represents a pull request of the Gitea REST API.
+type pullRequest struct {
+	Number    int        `json:"number"`
+	Title     string     `json:"title"`
+	Body      string     `json:"body"`
+	State     string     `json:"state"`
+	Draft     bool       `json:"draft"`
+	Merged    bool       `json:"merged"`
+	HTMLURL   string     `json:"html_url"`
+	CreatedAt *time.Time `json:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at"`
+	MergedAt  *time.Time `json:"merged_at"`
+	ClosedAt  *time.Time `json:"closed_at"`
+	User      user       `json:"user"`
+	Head      struct {
+		Ref string `json:"ref"`
+	} `json:"head"`
+	Base struct {
+		Ref string `json:"ref"`
+	} `json:"base"`
+	RequestedReviewers []user `json:"requested_reviewers"`
+	Assignees          []user `json:"assignees"`
+	Labels             []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+	Milestone *struct {
+		Title string `json:"title"`
+	} `json:"milestone"`
+}
+
+// review represents a pull request review of the Gitea REST API.
+type review struct {
+	User  user   `json:"user"`
+	State string `json:"state"`
+}
+
+// changedFile represents a file changed by a pull request of the Gitea REST API.
+type changedFile struct {
+	Filename string `json:"filename"`
+}
+
+// release represents a release of the Gitea REST API.
+type release struct {
+	TagName     string     `json:"tag_name"`
+	Name        string     `json:"name"`
+	Body        string     `json:"body"`
+	HTMLURL     string     `json:"html_url"`
+	Draft       bool       `json:"draft"`
+	Prerelease  bool       `json:"prerelease"`
+	CreatedAt   *time.Time `json:"created_at"`
+	PublishedAt *time.Time `json:"published_at"`
+	Author      user       `json:"author"`
+}
+
+// user represents a user of the Gitea REST API.
+type user struct {
+	ID      int    `json:"id"`
+	Login   string `json:"login"`
+	HTMLURL string `json:"html_url"`
+}
+
+// AllPullRequests fetches the pull requests of the given states from a Gitea repository, newest first. Gitea
+// lists the merged pull requests along with the closed ones, which are told apart by their merged flag. It
+// iterates through all the pages unless a maximum number of pages is set, in which case the result reports
+// whether it is truncated. The reviews and files of the pull requests of each page are fetched a bounded number
+// of pull requests at once.
+func (g *Gitea) AllPullRequests(ctx context.Context, params provider.AllPullRequestsParams) (*provider.AllPullRequestsResult, error) {
+	pullRequestStates := params.States
+	if len(pullRequestStates) == 0 {
+		pullRequestStates = []types.PullRequestState{types.MergedPullRequestState}
+	}
+
+	wanted := map[types.PullRequestState]bool{}
+	for _, state := range pullRequestStates {
+		wanted[state] = true
+	}
+
+	state := "all"
+	switch {
+	case !wanted[types.OpenPullRequestState]:
+		state = "closed"
+	case !wanted[types.ClosedPullRequestState] && !wanted[types.MergedPullRequestState]:
+		state = "open"
+	}
+
+	result := &provider.AllPullRequestsResult{PullRequests: []*types.PullRequest{}}
+
+	listed := 0
+	for page := 1; page != 0; {
+		if g.MaxPages > 0 && page > g.MaxPages {
+			result.Truncated = true
+			break
+		}
+
+		query := url.Values{
+			"state": {state},
+			"sort":  {"newest"},
+			"limit": {strconv.Itoa(limit)},
+			"page":  {strconv.Itoa(page)},
+		}
+
+		listedPullRequests := []pullRequest{}
+		header, err := g.client().Get(ctx, repoPath(params.Owner, params.Repo)+"/pulls?"+query.Encode(), &listedPullRequests)
+		if err != nil {
+			return nil, err
+		}
+		listed += len(listedPullRequests)
+
+		pullRequests := []pullRequest{}
+		for _, p := range listedPullRequests {
+			if wanted[pullRequestState(p)] {
+				pullRequests = append(pullRequests, p)
+			}
+		}
+
+		prs := make([]*types.PullRequest, len(pullRequests))
+		err = rest.Each(ctx, len(pullRequests), g.Concurrency, func(ctx context.Context, i int) error {
+			pr, err := g.pullRequest(ctx, params.Host, params.Owner, params.Repo, pullRequests[i])
+			prs[i] = pr
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, pr := range prs {
+			if pr != nil {
+				result.PullRequests = append(result.PullRequests, pr)
+			}
+		}
+
+		page = rest.NextPage(header, page, listed)
+	}
+
+	return result, nil
+}
+
+// PullRequests fetches the given pull requests, a bounded number at once, reporting the missing ones as not
+// found.
+func (g *Gitea) PullRequests(ctx context.Context, params provider.PullRequestsParams) ([]provider.PullRequestResult, error) {
+	results := make([]provider.PullRequestResult, len(params.PullRequests))
+
+	err := rest.Each(ctx, len(params.PullRequests), g.Concurrency, func(ctx context.Context, i int) error {
+		param := params.PullRequests[i]
+
+		p := pullRequest{}
+		_, err := g.client().Get(ctx, fmt.Sprintf("%s/pulls/%d", repoPath(param.Owner, param.Repo), param.Number), &p)
+		if err != nil {
+			results[i].Err = err
+			return nil
+		}
+
+		pr, err := g.pullRequest(ctx, params.Host, param.Owner, param.Repo, p)
+		if err != nil {
+			results[i].Err = err
+			return nil
+		}
+		if pr == nil {
+			results[i].Err = fmt.Errorf("unexpected created at nil value")
+			return nil
+		}
+
+		results[i].PullRequest = pr
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// AllReleases fetches the releases of a Gitea repository, most recently created first, leaving out the drafts.
+// It iterates through all the pages unless a maximum number of pages is set, in which case the result reports
+// whether it is truncated.
+func (g *Gitea) AllReleases(ctx context.Context, params provider.AllReleasesParams) (*provider.AllReleasesResult, error) {
+	result := &provider.AllReleasesResult{Releases: []*types.Release{}}
+
+	listed := 0
+	for page := 1; page != 0; {
+		if g.MaxPages > 0 && page > g.MaxPages {
+			result.Truncated = true
+			break
+		}
+
+		query := url.Values{
+			"draft": {"false"},
+			"limit": {strconv.Itoa(limit)},
+			"page":  {strconv.Itoa(page)},
+		}
+
+		releases := []release{}
+		header, err := g.client().Get(ctx, repoPath(params.Owner, params.Repo)+"/releases?"+query.Encode(), &releases)
+		if err != nil {
+			return nil, err
+		}
+		listed += len(releases)
+
+		for _, r := range releases {
+			if r.Draft {
+				continue
+			}
+
+			result.Releases = append(result.Releases, &types.Release{
+				TagName:      r.TagName,
+				Host:         params.Host,
+				Owner:        params.Owner,
+				Repo:         params.Repo,
+				Name:         r.Name,
+				Description:  r.Body,
+				URL:          r.HTMLURL,
+				IsPrerelease: r.Prerelease,
+				CreatedAt:    rest.UTC(r.CreatedAt),
+				PublishedAt:  rest.UTC(r.PublishedAt),
+				Author:       types.Author{Login: r.Author.Login},
+			})
+		}
+
+		page = rest.NextPage(header, page, listed)
+	}
+
+	return result, nil
+}
+
+// pullRequest returns the pull request of the given Gitea pull request along with its reviews and changed
+// files, nil when the pull request is incomplete.
+func (g *Gitea) pullRequest(ctx context.Context, host string, owner string, repo string, p pullRequest) (*types.PullRequest, error) {
+	if p.CreatedAt == nil {
+		return nil, nil
+	}
+
+	reviews, err := allPages[review](ctx, g, fmt.Sprintf("%s/pulls/%d/reviews", repoPath(owner, repo), p.Number))
+	if err != nil {
+		return nil, err
+	}
+
+	changedFiles, err := allPages[changedFile](ctx, g, fmt.Sprintf("%s/pulls/%d/files", repoPath(owner, repo), p.Number))
+	if err != nil {
+		return nil, err
+	}
+
+	files := []string{}
+	for _, f := range changedFiles {
+		files = append(files, f.Filename)
+	}
+
+	createdAt := p.CreatedAt.UTC()
+	pr := &types.PullRequest{
+		Number:         p.Number,
+		Host:           host,
+		Owner:          owner,
+		Repo:           repo,
+		Title:          p.Title,
+		Body:           p.Body,
+		CreatedAt:      &createdAt,
+		MergedAt:       rest.UTC(p.MergedAt),
+		ClosedAt:       rest.UTC(p.ClosedAt),
+		UpdatedAt:      rest.UTC(p.UpdatedAt),
+		State:          pullRequestState(p),
+		IsDraft:        p.Draft,
+		URL:            p.HTMLURL,
+		HeadRefName:    p.Head.Ref,
+		BaseRefName:    p.Base.Ref,
+		Contributors:   contributors(p, reviews),
+		ReviewDecision: reviewDecision(p, reviews),
+		Files:          files,
+	}
+
+	for _, reviewer := range p.RequestedReviewers {
+		pr.RequestedReviewers = append(pr.RequestedReviewers, reviewer.Login)
+	}
+
+	for _, label := range p.Labels {
+		pr.Labels = append(pr.Labels, label.Name)
+	}
+
+	for _, assignee := range p.Assignees {
+		pr.Assignees = append(pr.Assignees, assignee.Login)
+	}
+
+	if p.Milestone != nil {
+		pr.Milestone = p.Milestone.Title
+	}
+
+	endedAt := time.Now().UTC()
+	if e := pr.EndedAt(); e != nil {
+		endedAt = *e
+	}
+	pr.Duration = endedAt.Sub(createdAt)
+	pr.FormattedContributors = pr.Contributors.FormattedContributors(types.CommasFormatContributorType)
+
+	return pr, nil
+}
+
+// allPages returns the items of every page of the given API path, e.g. the reviews or files of a pull request.
+func allPages[T any](ctx context.Context, g *Gitea, path string) ([]T, error) {
+	items := []T{}
+
+	for page := 1; page != 0; {
+		query := url.Values{
+			"limit": {strconv.Itoa(limit)},
+			"page":  {strconv.Itoa(page)},
+		}
+
+		pageItems := []T{}
+		header, err := g.client().Get(ctx, path+"?"+query.Encode(), &pageItems)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, pageItems...)
+
+		page = rest.NextPage(header, page, len(items))
+	}
+
+	return items, nil
+}
+
+// contributors returns the pull request contributors: the author first, followed by the reviewers, or
+// commenters when they only left comments.
+func contributors(p pullRequest, reviews []review) types.Contributors {
+	contributors := types.Contributors{{
+		Login:      p.User.Login,
+		ID:         strconv.Itoa(p.User.ID),
+		ProfileURL: p.User.HTMLURL,
+		Role:       types.AuthorContributorRole,
+	}}
+	indexByID := map[int]int{p.User.ID: 0}
+
+	for _, r := range reviews {
+		role := types.ReviewerContributorRole
+		if r.State == "COMMENT" {
+			role = types.CommenterContributorRole
+		}
+
+		if i, ok := indexByID[r.User.ID]; ok {
+			if role == types.ReviewerContributorRole && contributors[i].Role == types.CommenterContributorRole {
+				contributors[i].Role = role
+			}
+			continue
+		}
+
+		indexByID[r.User.ID] = len(contributors)
+		contributors = append(contributors, types.Contributor{
+			Login:      r.User.Login,
+			ID:         strconv.Itoa(r.User.ID),
+			ProfileURL: r.User.HTMLURL,
+			Role:       role,
+		})
+	}
+
+	return contributors
+}
+
+// reviewDecision returns the review state of the pull request from the latest review of each reviewer:
+// CHANGES_REQUESTED when any requested changes, APPROVED when any approved, REVIEW_REQUIRED when reviews are
+// pending and empty otherwise.
+func reviewDecision(p pullRequest, reviews []review) string {
+	latest := map[int]string{}
+	for _, r := range reviews {
+		if r.State == "APPROVED" || r.State == "REQUEST_CHANGES" {
+			latest[r.User.ID] = r.State
+		}
+	}
+
+	approved := false
+	for _, state := range latest {
+		if state == "REQUEST_CHANGES" {
+			return "CHANGES_REQUESTED"
+		}
+		approved = true
+	}
+
+	switch {
+	case approved:
+		return "APPROVED"
+	case len(p.RequestedReviewers) > 0:
+		return "REVIEW_REQUIRED"
+	default:
+		return ""
+	}
+}
+
+// pullRequestState returns the state of the given Gitea pull request, whose closed state includes the merged
+// pull requests.
+func pullRequestState(p pullRequest) types.PullRequestState {
+	switch {
+	case p.Merged || p.MergedAt != nil:
+		return types.MergedPullRequestState
+	case p.State == "closed":
+		return types.ClosedPullRequestState
+	default:
+		return types.OpenPullRequestState
+	}
+}
+
+// repoPath returns the API path of the given repository.
+func repoPath(owner string, repo string) string {
+	return fmt.Sprintf("repos/%s/%s", url.PathEscape(owner), url.PathEscape(repo))
+}
+
+// client returns the REST API client of the Gitea requests.
+func (g *Gitea) client() *rest.Client {
+	return &rest.Client{
+		HTTPClient:  g.Client,
+		APIURL:      g.APIURL,
+		Service:     "Gitea",
+		TokenHeader: "Authorization",
+		TokenPrefix: "token ",
+		Token:       g.Token,
+	}
+}
+
+// NewGitea returns a Gitea component of the given host configuration.
+func NewGitea(conf *config.GiteaHostConfig, timeout time.Duration) *Gitea {
+	return &Gitea{
+		Client:      rest.NewHTTPClient(timeout),
+		APIURL:      conf.APIURL,
+		Token:       conf.Token,
+		MaxPages:    conf.MaxPages,
+		Concurrency: rest.DefaultConcurrency,
+	}
+}
@@ -0,0 +1,271 @@
+package gitea
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/chris-ramon/golang-scaffolding/domain/metrics/provider"
+	"github.com/chris-ramon/golang-scaffolding/domain/metrics/types"
+)
+
+// newTestGitea returns a Gitea component whose API is served by a test server listing a merged, a closed and
+// an open pull request of the mirrors/service repository.
+func newTestGitea(t *testing.T) *Gitea {
+	t.Helper()
+
+	pullRequest := func(number int, state string, merged bool, mergedAt string) string {
+		return fmt.Sprintf(`{
+			"number": %d, "title": "PR %d", "body": "Body", "state": %q, "merged": %v,
+			"html_url": "https://gitea.example.com/mirrors/service/pulls/%d",
+			"created_at": "2024-01-01T10:00:00Z", "updated_at": "2024-01-03T10:00:00Z",
+			"merged_at": %s, "closed_at": null,
+			"user": {"id": 1, "login": "author", "html_url": "https://gitea.example.com/author"},
+			"head": {"ref": "feature-%d"}, "base": {"ref": "main"},
+			"requested_reviewers": [{"id": 4, "login": "pending"}],
+			"assignees": [{"id": 1, "login": "author"}],
+			"labels": [{"name": "bug"}, {"name": "backend"}], "milestone": {"title": "v1.0"}
+		}`, number, number, state, merged, number, mergedAt, number)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "token token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		const repo = "/api/v1/repos/mirrors/service"
+
+		switch {
+		case r.URL.Path == repo+"/pulls":
+			fmt.Fprintf(w, "[%s,%s,%s]",
+				pullRequest(3, "closed", true, `"2024-01-02T10:00:00Z"`),
+				pullRequest(2, "closed", false, "null"),
+				pullRequest(1, "open", false, "null"),
+			)
+		case strings.HasSuffix(r.URL.Path, "/reviews"):
+			w.Write([]byte(`[
+				{"user": {"id": 2, "login": "reviewer"}, "state": "REQUEST_CHANGES"},
+				{"user": {"id": 3, "login": "commenter"}, "state": "COMMENT"},
+				{"user": {"id": 2, "login": "reviewer"}, "state": "APPROVED"}
+			]`))
+		case strings.HasSuffix(r.URL.Path, "/files"):
+			w.Write([]byte(`[{"filename": "services/api/main.go", "status": "changed"}]`))
+		case r.URL.Path == repo+"/pulls/3":
+			w.Write([]byte(pullRequest(3, "closed", true, `"2024-01-02T10:00:00Z"`)))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return &Gitea{Client: server.Client(), APIURL: server.URL + "/api/v1", Token: "token"}
+}
+
+func TestAllPullRequests(t *testing.T) {
+	g := newTestGitea(t)
+
+	testCases := []struct {
+		name            string
+		states          []types.PullRequestState
+		expectedNumbers []int
+	}{
+		{name: "merged by default", expectedNumbers: []int{3}},
+		{name: "closed without merged", states: []types.PullRequestState{types.ClosedPullRequestState}, expectedNumbers: []int{2}},
+		{name: "every state", states: []types.PullRequestState{types.OpenPullRequestState, types.ClosedPullRequestState, types.MergedPullRequestState}, expectedNumbers: []int{3, 2, 1}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := g.AllPullRequests(context.Background(), provider.AllPullRequestsParams{
+				Host:   "gitea.example.com",
+				Owner:  "mirrors",
+				Repo:   "service",
+				States: tc.states,
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(result.PullRequests) != len(tc.expectedNumbers) {
+				t.Fatalf("expected %d pull requests, got %d", len(tc.expectedNumbers), len(result.PullRequests))
+			}
+
+			for i, number := range tc.expectedNumbers {
+				if result.PullRequests[i].Number != number {
+					t.Errorf("expected pull request %d to be #%d, got #%d", i, number, result.PullRequests[i].Number)
+				}
+			}
+		})
+	}
+}
+
+func TestPullRequests(t *testing.T) {
+	g := newTestGitea(t)
+
+	results, err := g.PullRequests(context.Background(), provider.PullRequestsParams{
+		Host: "gitea.example.com",
+		PullRequests: []provider.PullRequestParams{
+			{Owner: "mirrors", Repo: "service", Number: 3},
+			{Owner: "mirrors", Repo: "service", Number: 404},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	pr := results[0].PullRequest
+	if results[0].Err != nil || pr == nil {
+		t.Fatalf("expected the pull request, got %+v", results[0])
+	}
+
+	if pr.State != types.MergedPullRequestState || pr.MergedAt == nil || pr.Duration.Hours() != 24 || pr.HeadRefName != "feature-3" || pr.BaseRefName != "main" {
+		t.Errorf("unexpected pull request: %+v", pr)
+	}
+
+	if fmt.Sprint(pr.Labels) != "[bug backend]" || pr.Milestone != "v1.0" || fmt.Sprint(pr.Assignees) != "[author]" {
+		t.Errorf("unexpected pull request metadata: labels %v, milestone %q, assignees %v", pr.Labels, pr.Milestone, pr.Assignees)
+	}
+
+	if fmt.Sprint(pr.Files) != "[services/api/main.go]" {
+		t.Errorf("expected the changed files, got %v", pr.Files)
+	}
+
+	if pr.ReviewDecision != "APPROVED" {
+		t.Errorf("expected the latest review of each reviewer to approve, got %s", pr.ReviewDecision)
+	}
+
+	expectedRoles := []types.ContributorRole{types.AuthorContributorRole, types.ReviewerContributorRole, types.CommenterContributorRole}
+	if len(pr.Contributors) != len(expectedRoles) {
+		t.Fatalf("expected %d contributors, got %+v", len(expectedRoles), pr.Contributors)
+	}
+	for i, role := range expectedRoles {
+		if pr.Contributors[i].Role != role {
+			t.Errorf("expected contributor %d role %s, got %s", i, role, pr.Contributors[i].Role)
+		}
+	}
+
+	if !errors.Is(results[1].Err, provider.ErrPullRequestNotFound) {
+		t.Errorf("expected not found error, got %v", results[1].Err)
+	}
+}
+
+// newPagedTestGitea returns a Gitea component whose API is served by a test server returning a single item per
+// page, like a server configured with `MAX_RESPONSE_ITEMS = 1`, with its pagination headers.
+func newPagedTestGitea(t *testing.T) *Gitea {
+	t.Helper()
+
+	items := map[string][]string{
+		"/pulls": {
+			`{"number": 2, "state": "closed", "merged": true, "created_at": "2024-01-01T10:00:00Z", "merged_at": "2024-01-02T10:00:00Z", "user": {"id": 1, "login": "author"}}`,
+			`{"number": 1, "state": "closed", "merged": true, "created_at": "2024-01-01T10:00:00Z", "merged_at": "2024-01-02T10:00:00Z", "user": {"id": 1, "login": "author"}}`,
+		},
+		"/reviews": {
+			`{"user": {"id": 2, "login": "reviewer"}, "state": "REQUEST_CHANGES"}`,
+			`{"user": {"id": 2, "login": "reviewer"}, "state": "APPROVED"}`,
+		},
+		"/files": {
+			`{"filename": "services/api/main.go"}`,
+			`{"filename": "libs/auth/jwt.go"}`,
+		},
+		"/releases": {
+			`{"tag_name": "v2.0.0", "name": "v2.0.0", "prerelease": true, "created_at": "2024-03-01T10:00:00Z", "published_at": "2024-03-01T10:00:00Z", "author": {"login": "author"}}`,
+			`{"tag_name": "v1.0.0", "name": "First release", "body": "Notes", "html_url": "https://gitea.example.com/mirrors/service/releases/tag/v1.0.0", "created_at": "2024-02-01T10:00:00Z", "published_at": "2024-02-02T10:00:00Z", "author": {"login": "author"}}`,
+		},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+
+		for suffix, pageItems := range items {
+			if !strings.HasSuffix(r.URL.Path, suffix) {
+				continue
+			}
+
+			if page < 1 || page > len(pageItems) {
+				w.Write([]byte(`[]`))
+				return
+			}
+
+			// The pull requests are paginated from the `Link` header, the other items from the total count.
+			w.Header().Set("X-Total-Count", strconv.Itoa(len(pageItems)))
+			if suffix == "/pulls" && page < len(pageItems) {
+				w.Header().Set("Link", fmt.Sprintf(`<%s%s?limit=1&page=%d>; rel="next"`, "http://"+r.Host, r.URL.Path, page+1))
+				w.Header().Del("X-Total-Count")
+			}
+
+			fmt.Fprintf(w, "[%s]", pageItems[page-1])
+			return
+		}
+
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	t.Cleanup(server.Close)
+
+	return &Gitea{Client: server.Client(), APIURL: server.URL + "/api/v1"}
+}
+
+func TestAllPullRequestsFollowsPages(t *testing.T) {
+	g := newPagedTestGitea(t)
+
+	params := provider.AllPullRequestsParams{Host: "gitea.example.com", Owner: "mirrors", Repo: "service"}
+
+	result, err := g.AllPullRequests(context.Background(), params)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.PullRequests) != 2 || result.Truncated {
+		t.Fatalf("expected the pull requests of every page, got %d pull requests, truncated: %v", len(result.PullRequests), result.Truncated)
+	}
+
+	pr := result.PullRequests[0]
+	if pr.Number != 2 || result.PullRequests[1].Number != 1 {
+		t.Errorf("expected pull requests #2 and #1, got #%d and #%d", pr.Number, result.PullRequests[1].Number)
+	}
+
+	if fmt.Sprint(pr.Files) != "[services/api/main.go libs/auth/jwt.go]" {
+		t.Errorf("expected the files of every page, got %v", pr.Files)
+	}
+
+	if pr.ReviewDecision != "APPROVED" {
+		t.Errorf("expected the latest review of the last page to approve, got %s", pr.ReviewDecision)
+	}
+
+	g.MaxPages = 1
+	result, err = g.AllPullRequests(context.Background(), params)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.PullRequests) != 1 || !result.Truncated {
+		t.Errorf("expected result truncated to the first page, got %d pull requests, truncated: %v", len(result.PullRequests), result.Truncated)
+	}
+}
+
+func TestAllReleases(t *testing.T) {
+	g := newPagedTestGitea(t)
+
+	result, err := g.AllReleases(context.Background(), provider.AllReleasesParams{Host: "gitea.example.com", Owner: "mirrors", Repo: "service"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.Releases) != 2 || result.Truncated {
+		t.Fatalf("expected the releases of every page, got %d releases, truncated: %v", len(result.Releases), result.Truncated)
+	}
+
+	if release := result.Releases[0]; release.TagName != "v2.0.0" || !release.IsPrerelease {
+		t.Errorf("expected the v2.0.0 pre-release first, got %+v", release)
+	}
+
+	release := result.Releases[1]
+	if release.TagName != "v1.0.0" || release.Name != "First release" || release.Description != "Notes" || release.Host != "gitea.example.com" || release.Author.Login != "author" || release.PublishedAt == nil {
+		t.Errorf("unexpected release: %+v", release)
+	}
+}
@@ -0,0 +1,272 @@
+package github
+
+import (
+	"context"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chris-ramon/golang-scaffolding/config"
+	jwtV5 "github.com/golang-jwt/jwt/v5"
+	githubClient "github.com/google/go-github/github"
+	"golang.org/x/oauth2"
+)
+
+// appJWTExpiry is the expiry of the JWTs authenticating as the GitHub App, GitHub rejects more than 10 minutes.
+const appJWTExpiry = 9 * time.Minute
+
+// installationTokenRefreshMargin is the time before their expiry the installation tokens are refreshed at.
+const installationTokenRefreshMargin = 5 * time.Minute
+
+// missingInstallationTTL is the time the owners without installation are remembered for before the
+// installations are listed again for them.
+const missingInstallationTTL = 5 * time.Minute
+
+// ErrAppNotInstalled is the error of the owners the GitHub App is not installed for.
+var ErrAppNotInstalled = errors.New("GitHub App is not installed")
+
+type ownerContextKey struct{}
+
+// WithOwner returns a context carrying the repository owner the GitHub requests are made for, used to pick
+// the GitHub App installation authenticating them.
+func WithOwner(ctx context.Context, owner string) context.Context {
+	return context.WithValue(ctx, ownerContextKey{}, owner)
+}
+
+// ownerFromContext returns the repository owner of the given context, empty when unset.
+func ownerFromContext(ctx context.Context) string {
+	owner, _ := ctx.Value(ownerContextKey{}).(string)
+	return owner
+}
+
+// App authenticates the GitHub requests as the installations of a GitHub App.
+type App struct {
+	// ID is the GitHub App ID.
+	ID int64
+
+	// Client is the GitHub REST API client authenticated as the GitHub App.
+	Client *githubClient.Client
+
+	signKey *rsa.PrivateKey
+
+	// mu guards the caches below, it is not held across the GitHub requests.
+	mu sync.Mutex
+
+	// installations are the installation IDs by lower-cased account login.
+	installations map[string]int64
+
+	// missingInstallations are the times the installations were last listed without the lower-cased account
+	// login, by login.
+	missingInstallations map[string]time.Time
+
+	// tokens are the installation tokens by installation ID.
+	tokens map[int64]*oauth2.Token
+}
+
+// jwt returns a JWT authenticating as the GitHub App.
+func (a *App) jwt() (string, error) {
+	now := time.Now()
+
+	t := jwtV5.New(jwtV5.GetSigningMethod("RS256"))
+	t.Claims = jwtV5.RegisteredClaims{
+		// Issued in the past to allow for clock drift.
+		IssuedAt:  jwtV5.NewNumericDate(now.Add(-time.Minute)),
+		ExpiresAt: jwtV5.NewNumericDate(now.Add(appJWTExpiry)),
+		Issuer:    strconv.FormatInt(a.ID, 10),
+	}
+
+	return t.SignedString(a.signKey)
+}
+
+// InstallationToken returns the token of the GitHub App installation of the given repository owner, or
+// `ErrAppNotInstalled` when there is none. Tokens are cached and refreshed shortly before they expire.
+func (a *App) InstallationToken(ctx context.Context, owner string) (*oauth2.Token, error) {
+	installationID, err := a.installationID(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+
+	a.mu.Lock()
+	token, ok := a.tokens[installationID]
+	a.mu.Unlock()
+	if ok && time.Until(token.Expiry) > installationTokenRefreshMargin {
+		return token, nil
+	}
+
+	req, err := a.Client.NewRequest(http.MethodPost, fmt.Sprintf("app/installations/%d/access_tokens", installationID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	installationToken := &githubClient.InstallationToken{}
+	if _, err := a.Client.Do(ctx, req, installationToken); err != nil {
+		return nil, fmt.Errorf("failed to create GitHub App installation token of %s: %w", owner, err)
+	}
+
+	token = &oauth2.Token{
+		AccessToken: installationToken.GetToken(),
+		Expiry:      installationToken.GetExpiresAt(),
+	}
+
+	a.mu.Lock()
+	a.tokens[installationID] = token
+	a.mu.Unlock()
+
+	return token, nil
+}
+
+// installationID returns the ID of the GitHub App installation of the given account, the installations are
+// listed again when it is unknown in case the GitHub App was installed since, at most once per
+// `missingInstallationTTL`.
+func (a *App) installationID(ctx context.Context, owner string) (int64, error) {
+	login := strings.ToLower(owner)
+
+	a.mu.Lock()
+	installationID, ok := a.installations[login]
+	listedAt, missing := a.missingInstallations[login]
+	a.mu.Unlock()
+
+	switch {
+	case ok:
+		return installationID, nil
+	case missing && time.Since(listedAt) < missingInstallationTTL:
+		return 0, fmt.Errorf("%w for %s", ErrAppNotInstalled, owner)
+	}
+
+	installations := map[string]int64{}
+	opt := &githubClient.ListOptions{PerPage: 100}
+	for {
+		page, resp, err := a.Client.Apps.ListInstallations(ctx, opt)
+		if err != nil {
+			return 0, fmt.Errorf("failed to list GitHub App installations: %w", err)
+		}
+
+		for _, installation := range page {
+			installations[strings.ToLower(installation.GetAccount().GetLogin())] = installation.GetID()
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for account, id := range installations {
+		a.installations[account] = id
+		delete(a.missingInstallations, account)
+	}
+
+	installationID, ok = installations[login]
+	if !ok {
+		a.missingInstallations[login] = time.Now()
+		return 0, fmt.Errorf("%w for %s", ErrAppNotInstalled, owner)
+	}
+
+	return installationID, nil
+}
+
+// Budget returns the rate limit budget of the requests of the given context, the installation of their owner, see
+// `WithOwner`. Requests without owner, or whose owner has no installation, use the default budget, empty, of the
+// fallback token.
+func (a *App) Budget(ctx context.Context) string {
+	owner := ownerFromContext(ctx)
+	if owner == "" {
+		return ""
+	}
+
+	installationID, err := a.installationID(ctx, owner)
+	if err != nil {
+		return ""
+	}
+
+	return strconv.FormatInt(installationID, 10)
+}
+
+// Transport returns a transport authenticating each request with the installation token of the repository
+// owner of its context, see `WithOwner`. Requests without owner, or whose owner has no installation, use the
+// given fallback token source, they fail when it is nil.
+func (a *App) Transport(base http.RoundTripper, fallback oauth2.TokenSource) http.RoundTripper {
+	return &installationTransport{app: a, base: base, fallback: fallback}
+}
+
+type installationTransport struct {
+	app      *App
+	base     http.RoundTripper
+	fallback oauth2.TokenSource
+}
+
+func (t *installationTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var token *oauth2.Token
+	var err error
+
+	if owner := ownerFromContext(req.Context()); owner != "" {
+		token, err = t.app.InstallationToken(req.Context(), owner)
+		if errors.Is(err, ErrAppNotInstalled) && t.fallback != nil {
+			token, err = t.fallback.Token()
+		}
+	} else if t.fallback != nil {
+		token, err = t.fallback.Token()
+	} else {
+		err = errors.New("missing repository owner to pick the GitHub App installation")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	authReq := req.Clone(req.Context())
+	token.SetAuthHeader(authReq)
+
+	return baseTransport(t.base).RoundTrip(authReq)
+}
+
+// appTransport authenticates the requests as the GitHub App.
+type appTransport struct {
+	app  *App
+	base http.RoundTripper
+}
+
+func (t *appTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	jwt, err := t.app.jwt()
+	if err != nil {
+		return nil, err
+	}
+
+	authReq := req.Clone(req.Context())
+	authReq.Header.Set("Authorization", "Bearer "+jwt)
+
+	return baseTransport(t.base).RoundTrip(authReq)
+}
+
+func baseTransport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		return http.DefaultTransport
+	}
+	return base
+}
+
+// NewApp returns a pointer to the App struct of the GitHub App of the given configuration.
+func NewApp(conf *config.GitHubConfig) (*App, error) {
+	signKey, err := jwtV5.ParseRSAPrivateKeyFromPEM(conf.AppPrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse GitHub App private key: %w", err)
+	}
+
+	app := &App{
+		ID:                   conf.AppID,
+		signKey:              signKey,
+		installations:        map[string]int64{},
+		missingInstallations: map[string]time.Time{},
+		tokens:               map[int64]*oauth2.Token{},
+	}
+	app.Client = githubClient.NewClient(&http.Client{Transport: &appTransport{app: app}})
+
+	return app, nil
+}
@@ -0,0 +1,220 @@
+package github
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/chris-ramon/golang-scaffolding/config"
+	jwtV5 "github.com/golang-jwt/jwt/v5"
+	"golang.org/x/oauth2"
+)
+
+// newTestApp returns a GitHub App whose API is served by a test server issuing installation tokens expiring
+// after the given duration, along with the number of issued tokens and of installations listings.
+func newTestApp(t *testing.T, tokenExpiry time.Duration) (*App, *atomic.Int32, *atomic.Int32) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	issuedTokens := &atomic.Int32{}
+	listings := &atomic.Int32{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		jwt := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		claims := &jwtV5.RegisteredClaims{}
+		_, err := jwtV5.ParseWithClaims(jwt, claims, func(t *jwtV5.Token) (interface{}, error) {
+			return &key.PublicKey, nil
+		})
+		if err != nil || claims.Issuer != "42" {
+			t.Errorf("expected request authenticated as the app, got %q: %v", jwt, err)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/app/installations":
+			listings.Add(1)
+			w.Write([]byte(`[{"id":1,"account":{"login":"graphql-go"}},{"id":2,"account":{"login":"chris-ramon"}}]`))
+		case r.Method == http.MethodPost && strings.HasPrefix(r.URL.Path, "/app/installations/"):
+			n := issuedTokens.Add(1)
+			installationID := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/app/installations/"), "/access_tokens")
+			fmt.Fprintf(w, `{"token":"installation-%s-%d","expires_at":%q}`, installationID, n, time.Now().Add(tokenExpiry).Format(time.RFC3339))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	app, err := NewApp(&config.GitHubConfig{
+		AppID:         42,
+		AppPrivateKey: pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	baseURL, err := url.Parse(server.URL + "/")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	app.Client.BaseURL = baseURL
+
+	return app, issuedTokens, listings
+}
+
+func TestAppInstallationToken(t *testing.T) {
+	app, issuedTokens, listings := newTestApp(t, time.Hour)
+
+	token, err := app.InstallationToken(context.Background(), "GraphQL-Go")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if token.AccessToken != "installation-1-1" {
+		t.Errorf("expected token of the graphql-go installation, got %s", token.AccessToken)
+	}
+
+	if _, err := app.InstallationToken(context.Background(), "graphql-go"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if issuedTokens.Load() != 1 {
+		t.Errorf("expected cached installation token, got %d issued tokens", issuedTokens.Load())
+	}
+
+	token, err = app.InstallationToken(context.Background(), "chris-ramon")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if token.AccessToken != "installation-2-2" {
+		t.Errorf("expected token of the chris-ramon installation, got %s", token.AccessToken)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := app.InstallationToken(context.Background(), "unknown"); !errors.Is(err, ErrAppNotInstalled) {
+			t.Errorf("expected not installed error for an owner without installation, got %v", err)
+		}
+	}
+
+	if listings.Load() != 2 {
+		t.Errorf("expected the owner without installation to be remembered, got %d installations listings", listings.Load())
+	}
+
+	app.missingInstallations["unknown"] = time.Now().Add(-missingInstallationTTL)
+	if _, err := app.InstallationToken(context.Background(), "unknown"); !errors.Is(err, ErrAppNotInstalled) {
+		t.Errorf("expected not installed error for an owner without installation, got %v", err)
+	}
+
+	if listings.Load() != 3 {
+		t.Errorf("expected the installations to be listed again once the owner without installation expired, got %d installations listings", listings.Load())
+	}
+}
+
+func TestAppInstallationTokenRefresh(t *testing.T) {
+	app, issuedTokens, _ := newTestApp(t, installationTokenRefreshMargin-time.Minute)
+
+	for i := 0; i < 2; i++ {
+		if _, err := app.InstallationToken(context.Background(), "graphql-go"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if issuedTokens.Load() != 2 {
+		t.Errorf("expected installation token about to expire to be refreshed, got %d issued tokens", issuedTokens.Load())
+	}
+}
+
+func TestAppBudget(t *testing.T) {
+	app, _, _ := newTestApp(t, time.Hour)
+
+	testCases := []struct {
+		name           string
+		ctx            context.Context
+		expectedBudget string
+	}{
+		{name: "installation of the owner", ctx: WithOwner(context.Background(), "Chris-Ramon"), expectedBudget: "2"},
+		{name: "owner without installation", ctx: WithOwner(context.Background(), "unknown"), expectedBudget: ""},
+		{name: "no owner", ctx: context.Background(), expectedBudget: ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if budget := app.Budget(tc.ctx); budget != tc.expectedBudget {
+				t.Errorf("expected budget %q, got %q", tc.expectedBudget, budget)
+			}
+		})
+	}
+}
+
+func TestAppTransport(t *testing.T) {
+	app, _, _ := newTestApp(t, time.Hour)
+
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+	}))
+	t.Cleanup(server.Close)
+
+	testCases := []struct {
+		name                  string
+		owner                 string
+		fallback              oauth2.TokenSource
+		expectedAuthorization string
+		expectError           bool
+	}{
+		{name: "installation of the owner", owner: "graphql-go", expectedAuthorization: "Bearer installation-1-1"},
+		{name: "fallback without owner", fallback: oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "pat"}), expectedAuthorization: "Bearer pat"},
+		{name: "no fallback without owner", expectError: true},
+		{name: "fallback for an owner without installation", owner: "unknown", fallback: oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "pat"}), expectedAuthorization: "Bearer pat"},
+		{name: "no fallback for an owner without installation", owner: "unknown", expectError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			authorization = ""
+			client := &http.Client{Transport: app.Transport(nil, tc.fallback)}
+
+			ctx := context.Background()
+			if tc.owner != "" {
+				ctx = WithOwner(ctx, tc.owner)
+			}
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost, server.URL, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			resp, err := client.Do(req)
+			if tc.expectError {
+				if err == nil {
+					t.Errorf("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			resp.Body.Close()
+
+			if authorization != tc.expectedAuthorization {
+				t.Errorf("expected authorization %q, got %q", tc.expectedAuthorization, authorization)
+			}
+		})
+	}
+}
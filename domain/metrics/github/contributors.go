@@ -0,0 +1,119 @@
+package github
+
+import (
+	"strings"
+
+	"github.com/chris-ramon/golang-scaffolding/domain/metrics/types"
+	"github.com/chris-ramon/golang-scaffolding/pkg/trailers"
+)
+
+// ContributorsParams represents the Contributors parameters.
+type ContributorsParams struct {
+	// Host is the repository host, github.com when empty, whose profiles are linked for the co-authors
+	// without participation.
+	Host string
+
+	// Author is the pull request author.
+	Author Author
+
+	// Participants are the pull request participants.
+	Participants ParticipantsNodes
+
+	// Reviews are the pull request reviews.
+	Reviews ReviewsNodes
+
+	// Commits are the pull request commits.
+	Commits CommitsNodes
+}
+
+// Contributors returns the pull request contributors tagged by their role: the author first,
+// followed by the participants as reviewers or commenters, and the `Co-authored-by` commit
+// trailers as co-authors.
+func Contributors(params ContributorsParams) types.Contributors {
+	reviewers := map[string]bool{}
+	for _, review := range params.Reviews {
+		reviewers[string(review.Author.Login)] = true
+	}
+
+	contributors := types.Contributors{}
+	indexByLogin := map[string]int{}
+
+	add := func(c types.Contributor) {
+		if c.Login != "" {
+			indexByLogin[strings.ToLower(c.Login)] = len(contributors)
+		}
+		contributors = append(contributors, c)
+	}
+
+	add(types.Contributor{
+		Login:      string(params.Author.Login),
+		ProfileURL: string(params.Author.URL),
+		Role:       types.AuthorContributorRole,
+	})
+
+	for _, participant := range params.Participants {
+		if participant.Login == params.Author.Login {
+			author := &contributors[0]
+			if author.ProfileURL == "" {
+				author.ProfileURL = string(participant.URL)
+			}
+			author.ID = string(participant.ID)
+			continue
+		}
+
+		role := types.CommenterContributorRole
+		if reviewers[string(participant.Login)] {
+			role = types.ReviewerContributorRole
+		}
+
+		add(types.Contributor{
+			ProfileURL: string(participant.URL),
+			ID:         string(participant.ID),
+			Login:      string(participant.Login),
+			Role:       role,
+		})
+	}
+
+	host := params.Host
+	if host == "" {
+		host = DefaultHost
+	}
+
+	seenEmails := map[string]bool{}
+
+	for _, commit := range params.Commits {
+		for _, coAuthor := range trailers.CoAuthors(string(commit.Commit.Message)) {
+			email := strings.ToLower(coAuthor.Email)
+			if seenEmails[email] {
+				continue
+			}
+			seenEmails[email] = true
+
+			login := coAuthor.Login()
+
+			if idx, ok := indexByLogin[strings.ToLower(login)]; ok && login != "" {
+				// Co-authoring outranks reviewing or commenting, the author keeps its role.
+				if contributors[idx].Role != types.AuthorContributorRole {
+					contributors[idx].Role = types.CoAuthorContributorRole
+				}
+				contributors[idx].Name = coAuthor.Name
+				contributors[idx].Email = coAuthor.Email
+				continue
+			}
+
+			c := types.Contributor{
+				Login: login,
+				Name:  coAuthor.Name,
+				Email: coAuthor.Email,
+				Role:  types.CoAuthorContributorRole,
+			}
+			if login != "" {
+				c.ProfileURL = OwnerRef{Host: host, Owner: login}.URL()
+			}
+
+			add(c)
+		}
+	}
+
+	return contributors
+}
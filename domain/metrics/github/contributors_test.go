@@ -0,0 +1,49 @@
+package github
+
+import (
+	"testing"
+
+	"github.com/chris-ramon/golang-scaffolding/domain/metrics/types"
+)
+
+func TestContributors(t *testing.T) {
+	testCases := []struct {
+		name               string
+		host               string
+		expectedProfileURL string
+	}{
+		{name: "github.com by default", expectedProfileURL: "https://github.com/pair"},
+		{name: "enterprise host", host: "github.example.com", expectedProfileURL: "https://github.example.com/pair"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			contributors := Contributors(ContributorsParams{
+				Host:   tc.host,
+				Author: Author{Login: "author", URL: "https://github.example.com/author"},
+				Participants: ParticipantsNodes{
+					{Login: "author"},
+					{Login: "reviewer", URL: "https://github.example.com/reviewer"},
+				},
+				Reviews: ReviewsNodes{{Author: Author{Login: "reviewer"}}},
+				Commits: CommitsNodes{
+					{Commit: Commit{Message: "Add feature\n\nCo-authored-by: Pair <1+pair@users.noreply.github.com>"}},
+				},
+			})
+
+			expectedRoles := []types.ContributorRole{types.AuthorContributorRole, types.ReviewerContributorRole, types.CoAuthorContributorRole}
+			if len(contributors) != len(expectedRoles) {
+				t.Fatalf("expected %d contributors, got %+v", len(expectedRoles), contributors)
+			}
+			for i, role := range expectedRoles {
+				if contributors[i].Role != role {
+					t.Errorf("expected contributor %d role %s, got %s", i, role, contributors[i].Role)
+				}
+			}
+
+			if contributors[2].ProfileURL != tc.expectedProfileURL {
+				t.Errorf("expected the co-author profile %s, got %s", tc.expectedProfileURL, contributors[2].ProfileURL)
+			}
+		})
+	}
+}
@@ -2,52 +2,83 @@ package github
 
 import (
 	"context"
-	"os"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"time"
 
 	"github.com/shurcooL/githubv4"
 	"golang.org/x/oauth2"
 
+	"github.com/chris-ramon/golang-scaffolding/config"
+	"github.com/chris-ramon/golang-scaffolding/domain/metrics/provider"
 	"github.com/chris-ramon/golang-scaffolding/domain/metrics/types"
+	"github.com/chris-ramon/golang-scaffolding/pkg/httprecorder"
 )
 
 // GitHubClient defines the interface for GitHub operations.
 type GitHubClient interface {
-	AllPullRequests(params AllPullRequestsParams) (AllPullRequestsQuery, error)
-	PullRequestContributors(params PullRequestContributorsParams) (PullRequestContributorsQuery, error)
-	Query(query any) error
+	AllPullRequests(ctx context.Context, params AllPullRequestsParams) (AllPullRequestsQuery, error)
+	PullRequests(ctx context.Context, params PullRequestsParams) ([]PullRequestResult, error)
+	AllIssues(ctx context.Context, params AllIssuesParams) (AllIssuesQuery, error)
+	AllReleases(ctx context.Context, params AllReleasesParams) (AllReleasesQuery, error)
+	AllRepositories(ctx context.Context, params AllRepositoriesParams) (AllRepositoriesQuery, error)
+	RateLimit(ctx context.Context) (types.RateLimit, error)
+	Query(ctx context.Context, query any, variables map[string]any) error
 }
 
 // GitHub represents the GitHub component.
 type GitHub struct {
 	// Client is the GitHub client.
 	Client *githubv4.Client
-}
 
-// PullRequestContributorsParams represents the PullRequestContributors parameters.
-type PullRequestContributorsParams struct {
-	// PullRequest is the pull request parameter.
-	PullRequest types.PullRequest
-}
+	// MaxPages is the default maximum number of pages to fetch when paginating, zero means unbounded.
+	MaxPages int
+
+	// Syncs persists the synced pull requests and the progress of the interrupted syncs, `AllPullRequests`
+	// fetches every page of the given states instead of syncing incrementally when nil.
+	Syncs SyncStore
+
+	// RateLimiter is the rate limiter shared by the GitHub requests, rate limiting is disabled when nil.
+	RateLimiter *RateLimiter
+
+	// Timeout is the timeout of each GitHub request, zero means no timeout.
+	Timeout time.Duration
 
-type PullRequestContributorsQuery struct {
-	Repository Repository `graphql:"repository(owner: $repositoryOwner, name: $repositoryName)"`
+	// syncLocks are the locks serializing the syncs of each repository, by sync store key.
+	syncLocks sync.Map
 }
 
-type Repository struct {
-	PullRequests PullRequests `graphql:"pullRequests(headRefName: $pullRequestsHeadRefName, first: $pullRequestsFirst)"`
+// PullRequestParams represents a pull request of the PullRequests parameters.
+type PullRequestParams struct {
+	// Owner is the repository owner.
+	Owner string
+	// Repo is the repository name.
+	Repo string
+	// Number is the pull request number.
+	Number int
 }
 
-type PullRequests struct {
-	Nodes PullRequestsNodes
+// PullRequestsParams represents the PullRequests parameters.
+type PullRequestsParams struct {
+	// PullRequests are the pull requests to fetch, of a single owner when authenticated as a GitHub App.
+	PullRequests []PullRequestParams
 }
 
-type PullRequestsNodes []PullRequestsNode
+// PullRequestResult represents a pull request of the PullRequests result.
+type PullRequestResult struct {
+	// PullRequest is the fetched pull request, zero when Err is set.
+	PullRequest AllPullRequestsNode
 
-type PullRequestsNode struct {
-	State        githubv4.String
-	Participants Participants `graphql:"participants(first: $participantsFirst)"`
+	// Err is the error fetching the pull request.
+	Err error
 }
 
+// ErrPullRequestNotFound is the error of the pull requests missing from the PullRequests result.
+var ErrPullRequestNotFound = provider.ErrPullRequestNotFound
+
 type Participants struct {
 	Nodes ParticipantsNodes
 }
@@ -60,6 +91,34 @@ type ParticipantsNode struct {
 
 type ParticipantsNodes []ParticipantsNode
 
+// Commits represents the commits of a pull request.
+type Commits struct {
+	Nodes    CommitsNodes
+	PageInfo PageInfo `graphql:"pageInfo"`
+}
+
+type CommitsNodes []CommitsNode
+
+type CommitsNode struct {
+	Commit Commit
+}
+
+type Commit struct {
+	Message githubv4.String
+}
+
+// Reviews represents the reviews of a pull request.
+type Reviews struct {
+	Nodes    ReviewsNodes
+	PageInfo PageInfo `graphql:"pageInfo"`
+}
+
+type ReviewsNodes []ReviewsNode
+
+type ReviewsNode struct {
+	Author Author `graphql:"author"`
+}
+
 // PageInfo represents pagination information from GitHub GraphQL API.
 type PageInfo struct {
 	HasNextPage githubv4.Boolean `graphql:"hasNextPage"`
@@ -72,14 +131,23 @@ type AllPullRequestsParams struct {
 	Owner string
 	// Repo is the repository name.
 	Repo string
+
+	// States are the pull request states to fetch, defaults to merged pull requests.
+	States []types.PullRequestState
+
+	// MaxPages is the maximum number of pages to fetch, defaults to the GitHub component maximum.
+	MaxPages int
 }
 
 type AllPullRequestsQuery struct {
 	Repository AllPullRequestsRepository `graphql:"repository(owner: $repositoryOwner, name: $repositoryName)"`
+	RateLimit  RateLimit                 `graphql:"rateLimit"`
 }
 
+func (q *AllPullRequestsQuery) rateLimit() RateLimit { return q.RateLimit }
+
 type AllPullRequestsRepository struct {
-	PullRequests AllPullRequestsPullRequests `graphql:"pullRequests(states: MERGED, first: $pullRequestsFirst, after: $pullRequestsAfter, orderBy: {field: CREATED_AT, direction: DESC})"`
+	PullRequests AllPullRequestsPullRequests `graphql:"pullRequests(states: $pullRequestsStates, first: $pullRequestsFirst, after: $pullRequestsAfter, orderBy: {field: CREATED_AT, direction: DESC})"`
 }
 
 type AllPullRequestsPullRequests struct {
@@ -96,97 +164,814 @@ type AllPullRequestsNode struct {
 	Body      githubv4.String
 	CreatedAt githubv4.DateTime
 	MergedAt  githubv4.DateTime
+	ClosedAt  githubv4.DateTime
+	UpdatedAt githubv4.DateTime
+	State     githubv4.PullRequestState
+	IsDraft   githubv4.Boolean
 	HeadRef   struct {
 		Name githubv4.String
 	}
-	Participants Participants `graphql:"participants(first: $participantsFirst)"`
-	Author       Author       `graphql:"author"`
+	HeadRefName             githubv4.String
+	BaseRefName             githubv4.String
+	Participants            Participants                       `graphql:"participants(first: $participantsFirst)"`
+	Author                  Author                             `graphql:"author"`
+	Commits                 Commits                            `graphql:"commits(first: $commitsFirst)"`
+	Reviews                 Reviews                            `graphql:"reviews(first: $reviewsFirst)"`
+	ReviewDecision          githubv4.PullRequestReviewDecision `graphql:"reviewDecision"`
+	ReviewRequests          ReviewRequests                     `graphql:"reviewRequests(first: $reviewRequestsFirst)"`
+	LastCommit              LastCommit                         `graphql:"lastCommit: commits(last: 1)"`
+	ClosingIssuesReferences ClosingIssuesReferences            `graphql:"closingIssuesReferences(first: $closingIssuesReferencesFirst)"`
+	Labels                  Labels                             `graphql:"labels(first: $labelsFirst)"`
+	Milestone               Milestone                          `graphql:"milestone"`
+	Assignees               Assignees                          `graphql:"assignees(first: $assigneesFirst)"`
+	Files                   Files                              `graphql:"files(first: $filesFirst)"`
+}
+
+// Files represents the files changed by a pull request.
+type Files struct {
+	Nodes    FilesNodes
+	PageInfo PageInfo `graphql:"pageInfo"`
+}
+
+type FilesNodes []FilesNode
+
+type FilesNode struct {
+	Path githubv4.String
+}
+
+// PullRequestFilesQuery represents the query of the files changed by a pull request, from a given cursor.
+type PullRequestFilesQuery struct {
+	Repository PullRequestFilesRepository `graphql:"repository(owner: $repositoryOwner, name: $repositoryName)"`
+	RateLimit  RateLimit                  `graphql:"rateLimit"`
+}
+
+func (q *PullRequestFilesQuery) rateLimit() RateLimit { return q.RateLimit }
+
+type PullRequestFilesRepository struct {
+	PullRequest struct {
+		Files Files `graphql:"files(first: $filesFirst, after: $filesAfter)"`
+	} `graphql:"pullRequest(number: $pullRequestNumber)"`
+}
+
+// PullRequestCommitsQuery represents the query of the commits of a pull request, from a given cursor.
+type PullRequestCommitsQuery struct {
+	Repository PullRequestCommitsRepository `graphql:"repository(owner: $repositoryOwner, name: $repositoryName)"`
+	RateLimit  RateLimit                    `graphql:"rateLimit"`
+}
+
+func (q *PullRequestCommitsQuery) rateLimit() RateLimit { return q.RateLimit }
+
+type PullRequestCommitsRepository struct {
+	PullRequest struct {
+		Commits Commits `graphql:"commits(first: $commitsFirst, after: $commitsAfter)"`
+	} `graphql:"pullRequest(number: $pullRequestNumber)"`
+}
+
+// PullRequestReviewsQuery represents the query of the reviews of a pull request, from a given cursor.
+type PullRequestReviewsQuery struct {
+	Repository PullRequestReviewsRepository `graphql:"repository(owner: $repositoryOwner, name: $repositoryName)"`
+	RateLimit  RateLimit                    `graphql:"rateLimit"`
+}
+
+func (q *PullRequestReviewsQuery) rateLimit() RateLimit { return q.RateLimit }
+
+type PullRequestReviewsRepository struct {
+	PullRequest struct {
+		Reviews Reviews `graphql:"reviews(first: $reviewsFirst, after: $reviewsAfter)"`
+	} `graphql:"pullRequest(number: $pullRequestNumber)"`
+}
+
+// Labels represents the labels of a pull request.
+type Labels struct {
+	Nodes LabelsNodes
+}
+
+type LabelsNodes []LabelsNode
+
+type LabelsNode struct {
+	Name githubv4.String
+}
+
+// Milestone represents the milestone of a pull request, zero when there is none.
+type Milestone struct {
+	Title githubv4.String
+}
+
+// Assignees represents the users assigned to a pull request.
+type Assignees struct {
+	Nodes AssigneesNodes
+}
+
+type AssigneesNodes []AssigneesNode
+
+type AssigneesNode struct {
+	Login githubv4.String
+}
+
+// ClosingIssuesReferences represents the issues a pull request closes when merged.
+type ClosingIssuesReferences struct {
+	Nodes IssuesNodes
+}
+
+type IssuesNodes []IssuesNode
+
+type IssuesNode struct {
+	Number     githubv4.Int
+	URL        githubv4.String
+	Title      githubv4.String
+	Body       githubv4.String
+	State      githubv4.IssueState
+	CreatedAt  githubv4.DateTime
+	ClosedAt   githubv4.DateTime
+	Author     Author `graphql:"author"`
+	Repository IssueRepository
+}
+
+// IssueRepository represents the repository of an issue, which can differ from the repository of the pull
+// requests closing it.
+type IssueRepository struct {
+	Name  githubv4.String
+	Owner struct {
+		Login githubv4.String
+	}
+}
+
+// AllIssuesParams represents the AllIssues parameters.
+type AllIssuesParams struct {
+	// Owner is the repository owner.
+	Owner string
+	// Repo is the repository name.
+	Repo string
+
+	// States are the issue states to fetch, defaults to every state.
+	States []types.IssueState
+
+	// MaxPages is the maximum number of pages to fetch, defaults to the GitHub component maximum.
+	MaxPages int
+}
+
+type AllIssuesQuery struct {
+	Repository AllIssuesRepository `graphql:"repository(owner: $repositoryOwner, name: $repositoryName)"`
+	RateLimit  RateLimit           `graphql:"rateLimit"`
+}
+
+func (q *AllIssuesQuery) rateLimit() RateLimit { return q.RateLimit }
+
+type AllIssuesRepository struct {
+	Issues AllIssuesIssues `graphql:"issues(states: $issuesStates, first: $issuesFirst, after: $issuesAfter, orderBy: {field: CREATED_AT, direction: DESC})"`
+}
+
+type AllIssuesIssues struct {
+	Nodes    IssuesNodes
+	PageInfo PageInfo `graphql:"pageInfo"`
+}
+
+// AllReleasesParams represents the AllReleases parameters.
+type AllReleasesParams struct {
+	// Owner is the repository owner.
+	Owner string
+	// Repo is the repository name.
+	Repo string
+
+	// MaxPages is the maximum number of pages to fetch, defaults to the GitHub component maximum.
+	MaxPages int
+}
+
+type AllReleasesQuery struct {
+	Repository AllReleasesRepository `graphql:"repository(owner: $repositoryOwner, name: $repositoryName)"`
+	RateLimit  RateLimit             `graphql:"rateLimit"`
+}
+
+func (q *AllReleasesQuery) rateLimit() RateLimit { return q.RateLimit }
+
+type AllReleasesRepository struct {
+	Releases AllReleasesReleases `graphql:"releases(first: $releasesFirst, after: $releasesAfter, orderBy: {field: CREATED_AT, direction: DESC})"`
+}
+
+type AllReleasesReleases struct {
+	Nodes    ReleasesNodes
+	PageInfo PageInfo `graphql:"pageInfo"`
+}
+
+type ReleasesNodes []ReleasesNode
+
+type ReleasesNode struct {
+	TagName      githubv4.String
+	Name         githubv4.String
+	Description  githubv4.String
+	URL          githubv4.String
+	IsDraft      githubv4.Boolean
+	IsPrerelease githubv4.Boolean
+	CreatedAt    githubv4.DateTime
+	PublishedAt  githubv4.DateTime
+	Author       Author `graphql:"author"`
+}
+
+// AllRepositoriesParams represents the AllRepositories parameters.
+type AllRepositoriesParams struct {
+	// Owner is the login of the organization or user owning the repositories.
+	Owner string
+
+	// MaxPages is the maximum number of pages to fetch, defaults to the GitHub component maximum.
+	MaxPages int
+}
+
+type AllRepositoriesQuery struct {
+	RepositoryOwner AllRepositoriesRepositoryOwner `graphql:"repositoryOwner(login: $repositoryOwner)"`
+	RateLimit       RateLimit                      `graphql:"rateLimit"`
+}
+
+func (q *AllRepositoriesQuery) rateLimit() RateLimit { return q.RateLimit }
+
+type AllRepositoriesRepositoryOwner struct {
+	Repositories AllRepositoriesRepositories `graphql:"repositories(first: $repositoriesFirst, after: $repositoriesAfter, orderBy: {field: NAME, direction: ASC})"`
+}
+
+type AllRepositoriesRepositories struct {
+	Nodes    RepositoriesNodes
+	PageInfo PageInfo `graphql:"pageInfo"`
+}
+
+type RepositoriesNodes []RepositoriesNode
+
+// RepositoriesNode represents a repository of an owner.
+type RepositoriesNode struct {
+	Name       githubv4.String
+	URL        githubv4.String
+	IsArchived githubv4.Boolean
+}
+
+// ReviewRequests represents the pending review requests of a pull request.
+type ReviewRequests struct {
+	Nodes ReviewRequestsNodes
+}
+
+type ReviewRequestsNodes []ReviewRequestsNode
+
+type ReviewRequestsNode struct {
+	RequestedReviewer RequestedReviewer
+}
+
+// RequestedReviewer represents a user or a team requested to review a pull request.
+type RequestedReviewer struct {
+	User struct {
+		Login githubv4.String
+	} `graphql:"... on User"`
+	Team struct {
+		Slug githubv4.String
+	} `graphql:"... on Team"`
+}
+
+// Name returns the user login or the team slug of the requested reviewer.
+func (r RequestedReviewer) Name() string {
+	if r.User.Login != "" {
+		return string(r.User.Login)
+	}
+
+	return string(r.Team.Slug)
+}
+
+// LastCommit represents the last commit of a pull request.
+type LastCommit struct {
+	Nodes LastCommitNodes
+}
+
+type LastCommitNodes []LastCommitNode
+
+type LastCommitNode struct {
+	Commit struct {
+		StatusCheckRollup struct {
+			State githubv4.StatusState
+		}
+	}
+}
+
+// CIStatus returns the combined CI status of the last commit, empty when there are no checks.
+func (c LastCommit) CIStatus() string {
+	if len(c.Nodes) == 0 {
+		return ""
+	}
+
+	return string(c.Nodes[0].Commit.StatusCheckRollup.State)
 }
 
 type Author struct {
 	Login githubv4.String
+	URL   githubv4.String
 }
 
-// AllPullRequests fetches all merged pull requests from a repository with pagination support.
-// It will iterate through up to 10 pages to retrieve all pull requests.
-func (gh *GitHub) AllPullRequests(params AllPullRequestsParams) (AllPullRequestsQuery, error) {
+// AllPullRequests fetches all pull requests of the given states from a repository with pagination support.
+// It iterates through all the pages unless a maximum number of pages is set, in which case the returned
+// page info reports whether the result is truncated. When a sync store is set, only the pull requests updated
+// since the last sync are fetched, an interrupted sync resumes from its last page, and the result is read from
+// the synced copy of the repository.
+func (gh *GitHub) AllPullRequests(ctx context.Context, params AllPullRequestsParams) (AllPullRequestsQuery, error) {
+	ctx = WithOwner(ctx, params.Owner)
 	finalQuery := AllPullRequestsQuery{}
-	var allNodes AllPullRequestsNodes
 
+	states := []githubv4.PullRequestState{}
+	for _, state := range params.States {
+		states = append(states, githubv4.PullRequestState(state))
+	}
+	if len(states) == 0 {
+		states = append(states, githubv4.PullRequestStateMerged)
+	}
+
+	if gh.Syncs != nil {
+		return gh.allPullRequestsFromSync(ctx, params, states)
+	}
+
+	maxPages := params.MaxPages
+	if maxPages <= 0 {
+		maxPages = gh.MaxPages
+	}
+
+	nodes := AllPullRequestsNodes{}
 	var cursor *githubv4.String
-	maxIterations := 10
+	hasNextPage := true
+
+	for pages := 0; hasNextPage && (maxPages <= 0 || pages < maxPages); pages++ {
+		// Stops paginating as soon as the request is cancelled.
+		if err := ctx.Err(); err != nil {
+			return finalQuery, err
+		}
 
-	for i := 0; i < maxIterations; i++ {
 		query := AllPullRequestsQuery{}
 
 		variables := map[string]interface{}{
-			"repositoryOwner":   githubv4.String(params.Owner),
-			"repositoryName":    githubv4.String(params.Repo),
-			"pullRequestsFirst": githubv4.Int(100),
-			"participantsFirst": githubv4.Int(100),
-			"pullRequestsAfter": cursor,
+			"repositoryOwner":              githubv4.String(params.Owner),
+			"repositoryName":               githubv4.String(params.Repo),
+			"pullRequestsFirst":            githubv4.Int(100),
+			"participantsFirst":            githubv4.Int(100),
+			"commitsFirst":                 githubv4.Int(100),
+			"reviewsFirst":                 githubv4.Int(100),
+			"reviewRequestsFirst":          githubv4.Int(100),
+			"pullRequestsAfter":            cursor,
+			"pullRequestsStates":           states,
+			"closingIssuesReferencesFirst": githubv4.Int(100),
+			"labelsFirst":                  githubv4.Int(100),
+			"assigneesFirst":               githubv4.Int(100),
+			"filesFirst":                   githubv4.Int(100),
 		}
 
-		err := gh.Client.Query(context.Background(), &query, variables)
+		err := gh.Query(ctx, &query, variables)
 		if err != nil {
 			return finalQuery, err
 		}
+		finalQuery.RateLimit = query.RateLimit
+
+		if err := gh.completeConnections(ctx, params.Owner, params.Repo, query.Repository.PullRequests.Nodes); err != nil {
+			return finalQuery, err
+		}
 
 		// Append nodes from this page to our collection
-		allNodes = append(allNodes, query.Repository.PullRequests.Nodes...)
+		nodes = append(nodes, query.Repository.PullRequests.Nodes...)
 
-		// Check if there are more pages
-		if !query.Repository.PullRequests.PageInfo.HasNextPage {
-			break
-		}
+		hasNextPage = bool(query.Repository.PullRequests.PageInfo.HasNextPage)
 
 		// Set cursor for next iteration
 		next := query.Repository.PullRequests.PageInfo.EndCursor
 		cursor = &next
 	}
 
-	// Build the final result with all collected nodes
-	finalQuery.Repository.PullRequests.Nodes = allNodes
+	// Build the final result with all collected nodes, a next page means the result was truncated
+	finalQuery.Repository.PullRequests.Nodes = nodes
 	finalQuery.Repository.PullRequests.PageInfo = PageInfo{
-		HasNextPage: githubv4.Boolean(false), // We've collected all available pages
-		EndCursor:   githubv4.String(""),
+		HasNextPage: githubv4.Boolean(hasNextPage),
+	}
+	if hasNextPage && cursor != nil {
+		finalQuery.Repository.PullRequests.PageInfo.EndCursor = *cursor
 	}
 
 	return finalQuery, nil
 }
 
-// PullRequestContributors searches and returns the contributors of the given pull request.
-func (gh *GitHub) PullRequestContributors(params PullRequestContributorsParams) (PullRequestContributorsQuery, error) {
-	query := PullRequestContributorsQuery{}
+// PullRequests fetches the given pull requests in a single GraphQL request, with the same fields as the pull
+// requests of `AllPullRequests`. Each pull request is an aliased `pullRequest(number:)` field of its repository,
+// so the pull requests can span repositories. The results are in the order of the given pull requests and
+// report the pull requests that could not be fetched, the returned error is set when nothing was fetched.
+func (gh *GitHub) PullRequests(ctx context.Context, params PullRequestsParams) ([]PullRequestResult, error) {
+	if len(params.PullRequests) == 0 {
+		return []PullRequestResult{}, nil
+	}
+
+	ctx = WithOwner(ctx, params.PullRequests[0].Owner)
 
 	variables := map[string]interface{}{
-		"repositoryOwner":         githubv4.String(params.PullRequest.Owner),
-		"repositoryName":          githubv4.String(params.PullRequest.Repo),
-		"participantsFirst":       githubv4.Int(100),
-		"pullRequestsHeadRefName": githubv4.String(params.PullRequest.HeadRefName),
-		"pullRequestsFirst":       githubv4.Int(100),
+		"participantsFirst":            githubv4.Int(100),
+		"commitsFirst":                 githubv4.Int(100),
+		"reviewsFirst":                 githubv4.Int(100),
+		"reviewRequestsFirst":          githubv4.Int(100),
+		"closingIssuesReferencesFirst": githubv4.Int(100),
+		"labelsFirst":                  githubv4.Int(100),
+		"assigneesFirst":               githubv4.Int(100),
+		"filesFirst":                   githubv4.Int(100),
 	}
 
-	result := gh.Client.Query(context.Background(), &query, variables)
+	// The query is built at runtime since each pull request needs its own aliased field.
+	fields := []reflect.StructField{}
+	for i, pr := range params.PullRequests {
+		variables[fmt.Sprintf("repositoryOwner%d", i)] = githubv4.String(pr.Owner)
+		variables[fmt.Sprintf("repositoryName%d", i)] = githubv4.String(pr.Repo)
+		variables[fmt.Sprintf("pullRequestNumber%d", i)] = githubv4.Int(pr.Number)
+
+		repositoryType := reflect.StructOf([]reflect.StructField{{
+			Name: "PullRequest",
+			Type: reflect.TypeOf(AllPullRequestsNode{}),
+			Tag:  reflect.StructTag(fmt.Sprintf(`graphql:"pullRequest(number: $pullRequestNumber%d)"`, i)),
+		}})
+
+		fields = append(fields, reflect.StructField{
+			Name: fmt.Sprintf("PullRequest%d", i),
+			Type: repositoryType,
+			Tag:  reflect.StructTag(fmt.Sprintf(`graphql:"pullRequest%d: repository(owner: $repositoryOwner%d, name: $repositoryName%d)"`, i, i, i)),
+		})
+	}
+	fields = append(fields, reflect.StructField{
+		Name: "RateLimit",
+		Type: reflect.TypeOf(RateLimit{}),
+		Tag:  `graphql:"rateLimit"`,
+	})
+
+	query := reflect.New(reflect.StructOf(fields))
+
+	// The data of the found pull requests is decoded even when others fail.
+	queryErr := gh.Query(ctx, query.Interface(), variables)
+
+	results := []PullRequestResult{}
+	found := false
+
+	for i := range params.PullRequests {
+		node := query.Elem().Field(i).Field(0).Interface().(AllPullRequestsNode)
+
+		if node.Number == 0 {
+			err := ErrPullRequestNotFound
+			if queryErr != nil {
+				err = fmt.Errorf("%w: %w", ErrPullRequestNotFound, queryErr)
+			}
+			results = append(results, PullRequestResult{Err: err})
+			continue
+		}
 
-	return query, result
+		nodes := AllPullRequestsNodes{node}
+		if err := gh.completeConnections(ctx, params.PullRequests[i].Owner, params.PullRequests[i].Repo, nodes); err != nil {
+			results = append(results, PullRequestResult{Err: err})
+			continue
+		}
+
+		found = true
+		results = append(results, PullRequestResult{PullRequest: nodes[0]})
+	}
+
+	if !found && queryErr != nil {
+		return nil, queryErr
+	}
+
+	gh.updateRateLimit(ctx, query.Elem().FieldByName("RateLimit").Interface().(RateLimit))
+
+	return results, nil
 }
 
-// Query executes and returns the given query.
-func (gh *GitHub) Query(query any) error {
-	return gh.Client.Query(context.Background(), &query, nil)
+// completeConnections fetches the files, commits and reviews of the given pull requests of a repository left
+// out of the first page of their connections, one query per page, so that large pull requests keep every
+// changed file and every co-author and reviewer.
+func (gh *GitHub) completeConnections(ctx context.Context, owner string, repo string, nodes AllPullRequestsNodes) error {
+	for i := range nodes {
+		node := &nodes[i]
+
+		variables := func(connection string, cursor githubv4.String) map[string]interface{} {
+			return map[string]interface{}{
+				"repositoryOwner":    githubv4.String(owner),
+				"repositoryName":     githubv4.String(repo),
+				"pullRequestNumber":  node.Number,
+				connection + "First": githubv4.Int(100),
+				connection + "After": &cursor,
+			}
+		}
+
+		for node.Files.PageInfo.HasNextPage {
+			query := PullRequestFilesQuery{}
+			if err := gh.Query(ctx, &query, variables("files", node.Files.PageInfo.EndCursor)); err != nil {
+				return err
+			}
+
+			node.Files.Nodes = append(node.Files.Nodes, query.Repository.PullRequest.Files.Nodes...)
+			node.Files.PageInfo = query.Repository.PullRequest.Files.PageInfo
+		}
+
+		for node.Commits.PageInfo.HasNextPage {
+			query := PullRequestCommitsQuery{}
+			if err := gh.Query(ctx, &query, variables("commits", node.Commits.PageInfo.EndCursor)); err != nil {
+				return err
+			}
+
+			node.Commits.Nodes = append(node.Commits.Nodes, query.Repository.PullRequest.Commits.Nodes...)
+			node.Commits.PageInfo = query.Repository.PullRequest.Commits.PageInfo
+		}
+
+		for node.Reviews.PageInfo.HasNextPage {
+			query := PullRequestReviewsQuery{}
+			if err := gh.Query(ctx, &query, variables("reviews", node.Reviews.PageInfo.EndCursor)); err != nil {
+				return err
+			}
+
+			node.Reviews.Nodes = append(node.Reviews.Nodes, query.Repository.PullRequest.Reviews.Nodes...)
+			node.Reviews.PageInfo = query.Repository.PullRequest.Reviews.PageInfo
+		}
+	}
+
+	return nil
 }
 
-// NewGitHub returns a pointer to the GitHub struct.
-func NewGitHub() *GitHub {
-	github := &GitHub{}
+// AllIssues fetches the issues of the given states from a repository, most recently created first, along with
+// the pull requests. It iterates through all the pages unless a maximum number of pages is set, in which case
+// the returned page info reports whether the result is truncated.
+func (gh *GitHub) AllIssues(ctx context.Context, params AllIssuesParams) (AllIssuesQuery, error) {
+	ctx = WithOwner(ctx, params.Owner)
+	finalQuery := AllIssuesQuery{}
+
+	states := []githubv4.IssueState{}
+	for _, state := range params.States {
+		states = append(states, githubv4.IssueState(state))
+	}
+	if len(states) == 0 {
+		states = append(states, githubv4.IssueStateOpen, githubv4.IssueStateClosed)
+	}
+
+	maxPages := params.MaxPages
+	if maxPages <= 0 {
+		maxPages = gh.MaxPages
+	}
+
+	nodes := IssuesNodes{}
+	var cursor *githubv4.String
+	hasNextPage := true
+
+	for pages := 0; hasNextPage && (maxPages <= 0 || pages < maxPages); pages++ {
+		if err := ctx.Err(); err != nil {
+			return finalQuery, err
+		}
 
-	src := oauth2.StaticTokenSource(
-		&oauth2.Token{AccessToken: os.Getenv("GITHUB_TOKEN")},
-	)
-	httpClient := oauth2.NewClient(context.Background(), src)
+		query := AllIssuesQuery{}
 
+		variables := map[string]interface{}{
+			"repositoryOwner": githubv4.String(params.Owner),
+			"repositoryName":  githubv4.String(params.Repo),
+			"issuesFirst":     githubv4.Int(100),
+			"issuesAfter":     cursor,
+			"issuesStates":    states,
+		}
+
+		if err := gh.Query(ctx, &query, variables); err != nil {
+			return finalQuery, err
+		}
+		finalQuery.RateLimit = query.RateLimit
+
+		nodes = append(nodes, query.Repository.Issues.Nodes...)
+		hasNextPage = bool(query.Repository.Issues.PageInfo.HasNextPage)
+
+		next := query.Repository.Issues.PageInfo.EndCursor
+		cursor = &next
+	}
+
+	finalQuery.Repository.Issues.Nodes = nodes
+	finalQuery.Repository.Issues.PageInfo = PageInfo{
+		HasNextPage: githubv4.Boolean(hasNextPage),
+	}
+	if hasNextPage && cursor != nil {
+		finalQuery.Repository.Issues.PageInfo.EndCursor = *cursor
+	}
+
+	return finalQuery, nil
+}
+
+// AllReleases fetches the releases of a repository, most recently created first. It iterates through all the
+// pages unless a maximum number of pages is set, in which case the returned page info reports whether the
+// result is truncated.
+func (gh *GitHub) AllReleases(ctx context.Context, params AllReleasesParams) (AllReleasesQuery, error) {
+	ctx = WithOwner(ctx, params.Owner)
+	finalQuery := AllReleasesQuery{}
+
+	maxPages := params.MaxPages
+	if maxPages <= 0 {
+		maxPages = gh.MaxPages
+	}
+
+	nodes := ReleasesNodes{}
+	var cursor *githubv4.String
+	hasNextPage := true
+
+	for pages := 0; hasNextPage && (maxPages <= 0 || pages < maxPages); pages++ {
+		if err := ctx.Err(); err != nil {
+			return finalQuery, err
+		}
+
+		query := AllReleasesQuery{}
+
+		variables := map[string]interface{}{
+			"repositoryOwner": githubv4.String(params.Owner),
+			"repositoryName":  githubv4.String(params.Repo),
+			"releasesFirst":   githubv4.Int(100),
+			"releasesAfter":   cursor,
+		}
+
+		if err := gh.Query(ctx, &query, variables); err != nil {
+			return finalQuery, err
+		}
+		finalQuery.RateLimit = query.RateLimit
+
+		nodes = append(nodes, query.Repository.Releases.Nodes...)
+		hasNextPage = bool(query.Repository.Releases.PageInfo.HasNextPage)
+
+		next := query.Repository.Releases.PageInfo.EndCursor
+		cursor = &next
+	}
+
+	finalQuery.Repository.Releases.Nodes = nodes
+	finalQuery.Repository.Releases.PageInfo = PageInfo{
+		HasNextPage: githubv4.Boolean(hasNextPage),
+	}
+	if hasNextPage && cursor != nil {
+		finalQuery.Repository.Releases.PageInfo.EndCursor = *cursor
+	}
+
+	return finalQuery, nil
+}
+
+// AllRepositories fetches the repositories of an organization or user sorted by name. It iterates through all
+// the pages unless a maximum number of pages is set, in which case the returned page info reports whether the
+// result is truncated.
+func (gh *GitHub) AllRepositories(ctx context.Context, params AllRepositoriesParams) (AllRepositoriesQuery, error) {
+	ctx = WithOwner(ctx, params.Owner)
+	finalQuery := AllRepositoriesQuery{}
+
+	maxPages := params.MaxPages
+	if maxPages <= 0 {
+		maxPages = gh.MaxPages
+	}
+
+	nodes := RepositoriesNodes{}
+	var cursor *githubv4.String
+	hasNextPage := true
+
+	for pages := 0; hasNextPage && (maxPages <= 0 || pages < maxPages); pages++ {
+		if err := ctx.Err(); err != nil {
+			return finalQuery, err
+		}
+
+		query := AllRepositoriesQuery{}
+
+		variables := map[string]interface{}{
+			"repositoryOwner":   githubv4.String(params.Owner),
+			"repositoriesFirst": githubv4.Int(100),
+			"repositoriesAfter": cursor,
+		}
+
+		if err := gh.Query(ctx, &query, variables); err != nil {
+			return finalQuery, err
+		}
+		finalQuery.RateLimit = query.RateLimit
+
+		nodes = append(nodes, query.RepositoryOwner.Repositories.Nodes...)
+		hasNextPage = bool(query.RepositoryOwner.Repositories.PageInfo.HasNextPage)
+
+		next := query.RepositoryOwner.Repositories.PageInfo.EndCursor
+		cursor = &next
+	}
+
+	finalQuery.RepositoryOwner.Repositories.Nodes = nodes
+	finalQuery.RepositoryOwner.Repositories.PageInfo = PageInfo{
+		HasNextPage: githubv4.Boolean(hasNextPage),
+	}
+	if hasNextPage && cursor != nil {
+		finalQuery.RepositoryOwner.Repositories.PageInfo.EndCursor = *cursor
+	}
+
+	return finalQuery, nil
+}
+
+// RateLimit returns the last known rate limit status, querying it when no request was made yet.
+func (gh *GitHub) RateLimit(ctx context.Context) (types.RateLimit, error) {
+	if gh.RateLimiter != nil {
+		if status, known := gh.RateLimiter.Status(); known {
+			return status, nil
+		}
+	}
+
+	query := RateLimitQuery{}
+	if err := gh.Query(ctx, &query, nil); err != nil {
+		return types.RateLimit{}, err
+	}
+
+	return types.RateLimit{
+		Limit:     int(query.RateLimit.Limit),
+		Cost:      int(query.RateLimit.Cost),
+		Remaining: int(query.RateLimit.Remaining),
+		Used:      int(query.RateLimit.Used),
+		ResetAt:   query.RateLimit.ResetAt.UTC(),
+	}, nil
+}
+
+// updateRateLimit updates the shared rate limiter with the rate limit of a query of the given context.
+func (gh *GitHub) updateRateLimit(ctx context.Context, rateLimit RateLimit) {
+	if gh.RateLimiter == nil {
+		return
+	}
+
+	gh.RateLimiter.Update(ctx, rateLimit)
+}
+
+// Query executes the given query, a pointer to a query struct, with the given variables within the
+// request timeout. The rate limiter is updated when the query requests the rate limit.
+func (gh *GitHub) Query(ctx context.Context, query any, variables map[string]any) error {
+	if gh.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, gh.Timeout)
+		defer cancel()
+	}
+
+	if err := gh.Client.Query(ctx, query, variables); err != nil {
+		return err
+	}
+
+	if q, ok := query.(interface{ rateLimit() RateLimit }); ok {
+		gh.updateRateLimit(ctx, q.rateLimit())
+	}
+
+	return nil
+}
+
+// newGitHub returns a pointer to the GitHub struct of the given host, authenticated by the given transport.
+// The sync states of GitHub Enterprise Server hosts are persisted in a directory per host. When recording or
+// replaying fixtures, syncing is disabled since it changes the queries from one run to the next, and the given
+// transport is not called when replaying.
+func newGitHub(conf *config.GitHubConfig, host string, transport http.RoundTripper) (*GitHub, *http.Client) {
+	github := &GitHub{
+		MaxPages:    conf.MaxPages,
+		RateLimiter: NewRateLimiter(),
+		Timeout:     conf.Timeout,
+	}
+
+	hostDir := func(dir string) string {
+		if host == DefaultHost {
+			return dir
+		}
+		return filepath.Join(dir, host)
+	}
+
+	if conf.FixturesMode != "" {
+		recorder := &httprecorder.Recorder{Dir: hostDir(conf.FixturesDir), Mode: httprecorder.Mode(conf.FixturesMode)}
+		transport = recorder.Transport(transport)
+	}
+
+	if conf.SyncDir != "" && conf.FixturesMode == "" {
+		github.Syncs = NewFileSyncStore(hostDir(conf.SyncDir))
+	}
+
+	httpClient := &http.Client{
+		Transport: github.RateLimiter.Transport(transport),
+	}
+
+	return github, httpClient
+}
+
+// tokenSource returns the token source of the given personal access token, nil when empty.
+func tokenSource(token string) oauth2.TokenSource {
+	if token == "" {
+		return nil
+	}
+
+	return oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
+}
+
+// NewGitHub returns a pointer to the GitHub struct of github.com, authenticated as the installations of the
+// configured GitHub App or with the personal access token when there is no GitHub App.
+func NewGitHub(conf *config.GitHubConfig) (*GitHub, error) {
+	var transport http.RoundTripper = &oauth2.Transport{Source: oauth2.StaticTokenSource(&oauth2.Token{AccessToken: conf.Token})}
+
+	var app *App
+	if conf.AppID != 0 {
+		var err error
+		if app, err = NewApp(conf); err != nil {
+			return nil, err
+		}
+		transport = app.Transport(nil, tokenSource(conf.Token))
+	}
+
+	github, httpClient := newGitHub(conf, DefaultHost, transport)
 	github.Client = githubv4.NewClient(httpClient)
+	if app != nil {
+		github.RateLimiter.Budget = app.Budget
+	}
+
+	return github, nil
+}
+
+// NewEnterpriseGitHub returns a pointer to the GitHub struct of the given GitHub Enterprise Server host.
+func NewEnterpriseGitHub(conf *config.GitHubConfig, hostConf *config.GitHubHostConfig) *GitHub {
+	transport := &oauth2.Transport{Source: oauth2.StaticTokenSource(&oauth2.Token{AccessToken: hostConf.Token})}
+	github, httpClient := newGitHub(conf, hostConf.Host, transport)
+	github.Client = githubv4.NewEnterpriseClient(hostConf.GraphQLURL, httpClient)
 
 	return github
 }
@@ -0,0 +1,423 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+
+	"github.com/chris-ramon/golang-scaffolding/config"
+)
+
+// queryVariableRegexp matches the variables used by a GraphQL query.
+var queryVariableRegexp = regexp.MustCompile(`\$(\w+)`)
+
+// graphQLRequest represents a GraphQL request received by the test server.
+type graphQLRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables"`
+}
+
+// newGraphQLServer returns a GraphQL test server that fails when the query uses undeclared
+// variables and otherwise replies with the result of the given respond function.
+func newGraphQLServer(t *testing.T, respond func(req graphQLRequest) (int, string)) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := graphQLRequest{}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("unexpected error: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		for _, match := range queryVariableRegexp.FindAllStringSubmatch(req.Query, -1) {
+			if _, ok := req.Variables[match[1]]; !ok {
+				t.Errorf("query uses undeclared variable: %s", match[1])
+			}
+		}
+
+		status, body := respond(req)
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+// pullRequestsPage returns a GraphQL response of a page of pull requests.
+func pullRequestsPage(number int, hasNextPage bool, endCursor string) string {
+	return fmt.Sprintf(`{"data":{"repository":{"pullRequests":{"nodes":[{"number":%d,"createdAt":"2024-01-01T00:00:00Z","mergedAt":"2024-01-02T00:00:00Z"}],"pageInfo":{"hasNextPage":%v,"endCursor":%q}}}}}`, number, hasNextPage, endCursor)
+}
+
+func TestAllPullRequestsMaxPages(t *testing.T) {
+	server := newGraphQLServer(t, func(req graphQLRequest) (int, string) {
+		if req.Variables["pullRequestsAfter"] == nil {
+			return http.StatusOK, pullRequestsPage(1, true, "cursor-1")
+		}
+		return http.StatusOK, pullRequestsPage(2, false, "cursor-2")
+	})
+
+	gh := &GitHub{
+		Client: githubv4.NewEnterpriseClient(server.URL, server.Client()),
+	}
+
+	result, err := gh.AllPullRequests(context.Background(), AllPullRequestsParams{Owner: "owner", Repo: "repo", MaxPages: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.Repository.PullRequests.Nodes) != 1 {
+		t.Fatalf("expected 1 pull request, got %d", len(result.Repository.PullRequests.Nodes))
+	}
+
+	pageInfo := result.Repository.PullRequests.PageInfo
+	if !pageInfo.HasNextPage || pageInfo.EndCursor != "cursor-1" {
+		t.Errorf("expected truncated result with end cursor, got %+v", pageInfo)
+	}
+
+	result, err = gh.AllPullRequests(context.Background(), AllPullRequestsParams{Owner: "owner", Repo: "repo"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.Repository.PullRequests.Nodes) != 2 {
+		t.Fatalf("expected 2 pull requests without maximum pages, got %d", len(result.Repository.PullRequests.Nodes))
+	}
+
+	if result.Repository.PullRequests.PageInfo.HasNextPage {
+		t.Errorf("expected complete result")
+	}
+}
+
+// TestPullRequests replays the recorded response of a batch of pull requests of github.example.com, whose
+// third pull request does not exist.
+func TestPullRequests(t *testing.T) {
+	conf := &config.GitHubConfig{FixturesDir: "testdata/fixtures", FixturesMode: "replay"}
+	gh := NewEnterpriseGitHub(conf, &config.GitHubHostConfig{Host: "github.example.com", GraphQLURL: "https://github.example.com/api/graphql"})
+
+	results, err := gh.PullRequests(context.Background(), PullRequestsParams{
+		PullRequests: []PullRequestParams{
+			{Owner: "owner", Repo: "repo", Number: 42},
+			{Owner: "owner", Repo: "other", Number: 7},
+			{Owner: "owner", Repo: "repo", Number: 9},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+
+	pr := results[0].PullRequest
+	if results[0].Err != nil || pr.Number != 42 || pr.HeadRef.Name != "main" {
+		t.Errorf("expected pull request 42 of the main branch, got %+v", results[0])
+	}
+
+	if len(pr.Participants.Nodes) != 1 || pr.Participants.Nodes[0].Login != "user1" {
+		t.Errorf("expected participants of the pull request, got %+v", pr.Participants.Nodes)
+	}
+
+	if results[1].Err != nil || results[1].PullRequest.Number != 7 {
+		t.Errorf("expected pull request 7 of the other repository, got %+v", results[1])
+	}
+
+	if !errors.Is(results[2].Err, ErrPullRequestNotFound) {
+		t.Errorf("expected pull request 9 not to be found, got %v", results[2].Err)
+	}
+}
+
+func TestAllPullRequestsFiles(t *testing.T) {
+	server := newGraphQLServer(t, func(req graphQLRequest) (int, string) {
+		if req.Variables["filesAfter"] == nil {
+			return http.StatusOK, `{"data":{"repository":{"pullRequests":{"nodes":[{"number":1,"createdAt":"2024-01-01T00:00:00Z","mergedAt":"2024-01-02T00:00:00Z","files":{"nodes":[{"path":"services/api/main.go"}],"pageInfo":{"hasNextPage":true,"endCursor":"files-1"}}}],"pageInfo":{"hasNextPage":false,"endCursor":"cursor-1"}}}}}`
+		}
+
+		if req.Variables["filesAfter"] != "files-1" || req.Variables["pullRequestNumber"] != float64(1) {
+			t.Errorf("expected the files of pull request 1 after files-1, got %v", req.Variables)
+		}
+		return http.StatusOK, `{"data":{"repository":{"pullRequest":{"files":{"nodes":[{"path":"libs/auth/token.go"}],"pageInfo":{"hasNextPage":false,"endCursor":"files-2"}}}}}}`
+	})
+
+	gh := &GitHub{
+		Client: githubv4.NewEnterpriseClient(server.URL, server.Client()),
+	}
+
+	result, err := gh.AllPullRequests(context.Background(), AllPullRequestsParams{Owner: "owner", Repo: "repo"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	files := result.Repository.PullRequests.Nodes[0].Files
+	if len(files.Nodes) != 2 || files.Nodes[0].Path != "services/api/main.go" || files.Nodes[1].Path != "libs/auth/token.go" {
+		t.Errorf("expected every page of files, got %+v", files.Nodes)
+	}
+
+	if files.PageInfo.HasNextPage {
+		t.Errorf("expected no files left out, got %+v", files.PageInfo)
+	}
+}
+
+func TestPullRequestsConnections(t *testing.T) {
+	server := newGraphQLServer(t, func(req graphQLRequest) (int, string) {
+		switch {
+		case req.Variables["commitsAfter"] != nil:
+			if req.Variables["commitsAfter"] != "commits-1" || req.Variables["pullRequestNumber"] != float64(42) {
+				t.Errorf("expected the commits of pull request 42 after commits-1, got %v", req.Variables)
+			}
+			return http.StatusOK, `{"data":{"repository":{"pullRequest":{"commits":{"nodes":[{"commit":{"message":"Fix\n\nCo-authored-by: Pair <pair@example.com>"}}],"pageInfo":{"hasNextPage":false,"endCursor":"commits-2"}}}}}}`
+		case req.Variables["reviewsAfter"] != nil:
+			if req.Variables["reviewsAfter"] != "reviews-1" {
+				t.Errorf("expected the reviews after reviews-1, got %v", req.Variables)
+			}
+			return http.StatusOK, `{"data":{"repository":{"pullRequest":{"reviews":{"nodes":[{"author":{"login":"late-reviewer"}}],"pageInfo":{"hasNextPage":false,"endCursor":"reviews-2"}}}}}}`
+		case req.Variables["filesAfter"] != nil:
+			return http.StatusOK, `{"data":{"repository":{"pullRequest":{"files":{"nodes":[{"path":"libs/auth/token.go"}],"pageInfo":{"hasNextPage":false,"endCursor":"files-2"}}}}}}`
+		}
+
+		return http.StatusOK, `{"data":{"pullRequest0":{"pullRequest":{
+			"number": 42,
+			"commits": {"nodes": [{"commit": {"message": "Add feature"}}], "pageInfo": {"hasNextPage": true, "endCursor": "commits-1"}},
+			"reviews": {"nodes": [{"author": {"login": "reviewer"}}], "pageInfo": {"hasNextPage": true, "endCursor": "reviews-1"}},
+			"files": {"nodes": [{"path": "services/api/main.go"}], "pageInfo": {"hasNextPage": true, "endCursor": "files-1"}}
+		}}}}`
+	})
+
+	gh := &GitHub{
+		Client: githubv4.NewEnterpriseClient(server.URL, server.Client()),
+	}
+
+	results, err := gh.PullRequests(context.Background(), PullRequestsParams{
+		PullRequests: []PullRequestParams{{Owner: "owner", Repo: "repo", Number: 42}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	pr := results[0].PullRequest
+	if len(pr.Commits.Nodes) != 2 || pr.Commits.PageInfo.HasNextPage {
+		t.Errorf("expected every page of commits, got %+v", pr.Commits)
+	}
+
+	if len(pr.Reviews.Nodes) != 2 || pr.Reviews.Nodes[1].Author.Login != "late-reviewer" {
+		t.Errorf("expected every page of reviews, got %+v", pr.Reviews)
+	}
+
+	if len(pr.Files.Nodes) != 2 {
+		t.Errorf("expected every page of files, got %+v", pr.Files)
+	}
+}
+
+func TestAllIssues(t *testing.T) {
+	server := newGraphQLServer(t, func(req graphQLRequest) (int, string) {
+		states, _ := json.Marshal(req.Variables["issuesStates"])
+		if string(states) != `["OPEN","CLOSED"]` {
+			t.Errorf("expected issues of every state, got %s", states)
+		}
+
+		if req.Variables["issuesAfter"] == nil {
+			return http.StatusOK, `{"data":{"repository":{"issues":{"nodes":[{"number":2,"state":"OPEN","createdAt":"2024-01-02T00:00:00Z","repository":{"name":"repo","owner":{"login":"owner"}}}],"pageInfo":{"hasNextPage":true,"endCursor":"cursor-1"}}}}}`
+		}
+		return http.StatusOK, `{"data":{"repository":{"issues":{"nodes":[{"number":1,"state":"CLOSED","createdAt":"2024-01-01T00:00:00Z","closedAt":"2024-01-03T00:00:00Z"}],"pageInfo":{"hasNextPage":false,"endCursor":"cursor-2"}}}}}`
+	})
+
+	gh := &GitHub{
+		Client: githubv4.NewEnterpriseClient(server.URL, server.Client()),
+	}
+
+	result, err := gh.AllIssues(context.Background(), AllIssuesParams{Owner: "owner", Repo: "repo"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	nodes := result.Repository.Issues.Nodes
+	if len(nodes) != 2 || result.Repository.Issues.PageInfo.HasNextPage {
+		t.Fatalf("expected every page of issues, got %d issues, has next page: %v", len(nodes), result.Repository.Issues.PageInfo.HasNextPage)
+	}
+
+	if nodes[0].Number != 2 || nodes[0].Repository.Owner.Login != "owner" || nodes[1].Number != 1 || nodes[1].ClosedAt.IsZero() {
+		t.Errorf("unexpected issues: %+v", nodes)
+	}
+
+	gh.MaxPages = 1
+	result, err = gh.AllIssues(context.Background(), AllIssuesParams{Owner: "owner", Repo: "repo"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.Repository.Issues.Nodes) != 1 || !result.Repository.Issues.PageInfo.HasNextPage {
+		t.Errorf("expected issues truncated to the first page, got %d issues", len(result.Repository.Issues.Nodes))
+	}
+}
+
+func TestAllReleases(t *testing.T) {
+	server := newGraphQLServer(t, func(req graphQLRequest) (int, string) {
+		if req.Variables["repositoryOwner"] != "owner" || req.Variables["repositoryName"] != "repo" {
+			t.Errorf("expected releases of owner/repo, got %v", req.Variables)
+		}
+
+		if req.Variables["releasesAfter"] == nil {
+			return http.StatusOK, `{"data":{"repository":{"releases":{"nodes":[{"tagName":"v2.0.0","isDraft":true,"createdAt":"2024-02-01T00:00:00Z"}],"pageInfo":{"hasNextPage":true,"endCursor":"cursor-1"}}}}}`
+		}
+		return http.StatusOK, `{"data":{"repository":{"releases":{"nodes":[{"tagName":"v1.0.0","name":"First release","isDraft":false,"createdAt":"2024-01-01T00:00:00Z","publishedAt":"2024-01-02T00:00:00Z","author":{"login":"author"}}],"pageInfo":{"hasNextPage":false,"endCursor":"cursor-2"}}}}}`
+	})
+
+	gh := &GitHub{
+		Client: githubv4.NewEnterpriseClient(server.URL, server.Client()),
+	}
+
+	result, err := gh.AllReleases(context.Background(), AllReleasesParams{Owner: "owner", Repo: "repo"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	nodes := result.Repository.Releases.Nodes
+	if len(nodes) != 2 || result.Repository.Releases.PageInfo.HasNextPage {
+		t.Fatalf("expected every page of releases, got %d releases, has next page: %v", len(nodes), result.Repository.Releases.PageInfo.HasNextPage)
+	}
+
+	if nodes[0].TagName != "v2.0.0" || !bool(nodes[0].IsDraft) || nodes[1].TagName != "v1.0.0" || nodes[1].Author.Login != "author" || nodes[1].PublishedAt.IsZero() {
+		t.Errorf("unexpected releases: %+v", nodes)
+	}
+
+	gh.MaxPages = 1
+	result, err = gh.AllReleases(context.Background(), AllReleasesParams{Owner: "owner", Repo: "repo"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.Repository.Releases.Nodes) != 1 || !result.Repository.Releases.PageInfo.HasNextPage {
+		t.Errorf("expected releases truncated to the first page, got %d releases", len(result.Repository.Releases.Nodes))
+	}
+}
+
+func TestAllRepositories(t *testing.T) {
+	server := newGraphQLServer(t, func(req graphQLRequest) (int, string) {
+		if req.Variables["repositoryOwner"] != "org" {
+			t.Errorf("expected repositories of org, got %v", req.Variables["repositoryOwner"])
+		}
+
+		if req.Variables["repositoriesAfter"] == nil {
+			return http.StatusOK, `{"data":{"repositoryOwner":{"repositories":{"nodes":[{"name":"api","url":"https://github.com/org/api","isArchived":false}],"pageInfo":{"hasNextPage":true,"endCursor":"cursor-1"}}}}}`
+		}
+		return http.StatusOK, `{"data":{"repositoryOwner":{"repositories":{"nodes":[{"name":"web","url":"https://github.com/org/web","isArchived":true}],"pageInfo":{"hasNextPage":false,"endCursor":"cursor-2"}}}}}`
+	})
+
+	gh := &GitHub{
+		Client: githubv4.NewEnterpriseClient(server.URL, server.Client()),
+	}
+
+	result, err := gh.AllRepositories(context.Background(), AllRepositoriesParams{Owner: "org"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	nodes := result.RepositoryOwner.Repositories.Nodes
+	if len(nodes) != 2 || result.RepositoryOwner.Repositories.PageInfo.HasNextPage {
+		t.Fatalf("expected every page of repositories, got %d repositories", len(nodes))
+	}
+
+	if nodes[0].Name != "api" || nodes[0].URL != "https://github.com/org/api" || !nodes[1].IsArchived {
+		t.Errorf("unexpected repositories: %+v", nodes)
+	}
+
+	gh.MaxPages = 1
+	result, err = gh.AllRepositories(context.Background(), AllRepositoriesParams{Owner: "org"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.RepositoryOwner.Repositories.Nodes) != 1 || !result.RepositoryOwner.Repositories.PageInfo.HasNextPage {
+		t.Errorf("expected repositories truncated to the first page, got %d repositories", len(result.RepositoryOwner.Repositories.Nodes))
+	}
+}
+
+func TestAllPullRequestsCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	requests := 0
+	server := newGraphQLServer(t, func(req graphQLRequest) (int, string) {
+		requests++
+		cancel()
+		return http.StatusOK, pullRequestsPage(requests, true, fmt.Sprintf("cursor-%d", requests))
+	})
+
+	gh := &GitHub{Client: githubv4.NewEnterpriseClient(server.URL, server.Client())}
+
+	if _, err := gh.AllPullRequests(ctx, AllPullRequestsParams{Owner: "owner", Repo: "repo"}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected cancelled error, got: %v", err)
+	}
+
+	if requests != 1 {
+		t.Errorf("expected pagination to stop after cancellation, got %d requests", requests)
+	}
+}
+
+func TestQueryTimeout(t *testing.T) {
+	done := make(chan struct{})
+	defer close(done)
+
+	server := newGraphQLServer(t, func(req graphQLRequest) (int, string) {
+		<-done
+		return http.StatusOK, `{"data":{}}`
+	})
+
+	gh := &GitHub{
+		Client:  githubv4.NewEnterpriseClient(server.URL, server.Client()),
+		Timeout: 10 * time.Millisecond,
+	}
+
+	err := gh.Query(context.Background(), &RateLimitQuery{}, nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded error, got: %v", err)
+	}
+}
+
+func TestNewEnterpriseGitHubFixtures(t *testing.T) {
+	server := newGraphQLServer(t, func(req graphQLRequest) (int, string) {
+		if req.Variables["pullRequestsAfter"] == nil {
+			return http.StatusOK, pullRequestsPage(1, true, "cursor-1")
+		}
+		return http.StatusOK, pullRequestsPage(2, false, "cursor-2")
+	})
+
+	conf := &config.GitHubConfig{FixturesDir: t.TempDir()}
+	hostConf := &config.GitHubHostConfig{Host: "github.example.com", GraphQLURL: server.URL, Token: "token"}
+	params := AllPullRequestsParams{Owner: "owner", Repo: "repo"}
+
+	conf.FixturesMode = "record"
+	recorded, err := NewEnterpriseGitHub(conf, hostConf).AllPullRequests(context.Background(), params)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	server.Close()
+
+	conf.FixturesMode = "replay"
+	hostConf.Token = ""
+	replayed, err := NewEnterpriseGitHub(conf, hostConf).AllPullRequests(context.Background(), params)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(replayed.Repository.PullRequests.Nodes) != 2 || len(recorded.Repository.PullRequests.Nodes) != 2 {
+		t.Fatalf("expected every page to be recorded and replayed, got %d recorded and %d replayed pull requests", len(recorded.Repository.PullRequests.Nodes), len(replayed.Repository.PullRequests.Nodes))
+	}
+
+	for i, node := range replayed.Repository.PullRequests.Nodes {
+		if node.Number != recorded.Repository.PullRequests.Nodes[i].Number {
+			t.Errorf("expected pull request %d to be #%d, got #%d", i, recorded.Repository.PullRequests.Nodes[i].Number, node.Number)
+		}
+	}
+}
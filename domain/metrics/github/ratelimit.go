@@ -0,0 +1,401 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+
+	"github.com/chris-ramon/golang-scaffolding/domain/metrics/types"
+)
+
+// RateLimit represents the GitHub GraphQL API rate limit requested along every query.
+type RateLimit struct {
+	Limit     githubv4.Int
+	Cost      githubv4.Int
+	Remaining githubv4.Int
+	Used      githubv4.Int
+	ResetAt   githubv4.DateTime
+}
+
+// RateLimitQuery is the query of the GitHub GraphQL API rate limit.
+type RateLimitQuery struct {
+	RateLimit RateLimit `graphql:"rateLimit"`
+}
+
+func (q *RateLimitQuery) rateLimit() RateLimit { return q.RateLimit }
+
+// RateLimiter is a rate limiter shared by the GitHub requests, it throttles requests when the
+// remaining budget is exhausted and retries the bad gateway and rate limited responses with jittered backoff,
+// GraphQL responses with a `RATE_LIMITED` error included. Each budget, like the personal access token or a GitHub
+// App installation, has its own rate limit status.
+type RateLimiter struct {
+	// mu guards statuses.
+	mu sync.RWMutex
+
+	// statuses are the last known rate limit statuses by budget.
+	statuses map[string]types.RateLimit
+
+	// Budget returns the budget of the requests of the given context, every request shares the default budget,
+	// empty, when nil.
+	Budget func(ctx context.Context) string
+
+	// MinRemaining is the remaining budget under which requests wait for the rate limit reset.
+	MinRemaining int
+
+	// MaxRetries is the maximum number of retries of a failed request.
+	MaxRetries int
+
+	// BaseBackoff is the backoff of the first retry, doubled on each following retry.
+	BaseBackoff time.Duration
+
+	// MaxBackoff is the maximum backoff between retries.
+	MaxBackoff time.Duration
+
+	// MaxWait is the maximum time a request waits for the rate limit reset or before a retry.
+	MaxWait time.Duration
+}
+
+// Status returns the last known rate limit status of the default budget, false when no request was made yet.
+func (r *RateLimiter) Status() (types.RateLimit, bool) {
+	return r.status("")
+}
+
+// status returns the last known rate limit status of the given budget, false when no request was made yet.
+func (r *RateLimiter) status(budget string) (types.RateLimit, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	status, known := r.statuses[budget]
+	return status, known
+}
+
+// budget returns the budget of the requests of the given context.
+func (r *RateLimiter) budget(ctx context.Context) string {
+	if r.Budget == nil {
+		return ""
+	}
+
+	return r.Budget(ctx)
+}
+
+// Update updates the rate limit status of the budget of the given context from the given GraphQL rate limit.
+func (r *RateLimiter) Update(ctx context.Context, rateLimit RateLimit) {
+	if rateLimit.Limit == 0 {
+		return
+	}
+
+	budget := r.budget(ctx)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.statuses == nil {
+		r.statuses = map[string]types.RateLimit{}
+	}
+	r.statuses[budget] = types.RateLimit{
+		Limit:     int(rateLimit.Limit),
+		Cost:      int(rateLimit.Cost),
+		Remaining: int(rateLimit.Remaining),
+		Used:      int(rateLimit.Used),
+		ResetAt:   rateLimit.ResetAt.UTC(),
+	}
+}
+
+// updateFromHeader updates the rate limit status of the given budget from the `X-RateLimit-*` response headers,
+// or the GitLab `RateLimit-*` ones.
+func (r *RateLimiter) updateFromHeader(budget string, header http.Header) {
+	remaining, err := strconv.Atoi(rateLimitHeader(header, "Remaining"))
+	if err != nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.statuses == nil {
+		r.statuses = map[string]types.RateLimit{}
+	}
+	status := r.statuses[budget]
+	status.Remaining = remaining
+	if limit, err := strconv.Atoi(rateLimitHeader(header, "Limit")); err == nil {
+		status.Limit = limit
+	}
+	if used, err := strconv.Atoi(rateLimitHeader(header, "Used")); err == nil {
+		status.Used = used
+	}
+	if reset, err := strconv.ParseInt(rateLimitHeader(header, "Reset"), 10, 64); err == nil {
+		status.ResetAt = time.Unix(reset, 0).UTC()
+	}
+	r.statuses[budget] = status
+}
+
+// rateLimitHeader returns the value of the `X-RateLimit-<name>` response header, or of the GitLab
+// `RateLimit-<name>` one.
+func rateLimitHeader(header http.Header, name string) string {
+	if v := header.Get("X-RateLimit-" + name); v != "" {
+		return v
+	}
+
+	return header.Get("RateLimit-" + name)
+}
+
+// Wait blocks until the rate limit resets when the remaining budget of the given context is exhausted.
+func (r *RateLimiter) Wait(ctx context.Context) error {
+	status, known := r.status(r.budget(ctx))
+	if !known || status.Remaining > r.MinRemaining {
+		return nil
+	}
+
+	wait := time.Until(status.ResetAt)
+	if wait <= 0 {
+		return nil
+	}
+
+	return sleep(ctx, r.clamp(wait))
+}
+
+// clamp returns the given wait capped to the maximum wait.
+func (r *RateLimiter) clamp(wait time.Duration) time.Duration {
+	if r.MaxWait > 0 && wait > r.MaxWait {
+		return r.MaxWait
+	}
+
+	return wait
+}
+
+// backoff returns the jittered exponential backoff of the given retry attempt.
+func (r *RateLimiter) backoff(attempt int) time.Duration {
+	backoff := r.BaseBackoff << attempt
+	if backoff <= 0 || (r.MaxBackoff > 0 && backoff > r.MaxBackoff) {
+		backoff = r.MaxBackoff
+	}
+	if backoff <= 0 {
+		return 0
+	}
+
+	// Waits between half and the full backoff so concurrent retries spread out.
+	return backoff/2 + rand.N(backoff/2+1)
+}
+
+// retryWait returns whether the given response should be retried and how long to wait before, at most the
+// maximum wait.
+func (r *RateLimiter) retryWait(resp *http.Response, body []byte, attempt int) (bool, time.Duration) {
+	if attempt >= r.MaxRetries {
+		return false, 0
+	}
+
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true, r.clamp(r.backoff(attempt))
+	}
+
+	if !rateLimited(resp, body) {
+		return false, 0
+	}
+
+	if retryAfter, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		return true, r.clamp(time.Duration(retryAfter) * time.Second)
+	}
+
+	if rateLimitHeader(resp.Header, "Remaining") == "0" {
+		if reset, err := strconv.ParseInt(rateLimitHeader(resp.Header, "Reset"), 10, 64); err == nil {
+			wait := time.Until(time.Unix(reset, 0))
+			// Retrying before the reset would only be rejected again.
+			if r.MaxWait > 0 && wait > r.MaxWait {
+				return false, 0
+			}
+			return true, max(wait, 0)
+		}
+	}
+
+	// Secondary rate limits and too many requests responses are rate limited even without telling for how long.
+	return true, r.clamp(r.backoff(attempt))
+}
+
+// rateLimited reports whether the given response was rejected by a rate limit: a too many requests response, a
+// forbidden one of an exhausted or secondary rate limit, or a GraphQL response with a `RATE_LIMITED` error.
+func rateLimited(resp *http.Response, body []byte) bool {
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusForbidden:
+		message := strings.ToLower(string(body))
+		return resp.Header.Get("Retry-After") != "" ||
+			strings.Contains(message, "secondary rate limit") ||
+			strings.Contains(message, "abuse") ||
+			rateLimitHeader(resp.Header, "Remaining") == "0"
+	case http.StatusOK:
+		return graphQLRateLimited(body)
+	default:
+		return false
+	}
+}
+
+// graphQLRateLimited reports whether the given GraphQL response body has a `RATE_LIMITED` error, which GitHub
+// responds with a 200 status.
+func graphQLRateLimited(body []byte) bool {
+	if !bytes.Contains(body, []byte("RATE_LIMITED")) {
+		return false
+	}
+
+	response := struct {
+		Errors []struct {
+			Type string `json:"type"`
+		} `json:"errors"`
+	}{}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return false
+	}
+
+	for _, e := range response.Errors {
+		if e.Type == "RATE_LIMITED" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Transport returns a round tripper that throttles and retries the requests of the given base round tripper.
+func (r *RateLimiter) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return &rateLimitTransport{base: base, limiter: r}
+}
+
+type rateLimitTransport struct {
+	base    http.RoundTripper
+	limiter *RateLimiter
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		if err := t.limiter.Wait(req.Context()); err != nil {
+			return nil, err
+		}
+
+		resp, err := t.base.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+
+		t.limiter.updateFromHeader(t.limiter.budget(req.Context()), resp.Header)
+
+		// GraphQL queries are posted and report their rate limit errors in a successful response.
+		if resp.StatusCode < http.StatusBadRequest && req.Method != http.MethodPost {
+			return resp, nil
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+
+		if resp.StatusCode < http.StatusBadRequest && !graphQLRateLimited(body) {
+			return resp, nil
+		}
+
+		retry, wait := t.limiter.retryWait(resp, body, attempt)
+		if !retry || (req.Body != nil && req.GetBody == nil) {
+			if rateLimited(resp, body) {
+				return nil, newRateLimitError(resp, body)
+			}
+			return resp, nil
+		}
+
+		if err := sleep(req.Context(), wait); err != nil {
+			return nil, err
+		}
+
+		req = req.Clone(req.Context())
+		if req.GetBody != nil {
+			if req.Body, err = req.GetBody(); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// RateLimitError is the error of the requests still rejected by a rate limit once retried.
+type RateLimitError struct {
+	// StatusCode is the status code of the rejected response, 200 for the GraphQL `RATE_LIMITED` errors.
+	StatusCode int
+
+	// ResetAt is the time the rate limit resets at, zero when unknown.
+	ResetAt time.Time
+
+	// Body is the beginning of the body of the rejected response.
+	Body string
+}
+
+func (e *RateLimitError) Error() string {
+	message := fmt.Sprintf("API rate limit exceeded with status %d", e.StatusCode)
+	if !e.ResetAt.IsZero() {
+		message += fmt.Sprintf(", resets at %s", e.ResetAt.Format(time.RFC3339))
+	}
+	if e.Body != "" {
+		message += ": " + e.Body
+	}
+
+	return message
+}
+
+// newRateLimitError returns the rate limit error of the given rejected response.
+func newRateLimitError(resp *http.Response, body []byte) *RateLimitError {
+	err := &RateLimitError{
+		StatusCode: resp.StatusCode,
+		Body:       string(body[:min(len(body), 1024)]),
+	}
+
+	if reset, parseErr := strconv.ParseInt(rateLimitHeader(resp.Header, "Reset"), 10, 64); parseErr == nil {
+		err.ResetAt = time.Unix(reset, 0).UTC()
+	}
+
+	return err
+}
+
+// IsRateLimitError reports whether the given error is caused by a rate limit, see `RateLimitError`.
+func IsRateLimitError(err error) bool {
+	var rateLimitErr *RateLimitError
+	return errors.As(err, &rateLimitErr)
+}
+
+// sleep waits for the given duration or until the given context is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// NewRateLimiter returns a pointer to the RateLimiter struct with reasonable defaults.
+func NewRateLimiter() *RateLimiter {
+	return &RateLimiter{
+		MinRemaining: 10,
+		MaxRetries:   5,
+		BaseBackoff:  time.Second,
+		MaxBackoff:   time.Minute,
+		MaxWait:      time.Hour,
+	}
+}
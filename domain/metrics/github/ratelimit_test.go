@@ -0,0 +1,199 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterTransportRetries(t *testing.T) {
+	testCases := []struct {
+		name          string
+		status        int
+		body          string
+		expectedCalls int
+	}{
+		{name: "bad gateway", status: http.StatusBadGateway, body: "bad gateway", expectedCalls: 2},
+		{name: "secondary rate limit", status: http.StatusForbidden, body: `{"message":"You have exceeded a secondary rate limit"}`, expectedCalls: 2},
+		{name: "forbidden", status: http.StatusForbidden, body: `{"message":"Resource not accessible"}`, expectedCalls: 1},
+		{name: "graphql rate limited", status: http.StatusOK, body: `{"data":null,"errors":[{"type":"RATE_LIMITED","message":"API rate limit exceeded"}]}`, expectedCalls: 2},
+		{name: "graphql error", status: http.StatusOK, body: `{"data":null,"errors":[{"type":"NOT_FOUND","message":"Could not resolve to a Repository"}]}`, expectedCalls: 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			calls := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+
+				body, _ := io.ReadAll(r.Body)
+				if string(body) != "query" {
+					t.Errorf("expected request body to be replayed, got: %q", body)
+				}
+
+				if calls == 1 {
+					w.WriteHeader(tc.status)
+					w.Write([]byte(tc.body))
+					return
+				}
+				w.Write([]byte("ok"))
+			}))
+			defer server.Close()
+
+			limiter := NewRateLimiter()
+			limiter.BaseBackoff = time.Millisecond
+			client := &http.Client{Transport: limiter.Transport(nil)}
+
+			resp, err := client.Post(server.URL, "text/plain", strings.NewReader("query"))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			resp.Body.Close()
+
+			if calls != tc.expectedCalls {
+				t.Errorf("expected %d calls, got %d", tc.expectedCalls, calls)
+			}
+		})
+	}
+}
+
+func TestRateLimiterWait(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	limiter := NewRateLimiter()
+	client := &http.Client{Transport: limiter.Transport(nil)}
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	status, known := limiter.Status()
+	if !known || status.Limit != 5000 || status.Remaining != 0 {
+		t.Fatalf("expected rate limit status from headers, got %+v", status)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := limiter.Wait(ctx); err != context.DeadlineExceeded {
+		t.Errorf("expected wait until reset to be cancelled, got: %v", err)
+	}
+}
+
+func TestRateLimiterTransportClampsRetryAfter(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+
+		if calls == 1 {
+			w.Header().Set("Retry-After", "3600")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	limiter := NewRateLimiter()
+	limiter.MaxWait = 10 * time.Millisecond
+	client := &http.Client{Transport: limiter.Transport(nil)}
+
+	start := time.Now()
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	if calls != 2 {
+		t.Errorf("expected the rate limited request to be retried, got %d calls", calls)
+	}
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected the retry after wait to be capped to the maximum wait, waited %v", elapsed)
+	}
+}
+
+func TestRateLimiterTransportReturnsRateLimitError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", "1700000000")
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"message":"API rate limit exceeded"}`))
+	}))
+	defer server.Close()
+
+	limiter := NewRateLimiter()
+	limiter.MaxRetries = 0
+	client := &http.Client{Transport: limiter.Transport(nil)}
+
+	_, err := client.Get(server.URL)
+	if !IsRateLimitError(err) {
+		t.Fatalf("expected a rate limit error, got: %v", err)
+	}
+
+	var rateLimitErr *RateLimitError
+	errors.As(err, &rateLimitErr)
+	if rateLimitErr.StatusCode != http.StatusForbidden {
+		t.Errorf("expected status code %d, got: %d", http.StatusForbidden, rateLimitErr.StatusCode)
+	}
+	if !rateLimitErr.ResetAt.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("expected the reset time to be parsed, got: %v", rateLimitErr.ResetAt)
+	}
+}
+
+func TestRateLimiterBudgets(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", r.URL.Query().Get("remaining"))
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	limiter := NewRateLimiter()
+	limiter.Budget = ownerFromContext
+	client := &http.Client{Transport: limiter.Transport(nil)}
+
+	for owner, remaining := range map[string]string{"exhausted": "0", "other": "4999"} {
+		req, err := http.NewRequest(http.MethodGet, server.URL+"?remaining="+remaining, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		resp, err := client.Do(req.WithContext(WithOwner(context.Background(), owner)))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		resp.Body.Close()
+	}
+
+	if _, known := limiter.Status(); known {
+		t.Errorf("expected the default budget to be left unknown")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := limiter.Wait(WithOwner(ctx, "other")); err != nil {
+		t.Errorf("expected the requests of another budget not to wait, got: %v", err)
+	}
+
+	if err := limiter.Wait(WithOwner(ctx, "exhausted")); err != context.DeadlineExceeded {
+		t.Errorf("expected the requests of the exhausted budget to wait until reset, got: %v", err)
+	}
+}
@@ -0,0 +1,71 @@
+package github
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+// unsafeFileNameChars matches the characters replaced when mapping store keys to file names.
+var unsafeFileNameChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// jsonFileStore persists values as JSON files named after their keys.
+type jsonFileStore struct {
+	dir string
+}
+
+func (f jsonFileStore) path(key string) string {
+	return filepath.Join(f.dir, unsafeFileNameChars.ReplaceAllString(key, "_")+".json")
+}
+
+// load decodes the value of the given key into v, false when there is none.
+func (f jsonFileStore) load(key string, v any) (bool, error) {
+	data, err := os.ReadFile(f.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// save persists the value of the given key.
+func (f jsonFileStore) save(key string, v any) error {
+	if err := os.MkdirAll(f.dir, 0755); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	// Writes to a temporary file of its own first so neither an interruption nor a concurrent save of the same
+	// key ever leaves a corrupted file.
+	tmp, err := os.CreateTemp(f.dir, filepath.Base(f.path(key))+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), f.path(key))
+}
@@ -0,0 +1,370 @@
+package github
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+)
+
+// SyncStateVersion is the version of the persisted sync states, to increment whenever the fetched pull request
+// fields change. The pull requests of a sync state of another version are all fetched again.
+const SyncStateVersion = 1
+
+// SyncState represents the locally persisted copy of the pull requests of a repository.
+type SyncState struct {
+	// Version is the version of the sync state.
+	Version int
+
+	// Watermark is the update time of the most recently updated pull request of the last complete sync.
+	Watermark time.Time
+
+	// PendingWatermark is the watermark of an interrupted sync, applied once the sync completes.
+	PendingWatermark time.Time
+
+	// Cursor is the end cursor of the last fetched page of an interrupted sync.
+	Cursor string
+
+	// Nodes are the pull requests of every state synced so far.
+	Nodes AllPullRequestsNodes
+}
+
+// index returns the position of the synced pull requests by number.
+func (s *SyncState) index() map[githubv4.Int]int {
+	index := map[githubv4.Int]int{}
+	for i, node := range s.Nodes {
+		index[node.Number] = i
+	}
+
+	return index
+}
+
+// merge merges the given pull requests into the synced ones, replacing the ones of the same number.
+func (s *SyncState) merge(index map[githubv4.Int]int, nodes AllPullRequestsNodes) {
+	for _, node := range nodes {
+		if i, ok := index[node.Number]; ok {
+			s.Nodes[i] = node
+			continue
+		}
+		index[node.Number] = len(s.Nodes)
+		s.Nodes = append(s.Nodes, node)
+	}
+}
+
+// SyncStore persists the synced pull requests of the repositories.
+type SyncStore interface {
+	// Load returns the sync state of the given key, nil when there is none.
+	Load(key string) (*SyncState, error)
+
+	// Save persists the whole sync state of the given key.
+	Save(key string, state *SyncState) error
+
+	// Append persists the progress of a sync of the given key after each page: the state without its pull
+	// requests, and the pull requests of the page merged into the saved ones on load.
+	Append(key string, state *SyncState, nodes AllPullRequestsNodes) error
+}
+
+// FileSyncStore is a sync store that persists sync states as JSON files, along with a JSON lines journal of the
+// pages fetched since their last save.
+type FileSyncStore struct {
+	// Dir is the sync states directory.
+	Dir string
+}
+
+func (f *FileSyncStore) journalPath(key string) string {
+	return strings.TrimSuffix(jsonFileStore{dir: f.Dir}.path(key), ".json") + ".jsonl"
+}
+
+// Load returns the sync state of the given key, nil when there is none.
+func (f *FileSyncStore) Load(key string) (*SyncState, error) {
+	state := &SyncState{}
+	found, err := jsonFileStore{dir: f.Dir}.load(key, state)
+	if err != nil {
+		return nil, err
+	}
+
+	journal, err := os.Open(f.journalPath(key))
+	if errors.Is(err, os.ErrNotExist) {
+		if !found {
+			return nil, nil
+		}
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer journal.Close()
+
+	index := state.index()
+	reader := bufio.NewReader(journal)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+
+		// Every page starts a line of its own, so the partial write of an interrupted append is a line of its own
+		// too, skipped as its page is fetched again from the cursor of the previous one.
+		page := &SyncState{}
+		if json.Unmarshal(line, page) == nil {
+			state.Version = page.Version
+			state.Watermark = page.Watermark
+			state.PendingWatermark = page.PendingWatermark
+			state.Cursor = page.Cursor
+			state.merge(index, page.Nodes)
+			found = true
+		}
+
+		if errors.Is(err, io.EOF) {
+			break
+		}
+	}
+
+	if !found {
+		return nil, nil
+	}
+
+	return state, nil
+}
+
+// Save persists the sync state of the given key, replacing its journal.
+func (f *FileSyncStore) Save(key string, state *SyncState) error {
+	// The journal is removed first, an interruption before the save only losing pages fetched again from the cursor
+	// of the previous save.
+	if err := os.Remove(f.journalPath(key)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return jsonFileStore{dir: f.Dir}.save(key, state)
+}
+
+// Append appends the state and the pull requests of a page to the journal of the given key.
+func (f *FileSyncStore) Append(key string, state *SyncState, nodes AllPullRequestsNodes) error {
+	if err := os.MkdirAll(f.Dir, 0755); err != nil {
+		return err
+	}
+
+	page := *state
+	page.Nodes = nodes
+
+	data, err := json.Marshal(page)
+	if err != nil {
+		return err
+	}
+
+	journal, err := os.OpenFile(f.journalPath(key), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+
+	if _, err := journal.Write(append([]byte{'\n'}, data...)); err != nil {
+		journal.Close()
+		return err
+	}
+
+	return journal.Close()
+}
+
+// NewFileSyncStore returns a pointer to the FileSyncStore struct.
+func NewFileSyncStore(dir string) *FileSyncStore {
+	return &FileSyncStore{Dir: dir}
+}
+
+// SyncPullRequestsParams represents the SyncPullRequests parameters.
+type SyncPullRequestsParams struct {
+	// Owner is the repository owner.
+	Owner string
+	// Repo is the repository name.
+	Repo string
+
+	// MaxPages is the maximum number of pages to fetch, defaults to the GitHub component maximum.
+	MaxPages int
+}
+
+type SyncPullRequestsQuery struct {
+	Repository SyncPullRequestsRepository `graphql:"repository(owner: $repositoryOwner, name: $repositoryName)"`
+	RateLimit  RateLimit                  `graphql:"rateLimit"`
+}
+
+func (q *SyncPullRequestsQuery) rateLimit() RateLimit { return q.RateLimit }
+
+type SyncPullRequestsRepository struct {
+	PullRequests AllPullRequestsPullRequests `graphql:"pullRequests(first: $pullRequestsFirst, after: $pullRequestsAfter, orderBy: {field: UPDATED_AT, direction: DESC})"`
+}
+
+// SyncPullRequests fetches the pull requests of every state updated since the last sync, most recently
+// updated first, and merges them into the persisted copy of the repository. Once synced, a repository
+// costs a single page per call. A sync interrupted by an error or the maximum number of pages resumes
+// from its last page on the next call, in which case the returned state has a cursor.
+func (gh *GitHub) SyncPullRequests(ctx context.Context, params SyncPullRequestsParams) (*SyncState, error) {
+	ctx = WithOwner(ctx, params.Owner)
+
+	if gh.Syncs == nil {
+		return nil, fmt.Errorf("sync store not configured")
+	}
+
+	maxPages := params.MaxPages
+	if maxPages <= 0 {
+		maxPages = gh.MaxPages
+	}
+
+	key := syncPullRequestsKey(params.Owner, params.Repo)
+
+	// Concurrent syncs of a repository would overwrite each other's merged pull requests and watermark.
+	lock, _ := gh.syncLocks.LoadOrStore(key, &sync.Mutex{})
+	lock.(*sync.Mutex).Lock()
+	defer lock.(*sync.Mutex).Unlock()
+
+	state, err := gh.Syncs.Load(key)
+	if err != nil {
+		return nil, err
+	}
+	if state == nil {
+		state = &SyncState{Version: SyncStateVersion}
+	}
+
+	// Pull requests synced by another version lack the fields of this one, and incremental syncs never fetch the
+	// ones not updated since, so every pull request is fetched again, replacing the synced ones.
+	if state.Version != SyncStateVersion {
+		state.Version = SyncStateVersion
+		state.Watermark = time.Time{}
+		state.PendingWatermark = time.Time{}
+		state.Cursor = ""
+	}
+
+	index := state.index()
+
+	var cursor *githubv4.String
+	if state.Cursor != "" {
+		c := githubv4.String(state.Cursor)
+		cursor = &c
+	}
+
+	done := false
+
+	for pages := 0; !done && (maxPages <= 0 || pages < maxPages); pages++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		query := SyncPullRequestsQuery{}
+
+		variables := map[string]interface{}{
+			"repositoryOwner":              githubv4.String(params.Owner),
+			"repositoryName":               githubv4.String(params.Repo),
+			"pullRequestsFirst":            githubv4.Int(100),
+			"participantsFirst":            githubv4.Int(100),
+			"commitsFirst":                 githubv4.Int(100),
+			"reviewsFirst":                 githubv4.Int(100),
+			"reviewRequestsFirst":          githubv4.Int(100),
+			"pullRequestsAfter":            cursor,
+			"closingIssuesReferencesFirst": githubv4.Int(100),
+			"labelsFirst":                  githubv4.Int(100),
+			"assigneesFirst":               githubv4.Int(100),
+			"filesFirst":                   githubv4.Int(100),
+		}
+
+		if err := gh.Query(ctx, &query, variables); err != nil {
+			return nil, err
+		}
+
+		nodes := AllPullRequestsNodes{}
+		for _, node := range query.Repository.PullRequests.Nodes {
+			// Pull requests updated before the watermark were already synced, and so are the following ones.
+			if node.UpdatedAt.Before(state.Watermark) {
+				done = true
+				break
+			}
+			nodes = append(nodes, node)
+		}
+
+		// Only the files of the pull requests to sync are completed, the already synced ones are thrown away.
+		if err := gh.completeConnections(ctx, params.Owner, params.Repo, nodes); err != nil {
+			return nil, err
+		}
+
+		for _, node := range nodes {
+			if node.UpdatedAt.After(state.PendingWatermark) {
+				state.PendingWatermark = node.UpdatedAt.UTC()
+			}
+		}
+		state.merge(index, nodes)
+
+		pageInfo := query.Repository.PullRequests.PageInfo
+		if !pageInfo.HasNextPage {
+			done = true
+		}
+
+		next := pageInfo.EndCursor
+		cursor = &next
+		state.Cursor = string(next)
+
+		// Only the page is appended, the whole state being saved once at the end.
+		if !done {
+			if err := gh.Syncs.Append(key, state, nodes); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if done {
+		if state.PendingWatermark.After(state.Watermark) {
+			state.Watermark = state.PendingWatermark
+		}
+		state.PendingWatermark = time.Time{}
+		state.Cursor = ""
+	}
+
+	if err := gh.Syncs.Save(key, state); err != nil {
+		return nil, err
+	}
+
+	return state, nil
+}
+
+// allPullRequestsFromSync returns the synced pull requests of the given states, most recently created first.
+func (gh *GitHub) allPullRequestsFromSync(ctx context.Context, params AllPullRequestsParams, states []githubv4.PullRequestState) (AllPullRequestsQuery, error) {
+	query := AllPullRequestsQuery{}
+
+	state, err := gh.SyncPullRequests(ctx, SyncPullRequestsParams{
+		Owner:    params.Owner,
+		Repo:     params.Repo,
+		MaxPages: params.MaxPages,
+	})
+	if err != nil {
+		return query, err
+	}
+
+	nodes := AllPullRequestsNodes{}
+	for _, node := range state.Nodes {
+		if slices.Contains(states, node.State) {
+			nodes = append(nodes, node)
+		}
+	}
+
+	slices.SortFunc(nodes, func(a, b AllPullRequestsNode) int {
+		return b.CreatedAt.Compare(a.CreatedAt.Time)
+	})
+
+	query.Repository.PullRequests.Nodes = nodes
+	query.Repository.PullRequests.PageInfo = PageInfo{
+		HasNextPage: githubv4.Boolean(state.Cursor != ""),
+		EndCursor:   githubv4.String(state.Cursor),
+	}
+
+	return query, nil
+}
+
+// syncPullRequestsKey returns the sync store key of a repository, case-insensitive like its owner and name.
+func syncPullRequestsKey(owner, repo string) string {
+	return strings.ToLower(fmt.Sprintf("sync/%s/%s", owner, repo))
+}
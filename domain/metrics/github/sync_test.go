@@ -0,0 +1,354 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+)
+
+// syncPullRequestsPage returns a GraphQL response of a page of pull requests updated at the given dates.
+func syncPullRequestsPage(hasNextPage bool, endCursor string, nodes ...string) string {
+	return fmt.Sprintf(`{"data":{"repository":{"pullRequests":{"nodes":[%s],"pageInfo":{"hasNextPage":%v,"endCursor":%q}}}}}`, strings.Join(nodes, ","), hasNextPage, endCursor)
+}
+
+// syncPullRequestNode returns the JSON of a pull request node.
+func syncPullRequestNode(number int, state string, updatedAt string) string {
+	return fmt.Sprintf(`{"number":%d,"state":%q,"createdAt":"2024-01-0%dT00:00:00Z","updatedAt":%q}`, number, state, number, updatedAt)
+}
+
+func TestAllPullRequestsSyncsIncrementally(t *testing.T) {
+	requests := 0
+	synced := false
+
+	server := newGraphQLServer(t, func(req graphQLRequest) (int, string) {
+		requests++
+		cursor, _ := req.Variables["pullRequestsAfter"].(string)
+
+		if !synced {
+			if cursor == "" {
+				return http.StatusOK, syncPullRequestsPage(true, "cursor-1",
+					syncPullRequestNode(3, "OPEN", "2024-02-03T00:00:00Z"),
+					syncPullRequestNode(2, "MERGED", "2024-02-02T00:00:00Z"),
+				)
+			}
+			return http.StatusOK, syncPullRequestsPage(false, "cursor-2",
+				syncPullRequestNode(1, "MERGED", "2024-02-01T00:00:00Z"),
+			)
+		}
+
+		if _, ok := req.Variables["filesAfter"]; ok {
+			t.Errorf("expected no files fetched for the already synced pull requests")
+		}
+
+		// Pull request 3 got merged since the last sync, the files of the already synced pull request 2 have
+		// another page.
+		return http.StatusOK, syncPullRequestsPage(true, "cursor-3",
+			syncPullRequestNode(3, "MERGED", "2024-02-04T00:00:00Z"),
+			`{"number":2,"state":"MERGED","createdAt":"2024-01-02T00:00:00Z","updatedAt":"2024-02-02T00:00:00Z","files":{"nodes":[],"pageInfo":{"hasNextPage":true,"endCursor":"files-1"}}}`,
+		)
+	})
+
+	gh := &GitHub{
+		Client: githubv4.NewEnterpriseClient(server.URL, server.Client()),
+		Syncs:  NewFileSyncStore(t.TempDir()),
+	}
+
+	result, err := gh.AllPullRequests(context.Background(), AllPullRequestsParams{Owner: "owner", Repo: "repo"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if requests != 2 {
+		t.Fatalf("expected the first sync to fetch 2 pages, got %d", requests)
+	}
+
+	if len(result.Repository.PullRequests.Nodes) != 2 {
+		t.Fatalf("expected 2 merged pull requests, got %d", len(result.Repository.PullRequests.Nodes))
+	}
+
+	synced = true
+	requests = 0
+
+	result, err = gh.AllPullRequests(context.Background(), AllPullRequestsParams{Owner: "owner", Repo: "repo"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if requests != 1 {
+		t.Errorf("expected the incremental sync to fetch 1 page, got %d", requests)
+	}
+
+	numbers := []int{}
+	for _, node := range result.Repository.PullRequests.Nodes {
+		numbers = append(numbers, int(node.Number))
+	}
+	if fmt.Sprint(numbers) != "[3 2 1]" {
+		t.Errorf("expected merged pull requests [3 2 1] most recently created first, got %v", numbers)
+	}
+
+	if result.Repository.PullRequests.PageInfo.HasNextPage {
+		t.Errorf("expected complete result")
+	}
+}
+
+func TestSyncPullRequestsResumesAfterMaxPages(t *testing.T) {
+	server := newGraphQLServer(t, func(req graphQLRequest) (int, string) {
+		if req.Variables["pullRequestsAfter"] == nil {
+			return http.StatusOK, syncPullRequestsPage(true, "cursor-1",
+				syncPullRequestNode(2, "MERGED", "2024-02-02T00:00:00Z"),
+			)
+		}
+		return http.StatusOK, syncPullRequestsPage(false, "cursor-2",
+			syncPullRequestNode(1, "MERGED", "2024-02-01T00:00:00Z"),
+		)
+	})
+
+	gh := &GitHub{
+		Client: githubv4.NewEnterpriseClient(server.URL, server.Client()),
+		Syncs:  NewFileSyncStore(t.TempDir()),
+	}
+
+	state, err := gh.SyncPullRequests(context.Background(), SyncPullRequestsParams{Owner: "owner", Repo: "repo", MaxPages: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if state.Cursor != "cursor-1" || !state.Watermark.IsZero() {
+		t.Fatalf("expected interrupted sync without watermark, got cursor %q and watermark %v", state.Cursor, state.Watermark)
+	}
+
+	// Owners and repository names are case-insensitive, so the sync resumes whatever their case.
+	state, err = gh.SyncPullRequests(context.Background(), SyncPullRequestsParams{Owner: "Owner", Repo: "Repo", MaxPages: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if state.Cursor != "" || len(state.Nodes) != 2 {
+		t.Fatalf("expected complete sync of 2 pull requests, got cursor %q and %d pull requests", state.Cursor, len(state.Nodes))
+	}
+
+	if got := state.Watermark.Format("2006-01-02"); got != "2024-02-02" {
+		t.Errorf("expected watermark of the most recently updated pull request, got %s", got)
+	}
+}
+
+func TestAllPullRequestsResumesInterruptedSync(t *testing.T) {
+	requests := map[string]int{}
+	failSecondPage := true
+
+	server := newGraphQLServer(t, func(req graphQLRequest) (int, string) {
+		cursor, _ := req.Variables["pullRequestsAfter"].(string)
+		requests[cursor]++
+
+		if cursor == "" {
+			return http.StatusOK, syncPullRequestsPage(true, "cursor-1", syncPullRequestNode(1, "MERGED", "2024-01-03T00:00:00Z"))
+		}
+		if failSecondPage {
+			return http.StatusInternalServerError, "internal error"
+		}
+		return http.StatusOK, syncPullRequestsPage(false, "cursor-2", syncPullRequestNode(2, "MERGED", "2024-01-02T00:00:00Z"))
+	})
+
+	syncs := NewFileSyncStore(t.TempDir())
+	gh := &GitHub{
+		Client: githubv4.NewEnterpriseClient(server.URL, server.Client()),
+		Syncs:  syncs,
+	}
+
+	if _, err := gh.AllPullRequests(context.Background(), AllPullRequestsParams{Owner: "owner", Repo: "repo"}); err == nil {
+		t.Fatalf("expected error on interrupted fetch")
+	}
+
+	failSecondPage = false
+
+	result, err := gh.AllPullRequests(context.Background(), AllPullRequestsParams{Owner: "owner", Repo: "repo"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.Repository.PullRequests.Nodes) != 2 {
+		t.Fatalf("expected 2 pull requests, got %d", len(result.Repository.PullRequests.Nodes))
+	}
+
+	if requests[""] != 1 {
+		t.Errorf("expected first page to be fetched once, got %d", requests[""])
+	}
+
+	state, err := syncs.Load(syncPullRequestsKey("owner", "repo"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if state == nil || state.Cursor != "" {
+		t.Errorf("expected the sync cursor to be cleared after a complete sync, got %+v", state)
+	}
+}
+
+func TestSyncPullRequestsSerializesSyncsOfARepository(t *testing.T) {
+	var running, maxRunning atomic.Int32
+
+	server := newGraphQLServer(t, func(req graphQLRequest) (int, string) {
+		if n := running.Add(1); n > maxRunning.Load() {
+			maxRunning.Store(n)
+		}
+		defer running.Add(-1)
+		time.Sleep(5 * time.Millisecond)
+
+		return http.StatusOK, syncPullRequestsPage(false, "cursor-1",
+			syncPullRequestNode(2, "MERGED", "2024-02-02T00:00:00Z"),
+			syncPullRequestNode(1, "MERGED", "2024-02-01T00:00:00Z"),
+		)
+	})
+
+	dir := t.TempDir()
+	gh := &GitHub{
+		Client: githubv4.NewEnterpriseClient(server.URL, server.Client()),
+		Syncs:  NewFileSyncStore(dir),
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 4)
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := gh.SyncPullRequests(context.Background(), SyncPullRequestsParams{Owner: "owner", Repo: "repo"}); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if maxRunning.Load() != 1 {
+		t.Errorf("expected the syncs of a repository to run one at a time, got %d at once", maxRunning.Load())
+	}
+
+	state, err := gh.Syncs.Load(syncPullRequestsKey("owner", "repo"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(state.Nodes) != 2 {
+		t.Errorf("expected 2 synced pull requests, got %d", len(state.Nodes))
+	}
+
+	tmpFiles, _ := filepath.Glob(filepath.Join(dir, "**", "*.tmp"))
+	if len(tmpFiles) != 0 {
+		t.Errorf("expected no temporary files left, got %v", tmpFiles)
+	}
+}
+
+func TestSyncPullRequestsResyncsStatesOfAnotherVersion(t *testing.T) {
+	requests := 0
+
+	server := newGraphQLServer(t, func(req graphQLRequest) (int, string) {
+		requests++
+		if req.Variables["pullRequestsAfter"] == nil {
+			return http.StatusOK, syncPullRequestsPage(true, "cursor-1",
+				syncPullRequestNode(2, "MERGED", "2024-02-02T00:00:00Z"),
+			)
+		}
+		return http.StatusOK, syncPullRequestsPage(false, "cursor-2",
+			`{"number":1,"state":"MERGED","createdAt":"2024-01-01T00:00:00Z","updatedAt":"2024-02-01T00:00:00Z","baseRefName":"main"}`,
+		)
+	})
+
+	gh := &GitHub{
+		Client: githubv4.NewEnterpriseClient(server.URL, server.Client()),
+		Syncs:  NewFileSyncStore(t.TempDir()),
+	}
+
+	// A sync of a previous version, complete but without the base reference names.
+	previous := &SyncState{
+		Watermark: time.Date(2024, 2, 2, 0, 0, 0, 0, time.UTC),
+		Nodes: AllPullRequestsNodes{
+			{Number: 2, State: githubv4.PullRequestStateMerged},
+			{Number: 1, State: githubv4.PullRequestStateMerged},
+		},
+	}
+	if err := gh.Syncs.Save(syncPullRequestsKey("owner", "repo"), previous); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	state, err := gh.SyncPullRequests(context.Background(), SyncPullRequestsParams{Owner: "owner", Repo: "repo"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if requests != 2 {
+		t.Errorf("expected a full sync of 2 pages, got %d", requests)
+	}
+
+	if state.Version != SyncStateVersion || len(state.Nodes) != 2 || state.Nodes[1].BaseRefName != "main" {
+		t.Errorf("expected the synced pull requests fetched again, got version %d and %+v", state.Version, state.Nodes)
+	}
+}
+
+func TestFileSyncStoreAppendsPages(t *testing.T) {
+	syncs := NewFileSyncStore(t.TempDir())
+	key := syncPullRequestsKey("owner", "repo")
+
+	saved := &SyncState{
+		Version: SyncStateVersion,
+		Nodes: AllPullRequestsNodes{
+			{Number: 1, State: githubv4.PullRequestStateOpen},
+		},
+	}
+	if err := syncs.Save(key, saved); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := syncs.Append(key, &SyncState{Version: SyncStateVersion, Cursor: "cursor-1"}, AllPullRequestsNodes{
+		{Number: 2, State: githubv4.PullRequestStateMerged},
+		{Number: 1, State: githubv4.PullRequestStateMerged},
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The partial write of an interrupted append.
+	journal, err := os.OpenFile(syncs.journalPath(key), os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	journal.WriteString("\n" + `{"Version":1,"Cursor":"cursor-`)
+	journal.Close()
+
+	if err := syncs.Append(key, &SyncState{Version: SyncStateVersion, Cursor: "cursor-2"}, AllPullRequestsNodes{
+		{Number: 3, State: githubv4.PullRequestStateClosed},
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	state, err := syncs.Load(key)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if state.Cursor != "cursor-2" {
+		t.Errorf("expected the cursor of the last page, got %q", state.Cursor)
+	}
+
+	if len(state.Nodes) != 3 || state.Nodes[0].State != githubv4.PullRequestStateMerged {
+		t.Errorf("expected the pages merged into the saved pull requests, got %+v", state.Nodes)
+	}
+
+	if err := syncs.Save(key, state); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := os.Stat(syncs.journalPath(key)); !os.IsNotExist(err) {
+		t.Errorf("expected the journal removed on save, got %v", err)
+	}
+}
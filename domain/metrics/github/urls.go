@@ -1,62 +1,364 @@
 package github
 
 import (
-	"errors"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 
 	"github.com/chris-ramon/golang-scaffolding/domain/metrics/types"
 )
 
-// RepositoryFromURL returns owner and repo from a GitHub repository URL.
-func RepositoryFromURL(url string) (owner, repo string, err error) {
-	parts := strings.Split(url, "/")
-	if len(parts) < 5 {
-		return "", "", errors.New("invalid repository URL format")
+// DefaultHost is the host of the github.com repositories.
+const DefaultHost = "github.com"
+
+// LocalHost is the host of the repositories cloned on the local filesystem, referenced by their path or
+// `file://` URL. Their owner is the parent directory and their repo the directory name. It is the `file://`
+// scheme rather than a hostname so that it never collides with a forge served on `localhost`.
+const LocalHost = "file://"
+
+// RepoRef is a reference to a repository of github.com, a GitHub Enterprise Server, GitLab or Gitea host.
+type RepoRef struct {
+	Host string
+	// Owner is the repository owner, the possibly nested namespace of GitLab projects.
+	Owner string
+	Repo  string
+}
+
+// URL returns the canonical URL of the repository.
+func (r RepoRef) URL() string {
+	if r.Host == LocalHost {
+		path := r.Path()
+		switch {
+		case filepath.IsAbs(path):
+			return "file://" + path
+		case path == ".." || strings.HasPrefix(path, "../"):
+			return path
+		default:
+			return "./" + path
+		}
+	}
+
+	return fmt.Sprintf("https://%s/%s/%s", r.Host, r.Owner, r.Repo)
+}
+
+// Path returns the directory of a repository of the local filesystem, relative to the local repositories
+// directory when not absolute.
+func (r RepoRef) Path() string {
+	return filepath.Join(r.Owner, r.Repo)
+}
+
+// String returns the host, owner and repo of the repository, the URL of local repositories.
+func (r RepoRef) String() string {
+	if r.Host == LocalHost {
+		return r.URL()
 	}
 
-	owner = parts[3]
-	repo = parts[4]
+	return fmt.Sprintf("%s/%s/%s", r.Host, r.Owner, r.Repo)
+}
 
-	return owner, repo, nil
+// OwnerRef is a reference to the owner of repositories, an organization or a user, of a host.
+type OwnerRef struct {
+	Host  string
+	Owner string
 }
 
-// PullRequestsFromURLs returns a slice of pull requests from given pull requests URLs.
-func PullRequestsFromURLs(urls []string) (types.PullRequests, error) {
-	result := types.PullRequests{}
+// URL returns the canonical URL of the owner.
+func (r OwnerRef) URL() string {
+	return fmt.Sprintf("https://%s/%s", r.Host, r.Owner)
+}
+
+// PullRequestRef is a reference to a pull request, or a GitLab merge request, of a repository.
+type PullRequestRef struct {
+	RepoRef
+	Number int
+
+	// path is the path of the pull requests below the repository URL, `pull` when empty, `pulls` on Gitea
+	// and `-/merge_requests` on GitLab.
+	path string
+}
+
+// URL returns the canonical URL of the pull request.
+func (r PullRequestRef) URL() string {
+	if r.Host == LocalHost {
+		return fmt.Sprintf("%s#%d", r.RepoRef.URL(), r.Number)
+	}
+
+	path := r.path
+	if path == "" {
+		path = "pull"
+	}
+
+	return fmt.Sprintf("%s/%s/%d", r.RepoRef.URL(), path, r.Number)
+}
+
+// String returns the owner/repo#number shorthand of the pull request, prefixed by its host.
+func (r PullRequestRef) String() string {
+	return fmt.Sprintf("%s#%d", r.RepoRef.String(), r.Number)
+}
+
+// ParseRepoRef parses a repository reference, one of:
+//   - a repository URL or any URL below it, like https://github.com/owner/repo.git or
+//     https://github.com/owner/repo/pull/1/files?diff=split
+//   - an SSH remote, like git@github.com:owner/repo.git or ssh://git@github.com/owner/repo.git
+//   - a host-less owner/repo shorthand, which refers to github.com
+//   - an absolute or ./ relative path, or a file:// URL, of a repository of the local filesystem
+//
+// The namespace of a GitLab project nested in subgroups is only told apart from the path below the project
+// by the `/-/` separator of the GitLab URLs, like https://gitlab.com/group/subgroup/project/-/merge_requests.
+func ParseRepoRef(ref string) (RepoRef, error) {
+	if path, ok := localPath(ref); ok {
+		return localRepoRef(path)
+	}
 
-	for _, url := range urls {
-		pr, err := PullRequestFromURL(url)
+	repoRef, _, _, err := parseRef(ref)
+	if err != nil {
+		return RepoRef{}, fmt.Errorf("invalid repository reference %q: %w", ref, err)
+	}
+
+	return repoRef, nil
+}
+
+// ParseOwnerRef parses an owner reference, either an owner URL like https://github.com/owner or an owner
+// name optionally prefixed by the host, like github.example.com/owner.
+func ParseOwnerRef(ref string) (OwnerRef, error) {
+	ref = strings.TrimSpace(ref)
+	host, path := "", ref
+
+	if strings.Contains(ref, "://") {
+		u, err := url.Parse(ref)
 		if err != nil {
-			return nil, err
+			return OwnerRef{}, fmt.Errorf("invalid owner reference %q: %w", ref, err)
 		}
+		host, path = u.Host, u.Path
+	}
 
-		result = append(result, *pr)
+	parts := []string{}
+	for _, part := range strings.Split(path, "/") {
+		if part != "" {
+			parts = append(parts, part)
+		}
 	}
 
-	return result, nil
+	if host == "" && len(parts) > 1 && strings.Contains(parts[0], ".") {
+		host, parts = parts[0], parts[1:]
+	}
+	if host == "" {
+		host = DefaultHost
+	}
+
+	if len(parts) != 1 || strings.ContainsAny(parts[0], " \t:@#") {
+		return OwnerRef{}, fmt.Errorf("invalid owner reference %q", ref)
+	}
+
+	return OwnerRef{Host: strings.ToLower(host), Owner: parts[0]}, nil
 }
 
-// PullRequestFromURL returns a pull request type from given pull request URL.
-func PullRequestFromURL(url string) (*types.PullRequest, error) {
-	parts := strings.Split(url, "/")
-	if len(parts) == 0 {
-		return nil, errors.New("failed to split url parts")
+// ParsePullRequestRef parses a pull request reference, either a pull request URL like
+// https://github.com/owner/repo/pull/1/files or https://gitlab.com/group/project/-/merge_requests/1, or an
+// owner/repo#1 or GitLab group/project!1 shorthand, optionally prefixed by the host. Pull requests of local
+// repositories are referenced by the repository path or file:// URL followed by #1.
+func ParsePullRequestRef(ref string) (PullRequestRef, error) {
+	if base, number, found := strings.Cut(ref, "#"); found {
+		if path, ok := localPath(base); ok {
+			repoRef, err := localRepoRef(path)
+			if err != nil {
+				return PullRequestRef{}, err
+			}
+
+			n, err := pullRequestNumber(ref, number)
+			if err != nil {
+				return PullRequestRef{}, err
+			}
+
+			return PullRequestRef{RepoRef: repoRef, Number: n}, nil
+		}
+	}
+
+	shorthand := ref
+	path := ""
+	if i := strings.LastIndex(ref, "!"); i >= 0 && !strings.Contains(ref, "://") {
+		shorthand, path = ref[:i]+"#"+ref[i+1:], "-/merge_requests"
+	}
+
+	repoRef, rest, fragment, err := parseRef(shorthand)
+	if err != nil {
+		return PullRequestRef{}, fmt.Errorf("invalid pull request reference %q: %w", ref, err)
+	}
+
+	var number string
+	switch {
+	case len(rest) >= 2 && rest[0] == "pull":
+		number = rest[1]
+	case len(rest) >= 2 && rest[0] == "pulls":
+		number, path = rest[1], "pulls"
+	case len(rest) >= 2 && rest[0] == "merge_requests":
+		number, path = rest[1], "-/merge_requests"
+	case len(rest) == 0 && fragment != "":
+		number = fragment
+	default:
+		return PullRequestRef{}, fmt.Errorf("invalid pull request reference %q: missing pull request number", ref)
+	}
+
+	n, err := pullRequestNumber(ref, number)
+	if err != nil {
+		return PullRequestRef{}, err
+	}
+
+	return PullRequestRef{RepoRef: repoRef, Number: n, path: path}, nil
+}
+
+// pullRequestNumber returns the given pull request number of the given reference.
+func pullRequestNumber(ref string, number string) (int, error) {
+	n, err := strconv.Atoi(number)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid pull request reference %q: invalid pull request number %q", ref, number)
+	}
+
+	return n, nil
+}
+
+// parseRef returns the repository of the given reference, along with the path parts following it and the
+// fragment of the reference.
+func parseRef(ref string) (repoRef RepoRef, rest []string, fragment string, err error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return RepoRef{}, nil, "", fmt.Errorf("empty reference")
 	}
 
-	owner := parts[3]
-	repo := parts[4]
+	var host, path string
+	shorthand := false
+
+	switch {
+	case strings.Contains(ref, "://"):
+		u, err := url.Parse(ref)
+		if err != nil {
+			return RepoRef{}, nil, "", err
+		}
+
+		host = u.Host
+		if u.Scheme == "ssh" || u.Scheme == "git" {
+			host = u.Hostname()
+		}
+		path, fragment = u.Path, u.Fragment
+	case isSCPLike(ref):
+		// git@github.com:owner/repo.git
+		userHost, p, _ := strings.Cut(ref, ":")
+		if _, h, found := strings.Cut(userHost, "@"); found {
+			userHost = h
+		}
+		host, path = userHost, p
+	default:
+		// github.com/owner/repo or owner/repo#1
+		path, fragment, shorthand = strings.Cut(ref, "#")
+		path, _, _ = strings.Cut(path, "?")
+	}
+
+	parts := []string{}
+	for _, part := range strings.Split(path, "/") {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+
+	if host == "" && len(parts) > 0 && strings.Contains(parts[0], ".") {
+		host, parts = parts[0], parts[1:]
+	}
+	if host == "" {
+		host = DefaultHost
+	}
+
+	// The project of GitLab URLs ends before the `-` separator, and shorthands are only made of the project,
+	// below namespaces nested in subgroups.
+	end := 2
+	if i := slices.Index(parts, "-"); i >= 2 {
+		end = i
+	} else if shorthand && len(parts) > 2 {
+		end = len(parts)
+	}
+
+	if len(parts) < end {
+		return RepoRef{}, nil, "", fmt.Errorf("missing owner or repository")
+	}
 
-	number, err := strconv.Atoi(parts[6])
+	owner := strings.Join(parts[:end-1], "/")
+	repo := strings.TrimSuffix(parts[end-1], ".git")
+	if repo == "" || strings.ContainsAny(owner+repo, " \t:@") {
+		return RepoRef{}, nil, "", fmt.Errorf("invalid owner or repository")
+	}
+
+	rest = parts[end:]
+	if len(rest) > 0 && rest[0] == "-" {
+		rest = rest[1:]
+	}
+
+	return RepoRef{Host: strings.ToLower(host), Owner: owner, Repo: repo}, rest, fragment, nil
+}
+
+// localPath returns the path of the given reference when it refers to the local filesystem.
+func localPath(ref string) (string, bool) {
+	ref = strings.TrimSpace(ref)
+
+	switch {
+	case strings.HasPrefix(ref, "file://"):
+		u, err := url.Parse(ref)
+		if err != nil {
+			return "", false
+		}
+		return u.Path, true
+	case filepath.IsAbs(ref), strings.HasPrefix(ref, "./"), strings.HasPrefix(ref, "../"):
+		return ref, true
+	default:
+		return "", false
+	}
+}
+
+// localRepoRef returns the reference of the repository of the given local path. Relative paths are kept
+// relative, the git log component resolves them against the local repositories directory rather than the
+// working directory of the process.
+func localRepoRef(path string) (RepoRef, error) {
+	dir, name := filepath.Split(filepath.Clean(path))
+	if name == "" || name == "." || name == ".." {
+		return RepoRef{}, fmt.Errorf("invalid repository path %q", path)
+	}
+
+	return RepoRef{Host: LocalHost, Owner: filepath.Clean(dir), Repo: name}, nil
+}
+
+// isSCPLike returns whether the given reference is a scp-like SSH remote, where the host is separated from
+// the path by a colon.
+func isSCPLike(ref string) bool {
+	colon := strings.Index(ref, ":")
+	slash := strings.Index(ref, "/")
+
+	return colon > 0 && (slash < 0 || colon < slash)
+}
+
+// RepositoryFromURL returns owner and repo from a GitHub or GitHub Enterprise Server repository reference.
+func RepositoryFromURL(url string) (owner, repo string, err error) {
+	repoRef, err := ParseRepoRef(url)
+	if err != nil {
+		return "", "", err
+	}
+
+	return repoRef.Owner, repoRef.Repo, nil
+}
+
+// PullRequestFromURL returns a pull request type from given pull request reference, with its canonical URL.
+func PullRequestFromURL(url string) (*types.PullRequest, error) {
+	ref, err := ParsePullRequestRef(url)
 	if err != nil {
 		return nil, err
 	}
 
 	result := &types.PullRequest{
-		Owner:  owner,
-		Repo:   repo,
-		Number: number,
-		URL:    url,
+		Host:   ref.Host,
+		Owner:  ref.Owner,
+		Repo:   ref.Repo,
+		Number: ref.Number,
+		URL:    ref.URL(),
 	}
 
 	return result, nil
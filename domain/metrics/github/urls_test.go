@@ -0,0 +1,173 @@
+package github
+
+import (
+	"testing"
+)
+
+func TestParseRepoRef(t *testing.T) {
+	testCases := []struct {
+		name        string
+		ref         string
+		expected    RepoRef
+		expectError bool
+	}{
+		{name: "repository URL", ref: "https://github.com/graphql-go/graphql", expected: RepoRef{Host: "github.com", Owner: "graphql-go", Repo: "graphql"}},
+		{name: "trailing .git and slash", ref: "https://github.com/graphql-go/graphql.git/", expected: RepoRef{Host: "github.com", Owner: "graphql-go", Repo: "graphql"}},
+		{name: "query string", ref: "https://GitHub.com/graphql-go/graphql?tab=readme", expected: RepoRef{Host: "github.com", Owner: "graphql-go", Repo: "graphql"}},
+		{name: "URL below the repository", ref: "https://github.com/graphql-go/graphql/pull/117/files", expected: RepoRef{Host: "github.com", Owner: "graphql-go", Repo: "graphql"}},
+		{name: "enterprise host", ref: "https://github.example.com:8443/team/service", expected: RepoRef{Host: "github.example.com:8443", Owner: "team", Repo: "service"}},
+		{name: "SSH remote", ref: "git@github.com:graphql-go/graphql.git", expected: RepoRef{Host: "github.com", Owner: "graphql-go", Repo: "graphql"}},
+		{name: "SSH URL", ref: "ssh://git@github.example.com:22/team/service.git", expected: RepoRef{Host: "github.example.com", Owner: "team", Repo: "service"}},
+		{name: "host without scheme", ref: "github.example.com/team/service", expected: RepoRef{Host: "github.example.com", Owner: "team", Repo: "service"}},
+		{name: "shorthand", ref: " graphql-go/graphql ", expected: RepoRef{Host: "github.com", Owner: "graphql-go", Repo: "graphql"}},
+		{name: "GitLab project", ref: "https://gitlab.com/gitlab-org/gitlab.git", expected: RepoRef{Host: "gitlab.com", Owner: "gitlab-org", Repo: "gitlab"}},
+		{name: "GitLab project in a subgroup", ref: "https://gitlab.com/group/subgroup/project/-/merge_requests", expected: RepoRef{Host: "gitlab.com", Owner: "group/subgroup", Repo: "project"}},
+		{name: "local path", ref: "/srv/repositories/service/", expected: RepoRef{Host: LocalHost, Owner: "/srv/repositories", Repo: "service"}},
+		{name: "local file URL", ref: "file:///srv/repositories/service.git", expected: RepoRef{Host: LocalHost, Owner: "/srv/repositories", Repo: "service.git"}},
+		{name: "relative local path", ref: "./mirrors/service", expected: RepoRef{Host: LocalHost, Owner: "mirrors", Repo: "service"}},
+		{name: "forge served on localhost", ref: "http://localhost/mirrors/service", expected: RepoRef{Host: "localhost", Owner: "mirrors", Repo: "service"}},
+		{name: "missing repository", ref: "https://github.com/graphql-go", expectError: true},
+		{name: "missing path", ref: "https://github.com", expectError: true},
+		{name: "owner only", ref: "graphql-go", expectError: true},
+		{name: "empty", ref: "", expectError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ref, err := ParseRepoRef(tc.ref)
+
+			if tc.expectError {
+				if err == nil {
+					t.Errorf("expected error but got %+v", ref)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if ref != tc.expected {
+				t.Errorf("expected %+v, got %+v", tc.expected, ref)
+			}
+		})
+	}
+}
+
+func TestParseOwnerRef(t *testing.T) {
+	testCases := []struct {
+		name        string
+		ref         string
+		expected    OwnerRef
+		expectError bool
+	}{
+		{name: "owner URL", ref: "https://github.com/graphql-go/", expected: OwnerRef{Host: "github.com", Owner: "graphql-go"}},
+		{name: "enterprise host", ref: "https://GitHub.example.com/team", expected: OwnerRef{Host: "github.example.com", Owner: "team"}},
+		{name: "host without scheme", ref: "github.example.com/team", expected: OwnerRef{Host: "github.example.com", Owner: "team"}},
+		{name: "owner name", ref: " graphql-go ", expected: OwnerRef{Host: "github.com", Owner: "graphql-go"}},
+		{name: "repository URL", ref: "https://github.com/graphql-go/graphql", expectError: true},
+		{name: "missing owner", ref: "https://github.com", expectError: true},
+		{name: "empty", ref: "", expectError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ref, err := ParseOwnerRef(tc.ref)
+
+			if tc.expectError {
+				if err == nil {
+					t.Errorf("expected error but got %+v", ref)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if ref != tc.expected {
+				t.Errorf("expected %+v, got %+v", tc.expected, ref)
+			}
+		})
+	}
+}
+
+func TestParsePullRequestRef(t *testing.T) {
+	graphqlRepo := RepoRef{Host: "github.com", Owner: "graphql-go", Repo: "graphql"}
+
+	testCases := []struct {
+		name        string
+		ref         string
+		expected    PullRequestRef
+		expectError bool
+	}{
+		{name: "pull request URL", ref: "https://github.com/graphql-go/graphql/pull/117", expected: PullRequestRef{RepoRef: graphqlRepo, Number: 117}},
+		{name: "files tab", ref: "https://github.com/graphql-go/graphql/pull/117/files?diff=split#diff-1", expected: PullRequestRef{RepoRef: graphqlRepo, Number: 117}},
+		{name: "shorthand", ref: "graphql-go/graphql#117", expected: PullRequestRef{RepoRef: graphqlRepo, Number: 117}},
+		{name: "shorthand with host", ref: "github.example.com/team/service#7", expected: PullRequestRef{RepoRef: RepoRef{Host: "github.example.com", Owner: "team", Repo: "service"}, Number: 7}},
+		{name: "merge request URL", ref: "https://gitlab.com/group/subgroup/project/-/merge_requests/12/diffs", expected: PullRequestRef{RepoRef: RepoRef{Host: "gitlab.com", Owner: "group/subgroup", Repo: "project"}, Number: 12, path: "-/merge_requests"}},
+		{name: "Gitea pull request URL", ref: "https://gitea.example.com/mirrors/service/pulls/5/files", expected: PullRequestRef{RepoRef: RepoRef{Host: "gitea.example.com", Owner: "mirrors", Repo: "service"}, Number: 5, path: "pulls"}},
+		{name: "merge request shorthand", ref: "gitlab.com/group/project!12", expected: PullRequestRef{RepoRef: RepoRef{Host: "gitlab.com", Owner: "group", Repo: "project"}, Number: 12, path: "-/merge_requests"}},
+		{name: "local pull request", ref: "file:///srv/repositories/service#7", expected: PullRequestRef{RepoRef: RepoRef{Host: LocalHost, Owner: "/srv/repositories", Repo: "service"}, Number: 7}},
+		{name: "repository URL", ref: "https://github.com/graphql-go/graphql", expectError: true},
+		{name: "issue URL", ref: "https://github.com/graphql-go/graphql/issues/117", expectError: true},
+		{name: "short URL", ref: "https://github.com/graphql-go/graphql/pull", expectError: true},
+		{name: "invalid number", ref: "graphql-go/graphql#abc", expectError: true},
+		{name: "zero number", ref: "https://github.com/graphql-go/graphql/pull/0", expectError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ref, err := ParsePullRequestRef(tc.ref)
+
+			if tc.expectError {
+				if err == nil {
+					t.Errorf("expected error but got %+v", ref)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if ref != tc.expected {
+				t.Errorf("expected %+v, got %+v", tc.expected, ref)
+			}
+		})
+	}
+}
+
+func TestPullRequestRefURL(t *testing.T) {
+	ref, err := ParsePullRequestRef("git@github.example.com:team/service.git")
+	if err == nil {
+		t.Errorf("expected error for a repository reference, got %+v", ref)
+	}
+
+	ref, err = ParsePullRequestRef("github.example.com/team/service#7")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if ref.URL() != "https://github.example.com/team/service/pull/7" {
+		t.Errorf("expected canonical pull request URL, got %s", ref.URL())
+	}
+
+	ref, err = ParsePullRequestRef("gitlab.com/group/subgroup/project!12")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if ref.URL() != "https://gitlab.com/group/subgroup/project/-/merge_requests/12" {
+		t.Errorf("expected canonical merge request URL, got %s", ref.URL())
+	}
+
+	ref, err = ParsePullRequestRef("./service#7")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if ref.URL() != "./service#7" {
+		t.Errorf("expected the relative local pull request URL, got %s", ref.URL())
+	}
+}
@@ -0,0 +1,431 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/chris-ramon/golang-scaffolding/config"
+	"github.com/chris-ramon/golang-scaffolding/domain/metrics/provider"
+	"github.com/chris-ramon/golang-scaffolding/domain/metrics/rest"
+	"github.com/chris-ramon/golang-scaffolding/domain/metrics/types"
+)
+
+// GitLab represents the GitLab component, fetching the merge requests of a GitLab host from its REST API.
+type GitLab struct {
+	// Client is the HTTP client of the GitLab requests.
+	Client *http.Client
+
+	// APIURL is the REST API endpoint, e.g. `https://gitlab.com/api/v4`.
+	APIURL string
+
+	// Token is the access token the requests are authenticated with, anonymous when empty.
+	Token string
+
+	// MaxPages is the maximum number of pages to fetch when paginating, zero means unbounded.
+	MaxPages int
+
+	// Concurrency is the number of merge requests whose participants and diffs are fetched at once, defaults to
+	// `rest.DefaultConcurrency`.
+	Concurrency int
+}
+
+// perPage is the number of merge requests fetched per page, the maximum allowed by GitLab.
+const perPage = 100
+
+// mergeRequest represents a merge request of the GitLab REST API.
+type mergeRequest struct {
+	IID          int        `json:"iid"`
+	Title        string     `json:"title"`
+	Description  string     `json:"description"`
+	State        string     `json:"state"`
+	Draft        bool       `json:"draft"`
+	WebURL       string     `json:"web_url"`
+	SourceBranch string     `json:"source_branch"`
+	TargetBranch string     `json:"target_branch"`
+	CreatedAt    *time.Time `json:"created_at"`
+	UpdatedAt    *time.Time `json:"updated_at"`
+	MergedAt     *time.Time `json:"merged_at"`
+	ClosedAt     *time.Time `json:"closed_at"`
+	Author       user       `json:"author"`
+	Reviewers    []user     `json:"reviewers"`
+	Assignees    []user     `json:"assignees"`
+	Labels       []string   `json:"labels"`
+	Milestone    *struct {
+		Title string `json:"title"`
+	} `json:"milestone"`
+	HeadPipeline *struct {
+		Status string `json:"status"`
+	} `json:"head_pipeline"`
+}
+
+// diff represents a file diff of a merge request of the GitLab REST API.
+type diff struct {
+	NewPath string `json:"new_path"`
+}
+
+// release represents a release of the GitLab REST API.
+type release struct {
+	TagName         string     `json:"tag_name"`
+	Name            string     `json:"name"`
+	Description     string     `json:"description"`
+	CreatedAt       *time.Time `json:"created_at"`
+	ReleasedAt      *time.Time `json:"released_at"`
+	UpcomingRelease bool       `json:"upcoming_release"`
+	Author          user       `json:"author"`
+	Links           struct {
+		Self string `json:"self"`
+	} `json:"_links"`
+}
+
+// user represents a user of the GitLab REST API.
+type user struct {
+	ID       int    `json:"id"`
+	Username string `json:"username"`
+	WebURL   string `json:"web_url"`
+}
+
+// states are the GitLab merge request states of the pull request states.
+var states = map[types.PullRequestState]string{
+	types.OpenPullRequestState:   "opened",
+	types.ClosedPullRequestState: "closed",
+	types.MergedPullRequestState: "merged",
+}
+
+// AllPullRequests fetches the merge requests of the given states from a GitLab project, newest first. It
+// iterates through all the pages unless a maximum number of pages is set, in which case the result reports
+// whether it is truncated. The participants and diffs of the merge requests of each page are fetched a bounded
+// number of merge requests at once.
+func (gl *GitLab) AllPullRequests(ctx context.Context, params provider.AllPullRequestsParams) (*provider.AllPullRequestsResult, error) {
+	pullRequestStates := params.States
+	if len(pullRequestStates) == 0 {
+		pullRequestStates = []types.PullRequestState{types.MergedPullRequestState}
+	}
+
+	result := &provider.AllPullRequestsResult{PullRequests: []*types.PullRequest{}}
+
+	for _, state := range pullRequestStates {
+		s, ok := states[state]
+		if !ok {
+			return nil, fmt.Errorf("unsupported pull request state %q", state)
+		}
+
+		for page := 1; page != 0; {
+			if gl.MaxPages > 0 && page > gl.MaxPages {
+				result.Truncated = true
+				break
+			}
+
+			query := url.Values{
+				"state":    {s},
+				"order_by": {"created_at"},
+				"sort":     {"desc"},
+				"per_page": {strconv.Itoa(perPage)},
+				"page":     {strconv.Itoa(page)},
+			}
+
+			mergeRequests := []mergeRequest{}
+			header, err := gl.client().Get(ctx, projectPath(params.Owner, params.Repo)+"/merge_requests?"+query.Encode(), &mergeRequests)
+			if err != nil {
+				return nil, err
+			}
+
+			pullRequests := make([]*types.PullRequest, len(mergeRequests))
+			err = rest.Each(ctx, len(mergeRequests), gl.Concurrency, func(ctx context.Context, i int) error {
+				pr, err := gl.pullRequest(ctx, params.Host, params.Owner, params.Repo, mergeRequests[i])
+				pullRequests[i] = pr
+				return err
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			for _, pr := range pullRequests {
+				if pr != nil {
+					result.PullRequests = append(result.PullRequests, pr)
+				}
+			}
+
+			page = rest.NextPage(header, page, 0)
+		}
+	}
+
+	return result, nil
+}
+
+// PullRequests fetches the given merge requests, a bounded number at once, reporting the missing ones as not
+// found.
+func (gl *GitLab) PullRequests(ctx context.Context, params provider.PullRequestsParams) ([]provider.PullRequestResult, error) {
+	results := make([]provider.PullRequestResult, len(params.PullRequests))
+
+	err := rest.Each(ctx, len(params.PullRequests), gl.Concurrency, func(ctx context.Context, i int) error {
+		param := params.PullRequests[i]
+
+		mr := mergeRequest{}
+		_, err := gl.client().Get(ctx, fmt.Sprintf("%s/merge_requests/%d", projectPath(param.Owner, param.Repo), param.Number), &mr)
+		if err != nil {
+			results[i].Err = err
+			return nil
+		}
+
+		pr, err := gl.pullRequest(ctx, params.Host, param.Owner, param.Repo, mr)
+		if err != nil {
+			results[i].Err = err
+			return nil
+		}
+		if pr == nil {
+			results[i].Err = fmt.Errorf("unexpected created at nil value")
+			return nil
+		}
+
+		results[i].PullRequest = pr
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// AllReleases fetches the releases of a GitLab project, most recently created first, leaving out the upcoming
+// ones. It iterates through all the pages unless a maximum number of pages is set, in which case the result
+// reports whether it is truncated.
+func (gl *GitLab) AllReleases(ctx context.Context, params provider.AllReleasesParams) (*provider.AllReleasesResult, error) {
+	result := &provider.AllReleasesResult{Releases: []*types.Release{}}
+
+	for page := 1; page != 0; {
+		if gl.MaxPages > 0 && page > gl.MaxPages {
+			result.Truncated = true
+			break
+		}
+
+		query := url.Values{
+			"order_by": {"created_at"},
+			"sort":     {"desc"},
+			"per_page": {strconv.Itoa(perPage)},
+			"page":     {strconv.Itoa(page)},
+		}
+
+		releases := []release{}
+		header, err := gl.client().Get(ctx, projectPath(params.Owner, params.Repo)+"/releases?"+query.Encode(), &releases)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, r := range releases {
+			if r.UpcomingRelease {
+				continue
+			}
+
+			result.Releases = append(result.Releases, &types.Release{
+				TagName:     r.TagName,
+				Host:        params.Host,
+				Owner:       params.Owner,
+				Repo:        params.Repo,
+				Name:        r.Name,
+				Description: r.Description,
+				URL:         r.Links.Self,
+				CreatedAt:   rest.UTC(r.CreatedAt),
+				PublishedAt: rest.UTC(r.ReleasedAt),
+				Author:      types.Author{Login: r.Author.Username},
+			})
+		}
+
+		page = rest.NextPage(header, page, 0)
+	}
+
+	return result, nil
+}
+
+// pullRequest returns the pull request of the given merge request along with its participants and changed
+// files, nil when the merge request is incomplete.
+func (gl *GitLab) pullRequest(ctx context.Context, host string, owner string, repo string, mr mergeRequest) (*types.PullRequest, error) {
+	if mr.CreatedAt == nil {
+		return nil, nil
+	}
+
+	participants := []user{}
+	_, err := gl.client().Get(ctx, fmt.Sprintf("%s/merge_requests/%d/participants", projectPath(owner, repo), mr.IID), &participants)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := gl.files(ctx, owner, repo, mr.IID)
+	if err != nil {
+		return nil, err
+	}
+
+	createdAt := mr.CreatedAt.UTC()
+	pr := &types.PullRequest{
+		Number:       mr.IID,
+		Host:         host,
+		Owner:        owner,
+		Repo:         repo,
+		Title:        mr.Title,
+		Body:         mr.Description,
+		CreatedAt:    &createdAt,
+		MergedAt:     rest.UTC(mr.MergedAt),
+		ClosedAt:     rest.UTC(mr.ClosedAt),
+		UpdatedAt:    rest.UTC(mr.UpdatedAt),
+		State:        pullRequestState(mr.State),
+		IsDraft:      mr.Draft,
+		URL:          mr.WebURL,
+		HeadRefName:  mr.SourceBranch,
+		BaseRefName:  mr.TargetBranch,
+		Contributors: contributors(mr, participants),
+		Labels:       mr.Labels,
+		Files:        files,
+	}
+
+	if pr.State == types.MergedPullRequestState && pr.MergedAt == nil {
+		// Merge requests merged before GitLab recorded the merge time only have an update time.
+		pr.MergedAt = pr.UpdatedAt
+	}
+
+	for _, reviewer := range mr.Reviewers {
+		pr.RequestedReviewers = append(pr.RequestedReviewers, reviewer.Username)
+	}
+
+	for _, assignee := range mr.Assignees {
+		pr.Assignees = append(pr.Assignees, assignee.Username)
+	}
+
+	if mr.Milestone != nil {
+		pr.Milestone = mr.Milestone.Title
+	}
+
+	if mr.HeadPipeline != nil {
+		pr.CIStatus = ciStatus(mr.HeadPipeline.Status)
+	}
+
+	endedAt := time.Now().UTC()
+	if e := pr.EndedAt(); e != nil {
+		endedAt = *e
+	}
+	pr.Duration = endedAt.Sub(createdAt)
+	pr.FormattedContributors = pr.Contributors.FormattedContributors(types.CommasFormatContributorType)
+
+	return pr, nil
+}
+
+// files returns the paths of the files changed by the given merge request, from every page of its diffs.
+func (gl *GitLab) files(ctx context.Context, owner string, repo string, iid int) ([]string, error) {
+	files := []string{}
+
+	for page := 1; page != 0; {
+		query := url.Values{
+			"per_page": {strconv.Itoa(perPage)},
+			"page":     {strconv.Itoa(page)},
+		}
+
+		diffs := []diff{}
+		header, err := gl.client().Get(ctx, fmt.Sprintf("%s/merge_requests/%d/diffs?%s", projectPath(owner, repo), iid, query.Encode()), &diffs)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, d := range diffs {
+			files = append(files, d.NewPath)
+		}
+
+		page = rest.NextPage(header, page, len(files))
+	}
+
+	return files, nil
+}
+
+// contributors returns the merge request contributors: the author first, followed by the participants as
+// reviewers when they were assigned as such, as commenters otherwise.
+func contributors(mr mergeRequest, participants []user) types.Contributors {
+	reviewers := map[int]bool{}
+	for _, reviewer := range mr.Reviewers {
+		reviewers[reviewer.ID] = true
+	}
+
+	contributors := types.Contributors{{
+		Login:      mr.Author.Username,
+		ID:         strconv.Itoa(mr.Author.ID),
+		ProfileURL: mr.Author.WebURL,
+		Role:       types.AuthorContributorRole,
+	}}
+
+	for _, participant := range participants {
+		if participant.ID == mr.Author.ID {
+			continue
+		}
+
+		role := types.CommenterContributorRole
+		if reviewers[participant.ID] {
+			role = types.ReviewerContributorRole
+		}
+
+		contributors = append(contributors, types.Contributor{
+			Login:      participant.Username,
+			ID:         strconv.Itoa(participant.ID),
+			ProfileURL: participant.WebURL,
+			Role:       role,
+		})
+	}
+
+	return contributors
+}
+
+// pullRequestState returns the pull request state of the given merge request state, locked merge requests
+// are still open.
+func pullRequestState(state string) types.PullRequestState {
+	switch state {
+	case "merged":
+		return types.MergedPullRequestState
+	case "closed":
+		return types.ClosedPullRequestState
+	default:
+		return types.OpenPullRequestState
+	}
+}
+
+// ciStatus returns the CI status of the given pipeline status, empty when it has none.
+func ciStatus(status string) string {
+	switch status {
+	case "success":
+		return "SUCCESS"
+	case "failed":
+		return "FAILURE"
+	case "canceled", "skipped":
+		return "ERROR"
+	case "":
+		return ""
+	default:
+		return "PENDING"
+	}
+}
+
+// projectPath returns the API path of the project of the given namespace and name.
+func projectPath(owner string, repo string) string {
+	return "projects/" + url.PathEscape(owner+"/"+repo)
+}
+
+// client returns the REST API client of the GitLab requests.
+func (gl *GitLab) client() *rest.Client {
+	return &rest.Client{
+		HTTPClient:  gl.Client,
+		APIURL:      gl.APIURL,
+		Service:     "GitLab",
+		TokenHeader: "PRIVATE-TOKEN",
+		Token:       gl.Token,
+	}
+}
+
+// NewGitLab returns a GitLab component of the given host configuration.
+func NewGitLab(conf *config.GitLabHostConfig, timeout time.Duration) *GitLab {
+	return &GitLab{
+		Client:      rest.NewHTTPClient(timeout),
+		APIURL:      conf.APIURL,
+		Token:       conf.Token,
+		MaxPages:    conf.MaxPages,
+		Concurrency: rest.DefaultConcurrency,
+	}
+}
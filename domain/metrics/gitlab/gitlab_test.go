@@ -0,0 +1,271 @@
+package gitlab
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/chris-ramon/golang-scaffolding/config"
+	"github.com/chris-ramon/golang-scaffolding/domain/metrics/provider"
+	"github.com/chris-ramon/golang-scaffolding/domain/metrics/types"
+)
+
+// newTestGitLab returns a GitLab component whose API is served by a test server with two pages of merged
+// merge requests and an opened one of the group/subgroup/project project.
+func newTestGitLab(t *testing.T) *GitLab {
+	t.Helper()
+
+	mergeRequest := func(iid int, state string, mergedAt string) string {
+		return fmt.Sprintf(`{
+			"iid": %d, "title": "MR %d", "description": "Description", "state": %q, "draft": false,
+			"web_url": "https://gitlab.example.com/group/subgroup/project/-/merge_requests/%d",
+			"source_branch": "feature-%d", "target_branch": "main",
+			"created_at": "2024-01-01T10:00:00.000Z", "updated_at": "2024-01-03T10:00:00.000Z",
+			"merged_at": %s, "closed_at": null,
+			"author": {"id": 1, "username": "author", "web_url": "https://gitlab.example.com/author"},
+			"reviewers": [{"id": 2, "username": "reviewer", "web_url": "https://gitlab.example.com/reviewer"}],
+			"assignees": [{"id": 1, "username": "author", "web_url": "https://gitlab.example.com/author"}],
+			"labels": ["bug", "backend"], "milestone": {"title": "v1.0"},
+			"head_pipeline": {"status": "success"}
+		}`, iid, iid, state, iid, iid, mergedAt)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("PRIVATE-TOKEN") != "token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		const project = "/api/v4/projects/group%2Fsubgroup%2Fproject"
+		path := r.URL.EscapedPath()
+
+		switch {
+		case path == project+"/merge_requests" && r.URL.Query().Get("state") == "merged":
+			if r.URL.Query().Get("page") == "1" {
+				w.Header().Set("X-Next-Page", "2")
+				fmt.Fprintf(w, "[%s]", mergeRequest(3, "merged", `"2024-01-02T10:00:00.000Z"`))
+				return
+			}
+			fmt.Fprintf(w, "[%s]", mergeRequest(2, "merged", `"2024-01-02T10:00:00.000Z"`))
+		case path == project+"/merge_requests" && r.URL.Query().Get("state") == "opened":
+			fmt.Fprintf(w, "[%s]", mergeRequest(4, "opened", "null"))
+		case strings.HasSuffix(path, "/participants"):
+			w.Write([]byte(`[
+				{"id": 1, "username": "author", "web_url": "https://gitlab.example.com/author"},
+				{"id": 2, "username": "reviewer", "web_url": "https://gitlab.example.com/reviewer"},
+				{"id": 3, "username": "commenter", "web_url": "https://gitlab.example.com/commenter"}
+			]`))
+		case strings.HasSuffix(path, "/diffs"):
+			if r.URL.Query().Get("page") == "1" {
+				w.Header().Set("X-Next-Page", "2")
+				w.Write([]byte(`[{"old_path": "services/api/main.go", "new_path": "services/api/main.go"}]`))
+				return
+			}
+			w.Write([]byte(`[{"old_path": "libs/auth/token.go", "new_path": "libs/auth/jwt.go"}]`))
+		case path == project+"/releases":
+			if r.URL.Query().Get("page") == "1" {
+				w.Header().Set("X-Next-Page", "2")
+				w.Write([]byte(`[{"tag_name": "v2.0.0", "name": "v2.0.0", "created_at": "2024-03-01T10:00:00.000Z", "released_at": "2024-04-01T10:00:00.000Z", "upcoming_release": true}]`))
+				return
+			}
+			w.Write([]byte(`[{
+				"tag_name": "v1.0.0", "name": "First release", "description": "Notes",
+				"created_at": "2024-02-01T10:00:00.000Z", "released_at": "2024-02-02T10:00:00.000Z", "upcoming_release": false,
+				"author": {"id": 1, "username": "author", "web_url": "https://gitlab.example.com/author"},
+				"_links": {"self": "https://gitlab.example.com/group/subgroup/project/-/releases/v1.0.0"}
+			}]`))
+		case path == project+"/merge_requests/2":
+			w.Write([]byte(mergeRequest(2, "merged", `"2024-01-02T10:00:00.000Z"`)))
+		case path == project+"/merge_requests/429":
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return &GitLab{Client: server.Client(), APIURL: server.URL + "/api/v4", Token: "token"}
+}
+
+func TestAllPullRequests(t *testing.T) {
+	gl := newTestGitLab(t)
+
+	params := provider.AllPullRequestsParams{Host: "gitlab.example.com", Owner: "group/subgroup", Repo: "project"}
+
+	result, err := gl.AllPullRequests(context.Background(), params)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.PullRequests) != 2 || result.Truncated {
+		t.Fatalf("expected every page of merged merge requests, got %d pull requests, truncated: %v", len(result.PullRequests), result.Truncated)
+	}
+
+	pr := result.PullRequests[0]
+	if pr.Number != 3 || pr.Host != "gitlab.example.com" || pr.Owner != "group/subgroup" || pr.Repo != "project" {
+		t.Errorf("unexpected pull request identity: %+v", pr)
+	}
+
+	if pr.State != types.MergedPullRequestState || pr.MergedAt == nil || pr.HeadRefName != "feature-3" || pr.BaseRefName != "main" || pr.CIStatus != "SUCCESS" {
+		t.Errorf("unexpected pull request: %+v", pr)
+	}
+
+	if fmt.Sprint(pr.Labels) != "[bug backend]" || pr.Milestone != "v1.0" || fmt.Sprint(pr.Assignees) != "[author]" {
+		t.Errorf("unexpected pull request metadata: labels %v, milestone %q, assignees %v", pr.Labels, pr.Milestone, pr.Assignees)
+	}
+
+	if fmt.Sprint(pr.Files) != "[services/api/main.go libs/auth/jwt.go]" {
+		t.Errorf("expected the files of every page of diffs, got %v", pr.Files)
+	}
+
+	if pr.Duration.Hours() != 24 {
+		t.Errorf("expected a duration of 24 hours, got %v", pr.Duration)
+	}
+
+	expectedRoles := []types.ContributorRole{types.AuthorContributorRole, types.ReviewerContributorRole, types.CommenterContributorRole}
+	if len(pr.Contributors) != len(expectedRoles) {
+		t.Fatalf("expected %d contributors, got %+v", len(expectedRoles), pr.Contributors)
+	}
+	for i, role := range expectedRoles {
+		if pr.Contributors[i].Role != role {
+			t.Errorf("expected contributor %d role %s, got %s", i, role, pr.Contributors[i].Role)
+		}
+	}
+
+	gl.MaxPages = 1
+	result, err = gl.AllPullRequests(context.Background(), params)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.PullRequests) != 1 || !result.Truncated {
+		t.Errorf("expected result truncated to the first page, got %d pull requests, truncated: %v", len(result.PullRequests), result.Truncated)
+	}
+
+	params.States = []types.PullRequestState{types.OpenPullRequestState}
+	result, err = gl.AllPullRequests(context.Background(), params)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.PullRequests) != 1 || result.PullRequests[0].State != types.OpenPullRequestState || result.PullRequests[0].MergedAt != nil {
+		t.Errorf("expected the opened merge request, got %+v", result.PullRequests)
+	}
+}
+
+func TestAllReleases(t *testing.T) {
+	gl := newTestGitLab(t)
+
+	params := provider.AllReleasesParams{Host: "gitlab.example.com", Owner: "group/subgroup", Repo: "project"}
+
+	result, err := gl.AllReleases(context.Background(), params)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.Releases) != 1 || result.Truncated {
+		t.Fatalf("expected the released release of every page, got %d releases, truncated: %v", len(result.Releases), result.Truncated)
+	}
+
+	release := result.Releases[0]
+	if release.TagName != "v1.0.0" || release.Name != "First release" || release.Host != "gitlab.example.com" || release.Owner != "group/subgroup" || release.Repo != "project" {
+		t.Errorf("unexpected release identity: %+v", release)
+	}
+
+	if release.Author.Login != "author" || release.URL != "https://gitlab.example.com/group/subgroup/project/-/releases/v1.0.0" || release.PublishedAt == nil || release.PublishedAt.Day() != 2 {
+		t.Errorf("unexpected release: %+v", release)
+	}
+
+	gl.MaxPages = 1
+	result, err = gl.AllReleases(context.Background(), params)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.Releases) != 0 || !result.Truncated {
+		t.Errorf("expected releases truncated to the first page, got %d releases, truncated: %v", len(result.Releases), result.Truncated)
+	}
+}
+
+func TestPullRequests(t *testing.T) {
+	gl := newTestGitLab(t)
+
+	results, err := gl.PullRequests(context.Background(), provider.PullRequestsParams{
+		Host: "gitlab.example.com",
+		PullRequests: []provider.PullRequestParams{
+			{Owner: "group/subgroup", Repo: "project", Number: 2},
+			{Owner: "group/subgroup", Repo: "project", Number: 404},
+			{Owner: "group/subgroup", Repo: "project", Number: 429},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if results[0].Err != nil || results[0].PullRequest.Number != 2 {
+		t.Errorf("expected the merge request, got %+v", results[0])
+	}
+
+	if !errors.Is(results[1].Err, provider.ErrPullRequestNotFound) {
+		t.Errorf("expected not found error, got %v", results[1].Err)
+	}
+
+	if results[2].Err == nil || !strings.Contains(results[2].Err.Error(), "rate limit") {
+		t.Errorf("expected rate limit error, got %v", results[2].Err)
+	}
+}
+
+func TestPullRequestsRetriesRateLimitedRequests(t *testing.T) {
+	var requests, running, maxRunning atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if n := running.Add(1); n > maxRunning.Load() {
+			maxRunning.Store(n)
+		}
+		defer running.Add(-1)
+		time.Sleep(5 * time.Millisecond)
+
+		if requests.Add(1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/participants"), strings.HasSuffix(r.URL.Path, "/diffs"):
+			w.Write([]byte(`[]`))
+		default:
+			w.Write([]byte(`{"iid": 1, "state": "opened", "created_at": "2024-01-01T10:00:00.000Z", "author": {"id": 1}}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	gl := NewGitLab(&config.GitLabHostConfig{Host: "gitlab.example.com", APIURL: server.URL + "/api/v4"}, time.Minute)
+	gl.Concurrency = 2
+
+	params := provider.PullRequestsParams{Host: "gitlab.example.com"}
+	for i := 0; i < 6; i++ {
+		params.PullRequests = append(params.PullRequests, provider.PullRequestParams{Owner: "group", Repo: "project", Number: i + 1})
+	}
+
+	results, err := gl.PullRequests(context.Background(), params)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for i, result := range results {
+		if result.Err != nil {
+			t.Errorf("expected merge request %d after retrying the rate limited request, got %v", i+1, result.Err)
+		}
+	}
+
+	if maxRunning.Load() > 2 {
+		t.Errorf("expected at most 2 requests at once, got %d", maxRunning.Load())
+	}
+}
@@ -0,0 +1,439 @@
+package gitlog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chris-ramon/golang-scaffolding/config"
+	"github.com/chris-ramon/golang-scaffolding/domain/metrics/provider"
+	"github.com/chris-ramon/golang-scaffolding/domain/metrics/types"
+	"github.com/chris-ramon/golang-scaffolding/pkg/trailers"
+)
+
+// GitLog represents the git log component, deriving the pull requests of the repositories cloned on the local
+// filesystem from their merge commits, without any forge API.
+type GitLog struct {
+	// Root is the directory the analyzed repositories must be in, local analysis is disabled when empty.
+	Root string
+
+	// Timeout is the timeout of each git command, zero means no timeout.
+	Timeout time.Duration
+}
+
+var (
+	// gitHubMergeRegexp matches the `Merge pull request #1 from owner/branch` GitHub merge commit subjects.
+	gitHubMergeRegexp = regexp.MustCompile(`^Merge pull request #(\d+) from [^/\s]+/(\S+)`)
+
+	// gitLabMergeRegexp matches the `See merge request group/project!1` GitLab merge commit trailers.
+	gitLabMergeRegexp = regexp.MustCompile(`(?m)^See merge request \S+!(\d+)\s*$`)
+
+	// branchMergeRegexp matches the `Merge branch 'branch'` and `Merge remote-tracking branch 'origin/branch'`
+	// git merge commit subjects, optionally followed by the `into 'base'` of the merges into other branches.
+	branchMergeRegexp = regexp.MustCompile(`^Merge (?:remote-tracking )?branch '([^']+)'(?: into '?([^'\s]+)'?)?`)
+)
+
+const (
+	fieldSeparator  = "\x1f"
+	recordSeparator = "\x1e"
+)
+
+// commit represents a commit of the git log output.
+type commit struct {
+	Hash        string
+	Parents     []string
+	AuthorName  string
+	AuthorEmail string
+	AuthoredAt  time.Time
+	CommittedAt time.Time
+	Subject     string
+	Body        string
+
+	// Files are the paths of the files changed by the commit, compared to the first parent of merges.
+	Files []string
+}
+
+// branchMerge represents a merge commit of the first-parent history with the commits of the branch it merged.
+type branchMerge struct {
+	commit
+
+	// BranchCommits are the non-merge commits of the merged branch, newest first.
+	BranchCommits []commit
+}
+
+// AllPullRequests returns the pull requests merged in the current branch of a local repository, newest first,
+// one for each merge commit of its first-parent history. Local repositories only have merged pull requests.
+func (g *GitLog) AllPullRequests(ctx context.Context, params provider.AllPullRequestsParams) (*provider.AllPullRequestsResult, error) {
+	result := &provider.AllPullRequestsResult{PullRequests: []*types.PullRequest{}}
+
+	if len(params.States) > 0 && !slices.Contains(params.States, types.MergedPullRequestState) {
+		return result, nil
+	}
+
+	dir, err := g.dir(params.Owner, params.Repo)
+	if err != nil {
+		return nil, err
+	}
+
+	commits, err := g.log(ctx, dir, "HEAD")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, merge := range merges(commits) {
+		result.PullRequests = append(result.PullRequests, pullRequest(dir, params, merge))
+	}
+
+	return result, nil
+}
+
+// PullRequests returns the given pull requests of a local repository, found by the number of their merge
+// commit message.
+func (g *GitLog) PullRequests(ctx context.Context, params provider.PullRequestsParams) ([]provider.PullRequestResult, error) {
+	results := make([]provider.PullRequestResult, len(params.PullRequests))
+	pullRequestsByRepo := map[string]map[int]*types.PullRequest{}
+
+	for i, param := range params.PullRequests {
+		key := filepath.Join(param.Owner, param.Repo)
+
+		pullRequests, ok := pullRequestsByRepo[key]
+		if !ok {
+			r, err := g.AllPullRequests(ctx, provider.AllPullRequestsParams{Host: params.Host, Owner: param.Owner, Repo: param.Repo})
+			if err != nil {
+				results[i].Err = err
+				continue
+			}
+
+			pullRequests = map[int]*types.PullRequest{}
+			for _, pr := range r.PullRequests {
+				if _, ok := pullRequests[pr.Number]; !ok && pr.Number != 0 {
+					pullRequests[pr.Number] = pr
+				}
+			}
+			pullRequestsByRepo[key] = pullRequests
+		}
+
+		pr, ok := pullRequests[param.Number]
+		if !ok {
+			results[i].Err = provider.ErrPullRequestNotFound
+			continue
+		}
+
+		results[i].PullRequest = pr
+	}
+
+	return results, nil
+}
+
+// merges returns the merge commits of the first-parent history of the first of the given commits, newest first.
+// The branch commits of a merge are the non-merge commits reachable from its other parents but not from its
+// first parent: walking the first-parent history oldest first, the commits reachable from each commit are the
+// ones already visited, so every commit is visited once.
+func merges(commits []commit) []branchMerge {
+	indexes := make(map[string]int, len(commits))
+	for i, c := range commits {
+		indexes[c.Hash] = i
+	}
+
+	// The first-parent history, newest first, stops at the commits missing from shallow clones.
+	history := []int{}
+	for i, ok := 0, len(commits) > 0; ok; {
+		history = append(history, i)
+		if len(commits[i].Parents) == 0 {
+			break
+		}
+		i, ok = indexes[commits[i].Parents[0]]
+	}
+
+	result := []branchMerge{}
+	visited := make([]bool, len(commits))
+
+	for h := len(history) - 1; h >= 0; h-- {
+		c := commits[history[h]]
+		visited[history[h]] = true
+
+		if len(c.Parents) < 2 {
+			continue
+		}
+
+		branch := []int{}
+		pending := slices.Clone(c.Parents[1:])
+		for len(pending) > 0 {
+			hash := pending[len(pending)-1]
+			pending = pending[:len(pending)-1]
+
+			i, ok := indexes[hash]
+			if !ok || visited[i] {
+				continue
+			}
+			visited[i] = true
+
+			if len(commits[i].Parents) < 2 {
+				branch = append(branch, i)
+			}
+			pending = append(pending, commits[i].Parents...)
+		}
+
+		// The git log output is newest first.
+		slices.Sort(branch)
+
+		m := branchMerge{commit: c, BranchCommits: make([]commit, len(branch))}
+		for j, i := range branch {
+			m.BranchCommits[j] = commits[i]
+		}
+		result = append(result, m)
+	}
+
+	slices.Reverse(result)
+
+	return result
+}
+
+// pullRequest returns the pull request of the given merge. The branch lifetime goes from the first commit
+// authored on the branch until the merge, its contributors are the commit authors, the first one as the
+// author, and the `Co-authored-by` commit trailers, and its files the ones the merge changed.
+func pullRequest(dir string, params provider.AllPullRequestsParams, merge branchMerge) *types.PullRequest {
+	branchCommits := merge.BranchCommits
+	number, headRefName, baseRefName, title, body := mergeMessage(merge.commit)
+
+	createdAt := merge.AuthoredAt.UTC()
+	for _, c := range branchCommits {
+		if c.AuthoredAt.Before(createdAt) {
+			createdAt = c.AuthoredAt.UTC()
+		}
+	}
+	mergedAt := merge.CommittedAt.UTC()
+
+	url := fmt.Sprintf("file://%s#%s", dir, merge.Hash)
+	if number != 0 {
+		url = fmt.Sprintf("file://%s#%d", dir, number)
+	}
+
+	pr := &types.PullRequest{
+		Number:       number,
+		Host:         params.Host,
+		Owner:        params.Owner,
+		Repo:         params.Repo,
+		Title:        title,
+		Body:         body,
+		CreatedAt:    &createdAt,
+		MergedAt:     &mergedAt,
+		ClosedAt:     &mergedAt,
+		UpdatedAt:    &mergedAt,
+		State:        types.MergedPullRequestState,
+		URL:          url,
+		HeadRefName:  headRefName,
+		BaseRefName:  baseRefName,
+		MergeCommit:  merge.Hash,
+		Contributors: contributors(merge.commit, branchCommits),
+		Files:        merge.Files,
+		Duration:     mergedAt.Sub(createdAt),
+	}
+	pr.FormattedContributors = pr.Contributors.FormattedContributors(types.CommasFormatContributorType)
+
+	return pr
+}
+
+// mergeMessage returns the pull request number, head and base branches, title and body of the given merge
+// commit message. The number is zero for merges without pull request, the base branch is only known for the
+// branch merges naming it and the title of GitHub merges is the first line of their body.
+func mergeMessage(merge commit) (number int, headRefName string, baseRefName string, title string, body string) {
+	title, body = merge.Subject, merge.Body
+
+	if m := gitHubMergeRegexp.FindStringSubmatch(merge.Subject); m != nil {
+		number, _ = strconv.Atoi(m[1])
+		headRefName = m[2]
+
+		if t, b, _ := strings.Cut(strings.TrimSpace(merge.Body), "\n"); t != "" {
+			title, body = t, strings.TrimSpace(b)
+		}
+
+		return number, headRefName, baseRefName, title, body
+	}
+
+	if m := branchMergeRegexp.FindStringSubmatch(merge.Subject); m != nil {
+		headRefName, baseRefName = m[1], m[2]
+	}
+
+	if m := gitLabMergeRegexp.FindStringSubmatch(merge.Body); m != nil {
+		number, _ = strconv.Atoi(m[1])
+	}
+
+	return number, headRefName, baseRefName, title, body
+}
+
+// contributors returns the contributors of the given branch commits, oldest first: the first author as the
+// author, the other authors and the `Co-authored-by` trailers as co-authors. Merges without branch commits
+// are authored by the merge commit author.
+func contributors(merge commit, branchCommits []commit) types.Contributors {
+	commits := branchCommits
+	if len(commits) == 0 {
+		commits = []commit{merge}
+	}
+
+	contributors := types.Contributors{}
+	seenEmails := map[string]bool{}
+
+	add := func(name string, email string) {
+		key := strings.ToLower(email)
+		if seenEmails[key] {
+			return
+		}
+		seenEmails[key] = true
+
+		role := types.CoAuthorContributorRole
+		if len(contributors) == 0 {
+			role = types.AuthorContributorRole
+		}
+
+		contributors = append(contributors, types.Contributor{
+			Login: trailers.CoAuthor{Name: name, Email: email}.Login(),
+			Name:  name,
+			Email: email,
+			Role:  role,
+		})
+	}
+
+	// The git log output is newest first.
+	for i := len(commits) - 1; i >= 0; i-- {
+		add(commits[i].AuthorName, commits[i].AuthorEmail)
+	}
+
+	for i := len(commits) - 1; i >= 0; i-- {
+		for _, coAuthor := range trailers.CoAuthors(commits[i].Body) {
+			add(coAuthor.Name, coAuthor.Email)
+		}
+	}
+
+	return contributors
+}
+
+// log returns the commits of the git log of the given repository and arguments, in a single pass listing the
+// files changed by each commit, compared to the first parent of merges.
+func (g *GitLog) log(ctx context.Context, dir string, args ...string) ([]commit, error) {
+	// The files of the numstat output follow the last field separator of each record, NUL terminated.
+	format := "%x1e" + strings.Join([]string{"%H", "%P", "%an", "%ae", "%aI", "%cI", "%s", "%b"}, "%x1f") + "%x1f"
+
+	out, err := g.git(ctx, dir, append([]string{"log", "--format=" + format, "-z", "--numstat", "--no-renames", "--diff-merges=first-parent"}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	commits := []commit{}
+
+	for _, record := range strings.Split(string(out), recordSeparator) {
+		if record == "" {
+			continue
+		}
+
+		fields := strings.Split(record, fieldSeparator)
+		if len(fields) != 9 {
+			return nil, fmt.Errorf("unexpected git log record: %q", record)
+		}
+
+		files := []string{}
+		for _, stat := range strings.Split(fields[8], "\x00") {
+			stat = strings.TrimLeft(stat, "\n")
+			if stat == "" {
+				continue
+			}
+
+			// The added and deleted lines, `-` for binary files, precede the path.
+			parts := strings.SplitN(stat, "\t", 3)
+			if len(parts) != 3 {
+				return nil, fmt.Errorf("unexpected git log numstat: %q", stat)
+			}
+			files = append(files, parts[2])
+		}
+
+		authoredAt, err := time.Parse(time.RFC3339, fields[4])
+		if err != nil {
+			return nil, err
+		}
+
+		committedAt, err := time.Parse(time.RFC3339, fields[5])
+		if err != nil {
+			return nil, err
+		}
+
+		commits = append(commits, commit{
+			Hash:        fields[0],
+			Parents:     strings.Fields(fields[1]),
+			AuthorName:  fields[2],
+			AuthorEmail: fields[3],
+			AuthoredAt:  authoredAt,
+			CommittedAt: committedAt,
+			Subject:     fields[6],
+			Body:        strings.TrimSpace(fields[7]),
+			Files:       files,
+		})
+	}
+
+	return commits, nil
+}
+
+// git returns the output of the given git command run in the given repository.
+func (g *GitLog) git(ctx context.Context, dir string, args ...string) ([]byte, error) {
+	if g.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, g.Timeout)
+		defer cancel()
+	}
+
+	stderr := &bytes.Buffer{}
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", dir}, args...)...)
+	cmd.Stderr = stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git %s failed: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+
+	return out, nil
+}
+
+// dir returns the directory of the given local repository, which must be within the root directory. Relative
+// repositories are relative to the root directory.
+func (g *GitLog) dir(owner string, repo string) (string, error) {
+	if g.Root == "" {
+		return "", errors.New("local repositories are disabled")
+	}
+
+	root, err := filepath.EvalSymlinks(g.Root)
+	if err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(owner, repo)
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(root, path)
+	}
+
+	dir, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", err
+	}
+
+	rel, err := filepath.Rel(root, dir)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("repository %q is outside of the local repositories directory", filepath.Join(owner, repo))
+	}
+
+	return dir, nil
+}
+
+// NewGitLog returns a git log component of the given configuration.
+func NewGitLog(conf *config.LocalConfig) *GitLog {
+	return &GitLog{
+		Root:    conf.RepositoriesDir,
+		Timeout: conf.Timeout,
+	}
+}
@@ -0,0 +1,185 @@
+package gitlog
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/chris-ramon/golang-scaffolding/domain/metrics/provider"
+	"github.com/chris-ramon/golang-scaffolding/domain/metrics/types"
+)
+
+// newTestRepository returns a root directory with a git repository whose main branch merges a GitHub pull
+// request with a co-authored commit changing `services/api/main.go`, and a branch without pull request.
+func newTestRepository(t *testing.T) (root string, dir string) {
+	t.Helper()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	root = t.TempDir()
+	dir = filepath.Join(root, "service")
+
+	git := func(date string, name string, args ...string) {
+		t.Helper()
+
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME="+name, "GIT_AUTHOR_EMAIL="+name+"@example.com", "GIT_AUTHOR_DATE="+date,
+			"GIT_COMMITTER_NAME=merger", "GIT_COMMITTER_EMAIL=merger@example.com", "GIT_COMMITTER_DATE="+date,
+			"GIT_CONFIG_GLOBAL=/dev/null", "GIT_CONFIG_SYSTEM=/dev/null",
+		)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v: %s", args, err, out)
+		}
+	}
+
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	git("2024-01-01T10:00:00Z", "alice", "init", "-q", "-b", "main")
+	git("2024-01-01T10:00:00Z", "alice", "commit", "-q", "--allow-empty", "-m", "Initial commit")
+
+	git("2024-01-02T10:00:00Z", "bob", "checkout", "-q", "-b", "feature")
+	if err := os.MkdirAll(filepath.Join(dir, "services", "api"), 0o755); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "services", "api", "main.go"), []byte("package main\n"), 0o644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	git("2024-01-02T10:00:00Z", "bob", "add", "services/api/main.go")
+	git("2024-01-02T10:00:00Z", "bob", "commit", "-q", "--allow-empty", "-m", "Add feature")
+	git("2024-01-03T10:00:00Z", "carol", "commit", "-q", "--allow-empty", "-m", "Fix feature\n\nCo-authored-by: Dave <dave@example.com>")
+	git("2024-01-04T10:00:00Z", "merger", "checkout", "-q", "main")
+	git("2024-01-04T10:00:00Z", "merger", "merge", "-q", "--no-ff", "feature", "-m", "Merge pull request #7 from bob/feature\n\nAdd the feature")
+
+	git("2024-01-05T10:00:00Z", "carol", "checkout", "-q", "-b", "legacy")
+	git("2024-01-05T10:00:00Z", "carol", "commit", "-q", "--allow-empty", "-m", "Legacy change")
+	git("2024-01-06T10:00:00Z", "merger", "checkout", "-q", "main")
+	git("2024-01-06T10:00:00Z", "merger", "merge", "-q", "--no-ff", "legacy", "-m", "Merge branch 'legacy'")
+
+	return root, dir
+}
+
+func TestAllPullRequests(t *testing.T) {
+	root, _ := newTestRepository(t)
+	g := &GitLog{Root: root}
+
+	result, err := g.AllPullRequests(context.Background(), provider.AllPullRequestsParams{Host: "file://", Owner: root, Repo: "service"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.PullRequests) != 2 {
+		t.Fatalf("expected a pull request for each merge, got %d", len(result.PullRequests))
+	}
+
+	legacy, pr := result.PullRequests[0], result.PullRequests[1]
+
+	if legacy.Number != 0 || legacy.HeadRefName != "legacy" || legacy.Title != "Merge branch 'legacy'" {
+		t.Errorf("unexpected merge without pull request: %+v", legacy)
+	}
+
+	if len(legacy.MergeCommit) != 40 || legacy.MergeCommit == pr.MergeCommit {
+		t.Errorf("expected the merge commit hash of each merge, got %q and %q", legacy.MergeCommit, pr.MergeCommit)
+	}
+
+	if pr.Number != 7 || pr.HeadRefName != "feature" || pr.Title != "Add the feature" || pr.State != types.MergedPullRequestState {
+		t.Errorf("unexpected pull request: %+v", pr)
+	}
+
+	if !pr.CreatedAt.Equal(time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)) || pr.Duration != 48*time.Hour {
+		t.Errorf("expected the branch lifetime from its first commit until the merge, got %v for %v", pr.CreatedAt, pr.Duration)
+	}
+
+	if len(pr.Files) != 1 || pr.Files[0] != "services/api/main.go" || len(legacy.Files) != 0 {
+		t.Errorf("expected the files changed by each merge, got %v and %v", pr.Files, legacy.Files)
+	}
+
+	expected := []types.Contributor{
+		{Name: "bob", Email: "bob@example.com", Role: types.AuthorContributorRole},
+		{Name: "carol", Email: "carol@example.com", Role: types.CoAuthorContributorRole},
+		{Name: "Dave", Email: "dave@example.com", Role: types.CoAuthorContributorRole},
+	}
+	if len(pr.Contributors) != len(expected) {
+		t.Fatalf("expected %d contributors, got %+v", len(expected), pr.Contributors)
+	}
+	for i, c := range expected {
+		if pr.Contributors[i] != c {
+			t.Errorf("expected contributor %+v, got %+v", c, pr.Contributors[i])
+		}
+	}
+
+	result, err = g.AllPullRequests(context.Background(), provider.AllPullRequestsParams{
+		Owner:  root,
+		Repo:   "service",
+		States: []types.PullRequestState{types.OpenPullRequestState},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.PullRequests) != 0 {
+		t.Errorf("expected no open pull requests, got %d", len(result.PullRequests))
+	}
+}
+
+func TestPullRequests(t *testing.T) {
+	root, _ := newTestRepository(t)
+	g := &GitLog{Root: root}
+
+	results, err := g.PullRequests(context.Background(), provider.PullRequestsParams{
+		PullRequests: []provider.PullRequestParams{
+			{Owner: root, Repo: "service", Number: 7},
+			{Owner: root, Repo: "service", Number: 8},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if results[0].Err != nil || results[0].PullRequest.Number != 7 {
+		t.Errorf("expected pull request #7, got %+v", results[0])
+	}
+
+	if !errors.Is(results[1].Err, provider.ErrPullRequestNotFound) {
+		t.Errorf("expected not found error, got %v", results[1].Err)
+	}
+}
+
+func TestDir(t *testing.T) {
+	root, dir := newTestRepository(t)
+
+	testCases := []struct {
+		name        string
+		gitLog      *GitLog
+		owner       string
+		repo        string
+		expectError bool
+	}{
+		{name: "within the root", gitLog: &GitLog{Root: root}, owner: root, repo: "service"},
+		{name: "relative to the root", gitLog: &GitLog{Root: root}, owner: ".", repo: "service"},
+		{name: "outside of the root", gitLog: &GitLog{Root: dir}, owner: root, repo: "service/..", expectError: true},
+		{name: "disabled", gitLog: &GitLog{}, owner: root, repo: "service", expectError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tc.gitLog.dir(tc.owner, tc.repo)
+
+			if tc.expectError && err == nil {
+				t.Errorf("expected error but got none")
+			}
+
+			if !tc.expectError && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
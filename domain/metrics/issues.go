@@ -0,0 +1,146 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/chris-ramon/golang-scaffolding/domain/metrics/github"
+	"github.com/chris-ramon/golang-scaffolding/domain/metrics/provider"
+	"github.com/chris-ramon/golang-scaffolding/domain/metrics/types"
+)
+
+type FindAllIssuesParams struct {
+	RepositoryURL string
+
+	// States are the issue states to find, defaults to every state.
+	States []types.IssueState
+}
+
+type FindAllIssuesResult struct {
+	Issues []*types.Issue
+
+	// Truncated reports whether issues were left out by the maximum number of fetched pages.
+	Truncated bool
+}
+
+// `findAllIssuesCacheKey` returns cache key of `FindAllIssues`, whose parameters are named apart from the
+// `FindAllPullRequests` ones they share their fields with.
+func (s *service) findAllIssuesCacheKey(params FindAllIssuesParams) (string, error) {
+	key, err := json.Marshal(struct {
+		Issues     FindAllIssuesParams
+		Generation uint64
+	}{params, s.repositoryCacheGeneration(params.RepositoryURL)})
+	if err != nil {
+		return "", err
+	}
+
+	return string(key), nil
+}
+
+// `getFindAllIssuesCacheValue` returns cached data of `FindAllIssues`.
+func (s *service) getFindAllIssuesCacheValue(data any) (*FindAllIssuesResult, error) {
+	result, ok := data.(*FindAllIssuesResult)
+	if !ok {
+		return nil, errors.New("unexpected type")
+	}
+
+	return result, nil
+}
+
+// `cacheFindAllIssuesValue` caches given result of `FindAllIssues`.
+func (s *service) cacheFindAllIssuesValue(key string, data any) {
+	s.cache.Add(key, data)
+}
+
+// FindAllIssues returns the issues of the given states of a repository, most recently created first. Only the
+// providers implementing `provider.IssueProvider`, GitHub ones so far, have issues.
+func (s *service) FindAllIssues(ctx context.Context, params FindAllIssuesParams) (*FindAllIssuesResult, error) {
+	key, err := s.findAllIssuesCacheKey(params)
+	if err != nil {
+		return nil, err
+	}
+
+	findAllIssuesCacheVal, found := s.cache.Get(key)
+	if found {
+		return s.getFindAllIssuesCacheValue(findAllIssuesCacheVal)
+	}
+
+	ref, err := github.ParseRepoRef(params.RepositoryURL)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := s.providerFor(ref.Host)
+	if err != nil {
+		return nil, err
+	}
+
+	issueProvider, ok := p.(provider.IssueProvider)
+	if !ok {
+		return nil, fmt.Errorf("issues are not supported on host %q", ref.Host)
+	}
+
+	r, err := issueProvider.AllIssues(ctx, provider.AllIssuesParams{
+		Host:   ref.Host,
+		Owner:  ref.Owner,
+		Repo:   ref.Repo,
+		States: params.States,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &FindAllIssuesResult{
+		Issues:    r.Issues,
+		Truncated: r.Truncated,
+	}
+
+	s.cacheFindAllIssuesValue(key, result)
+
+	return result, nil
+}
+
+// issuesFromNodes returns the issues of the given GitHub nodes, skipping the incomplete ones.
+func issuesFromNodes(host string, nodes github.IssuesNodes) []*types.Issue {
+	issues := []*types.Issue{}
+
+	for _, issueNode := range nodes {
+		if issueNode.CreatedAt.Time.IsZero() {
+			continue
+		}
+
+		issue := issueFromNode(host, issueNode)
+		issues = append(issues, &issue)
+	}
+
+	return issues
+}
+
+// issueFromNode returns the issue of the given GitHub node, of the repository the node belongs to.
+func issueFromNode(host string, issueNode github.IssuesNode) types.Issue {
+	issue := types.Issue{
+		Number: int(issueNode.Number),
+		Host:   host,
+		Owner:  string(issueNode.Repository.Owner.Login),
+		Repo:   string(issueNode.Repository.Name),
+		Title:  string(issueNode.Title),
+		Body:   string(issueNode.Body),
+		URL:    string(issueNode.URL),
+		State:  types.IssueState(issueNode.State),
+		Author: types.Author{Login: string(issueNode.Author.Login)},
+	}
+
+	if !issueNode.CreatedAt.Time.IsZero() {
+		createdAt := issueNode.CreatedAt.UTC()
+		issue.CreatedAt = &createdAt
+	}
+
+	if !issueNode.ClosedAt.Time.IsZero() {
+		closedAt := issueNode.ClosedAt.UTC()
+		issue.ClosedAt = &closedAt
+	}
+
+	return issue
+}
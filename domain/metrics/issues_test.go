@@ -0,0 +1,156 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+
+	cachePkg "github.com/chris-ramon/golang-scaffolding/cache"
+	"github.com/chris-ramon/golang-scaffolding/domain/metrics/github"
+	"github.com/chris-ramon/golang-scaffolding/domain/metrics/provider"
+	"github.com/chris-ramon/golang-scaffolding/domain/metrics/types"
+)
+
+// issueNode returns a GitHub issue node of the owner/repo repository.
+func issueNode(number int, createdAt time.Time, closedAt time.Time) github.IssuesNode {
+	node := github.IssuesNode{
+		Number:    githubv4.Int(number),
+		URL:       githubv4.String(fmt.Sprintf("https://github.com/owner/repo/issues/%d", number)),
+		Title:     githubv4.String("Issue"),
+		State:     githubv4.IssueStateOpen,
+		CreatedAt: githubv4.DateTime{Time: createdAt},
+		Author:    github.Author{Login: "author"},
+	}
+	node.Repository.Name = "repo"
+	node.Repository.Owner.Login = "owner"
+
+	if !closedAt.IsZero() {
+		node.State = githubv4.IssueStateClosed
+		node.ClosedAt = githubv4.DateTime{Time: closedAt}
+	}
+
+	return node
+}
+
+func TestFindAllIssues(t *testing.T) {
+	openedAt := time.Now().Add(-48 * time.Hour)
+	closedAt := time.Now()
+	calls := 0
+
+	mockGH := &mockGitHub{
+		allIssues: func(params github.AllIssuesParams) (github.AllIssuesQuery, error) {
+			calls++
+
+			if params.Owner != "owner" || params.Repo != "repo" || len(params.States) != 1 || params.States[0] != types.ClosedIssueState {
+				t.Errorf("unexpected params: %+v", params)
+			}
+
+			query := github.AllIssuesQuery{}
+			query.Repository.Issues.Nodes = github.IssuesNodes{
+				issueNode(1, openedAt, closedAt),
+				{Number: 2},
+			}
+			return query, nil
+		},
+	}
+
+	srv := &service{
+		cache:  cachePkg.New(),
+		GitHub: mockGH,
+	}
+
+	params := FindAllIssuesParams{
+		RepositoryURL: "https://github.com/owner/repo",
+		States:        []types.IssueState{types.ClosedIssueState},
+	}
+
+	result, err := srv.FindAllIssues(context.Background(), params)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.Issues) != 1 {
+		t.Fatalf("expected the incomplete issue to be skipped, got %d issues", len(result.Issues))
+	}
+
+	issue := result.Issues[0]
+	if issue.Number != 1 || issue.Host != "github.com" || issue.Owner != "owner" || issue.Repo != "repo" || issue.Author.Login != "author" {
+		t.Errorf("unexpected issue identity: %+v", issue)
+	}
+
+	if issue.State != types.ClosedIssueState || issue.CreatedAt == nil || issue.ClosedAt == nil || !issue.ClosedAt.Equal(closedAt) {
+		t.Errorf("unexpected issue state: %+v", issue)
+	}
+
+	if _, err := srv.FindAllIssues(context.Background(), params); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if calls != 1 {
+		t.Errorf("expected the second call to be cached, got %d calls", calls)
+	}
+
+	// The pull requests of the same repository are cached apart from its issues.
+	mockGH.allPullRequests = func(params github.AllPullRequestsParams) (github.AllPullRequestsQuery, error) {
+		return github.AllPullRequestsQuery{}, nil
+	}
+	if _, err := srv.FindAllPullRequests(context.Background(), FindAllPullRequestsParams{RepositoryURL: params.RepositoryURL}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestFindAllIssuesUnsupportedHost(t *testing.T) {
+	srv := &service{
+		cache:     cachePkg.New(),
+		GitHub:    &mockGitHub{},
+		Providers: map[string]provider.Provider{"gitlab.com": &mockProvider{}},
+	}
+
+	_, err := srv.FindAllIssues(context.Background(), FindAllIssuesParams{RepositoryURL: "https://gitlab.com/group/project"})
+	if err == nil {
+		t.Errorf("expected error for a host without issues")
+	}
+}
+
+func TestFindAllPullRequestsLinkedIssues(t *testing.T) {
+	openedAt := time.Now().Add(-72 * time.Hour)
+	createdAt := time.Now().Add(-48 * time.Hour)
+	mergedAt := time.Now().Add(-24 * time.Hour)
+
+	mockGH := &mockGitHub{
+		allPullRequests: func(params github.AllPullRequestsParams) (github.AllPullRequestsQuery, error) {
+			query := github.AllPullRequestsQuery{}
+			query.Repository.PullRequests.Nodes = github.AllPullRequestsNodes{{
+				Number:    githubv4.Int(10),
+				CreatedAt: githubv4.DateTime{Time: createdAt},
+				MergedAt:  githubv4.DateTime{Time: mergedAt},
+				ClosingIssuesReferences: github.ClosingIssuesReferences{
+					Nodes: github.IssuesNodes{issueNode(1, openedAt, mergedAt)},
+				},
+			}}
+			return query, nil
+		},
+	}
+
+	srv := &service{
+		cache:  cachePkg.New(),
+		GitHub: mockGH,
+	}
+
+	result, err := srv.FindAllPullRequests(context.Background(), FindAllPullRequestsParams{RepositoryURL: "https://github.com/owner/repo"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.PullRequests) != 1 || len(result.PullRequests[0].LinkedIssues) != 1 {
+		t.Fatalf("expected a pull request with a linked issue, got %+v", result.PullRequests)
+	}
+
+	issue := result.PullRequests[0].LinkedIssues[0]
+	if issue.Number != 1 || issue.Host != "github.com" || !issue.CreatedAt.Equal(openedAt) || !issue.ClosedAt.Equal(mergedAt) {
+		t.Errorf("unexpected linked issue: %+v", issue)
+	}
+}
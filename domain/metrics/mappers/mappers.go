@@ -1,7 +1,11 @@
 package mappers
 
 import (
+	"strconv"
+
+	"github.com/chris-ramon/golang-scaffolding/domain/metrics"
 	"github.com/chris-ramon/golang-scaffolding/domain/metrics/api"
+	"github.com/chris-ramon/golang-scaffolding/domain/metrics/github"
 	"github.com/chris-ramon/golang-scaffolding/domain/metrics/types"
 )
 
@@ -21,6 +25,7 @@ func PullRequestsFromTypeToFindParam(pullRequests types.PullRequests) types.Find
 func PullRequestFromTypeToFindParam(pullRequest types.PullRequest) types.FindPullRequestParam {
 	result := types.FindPullRequestParam{
 		Number: pullRequest.Number,
+		Host:   pullRequest.Host,
 		Owner:  pullRequest.Owner,
 		Repo:   pullRequest.Repo,
 		URL:    pullRequest.URL,
@@ -47,6 +52,9 @@ func PullRequestFromTypeToAPI(pullRequest *types.PullRequest) api.PullRequest {
 		Number:    pullRequest.Number,
 		CreatedAt: pullRequest.CreatedAt,
 		MergedAt:  pullRequest.MergedAt,
+		ClosedAt:  pullRequest.ClosedAt,
+		State:     string(pullRequest.State),
+		IsDraft:   pullRequest.IsDraft,
 		URL:       pullRequest.URL,
 		Title:     pullRequest.Title,
 		Body:      pullRequest.Body,
@@ -56,6 +64,122 @@ func PullRequestFromTypeToAPI(pullRequest *types.PullRequest) api.PullRequest {
 		},
 		Contributors:          ContributorsFromTypeToAPI(pullRequest.Contributors),
 		FormattedContributors: pullRequest.FormattedContributors,
+		Repository:            pullRequestRepository(pullRequest),
+		HeadRefName:           pullRequest.HeadRefName,
+		BaseRefName:           pullRequest.BaseRefName,
+		Stack:                 PullRequestStackFromTypeToAPI(pullRequest.Stack),
+		Labels:                pullRequest.Labels,
+		Milestone:             pullRequest.Milestone,
+		Assignees:             pullRequest.Assignees,
+		Files:                 pullRequest.Files,
+		LinkedIssues:          LinkedIssuesFromTypeToAPI(pullRequest.LinkedIssues),
+		Error:                 PullRequestErrorFromTypeToAPI(pullRequest.Error),
+	}
+}
+
+// pullRequestRepository returns the owner and name of the repository of given pull request, empty when unknown.
+func pullRequestRepository(pullRequest *types.PullRequest) string {
+	if pullRequest.Owner == "" || pullRequest.Repo == "" {
+		return ""
+	}
+
+	return pullRequest.Owner + "/" + pullRequest.Repo
+}
+
+// PullRequestStackFromTypeToAPI maps given pull request stack internal type to pull request stack API type.
+func PullRequestStackFromTypeToAPI(stack *types.PullRequestStack) *api.PullRequestStack {
+	if stack == nil {
+		return nil
+	}
+
+	result := &api.PullRequestStack{
+		Root:     stack.Root,
+		Position: stack.Position,
+		Size:     stack.Size,
+	}
+
+	if stack.Parent != 0 {
+		parent := stack.Parent
+		result.Parent = &parent
+	}
+
+	return result
+}
+
+// IssuesFromTypeToAPI maps given issues internal types to issues API types.
+func IssuesFromTypeToAPI(issues []*types.Issue) api.Issues {
+	result := api.Issues{}
+
+	for _, issue := range issues {
+		result = append(result, IssueFromTypeToAPI(*issue))
+	}
+
+	return result
+}
+
+// LinkedIssuesFromTypeToAPI maps given pull request linked issues internal types to issues API types.
+func LinkedIssuesFromTypeToAPI(issues []types.Issue) api.Issues {
+	result := api.Issues{}
+
+	for _, issue := range issues {
+		result = append(result, IssueFromTypeToAPI(issue))
+	}
+
+	return result
+}
+
+// IssueFromTypeToAPI maps given issue internal type to issue API type.
+func IssueFromTypeToAPI(issue types.Issue) api.Issue {
+	return api.Issue{
+		Number:    issue.Number,
+		Owner:     issue.Owner,
+		Repo:      issue.Repo,
+		URL:       issue.URL,
+		Title:     issue.Title,
+		Body:      issue.Body,
+		State:     string(issue.State),
+		Author:    issue.Author.Login,
+		CreatedAt: issue.CreatedAt,
+		ClosedAt:  issue.ClosedAt,
+	}
+}
+
+// ReleasesFromTypeToAPI maps given releases internal types to releases API types.
+func ReleasesFromTypeToAPI(releases []*types.Release) api.Releases {
+	result := api.Releases{}
+
+	for _, release := range releases {
+		result = append(result, ReleaseFromTypeToAPI(*release))
+	}
+
+	return result
+}
+
+// ReleaseFromTypeToAPI maps given release internal type to release API type.
+func ReleaseFromTypeToAPI(release types.Release) api.Release {
+	return api.Release{
+		TagName:      release.TagName,
+		Owner:        release.Owner,
+		Repo:         release.Repo,
+		Name:         release.Name,
+		Description:  release.Description,
+		URL:          release.URL,
+		IsPrerelease: release.IsPrerelease,
+		Author:       release.Author.Login,
+		CreatedAt:    release.CreatedAt,
+		PublishedAt:  release.PublishedAt,
+	}
+}
+
+// PullRequestErrorFromTypeToAPI maps given pull request error internal type to pull request error API type.
+func PullRequestErrorFromTypeToAPI(pullRequestError *types.PullRequestError) *api.PullRequestError {
+	if pullRequestError == nil {
+		return nil
+	}
+
+	return &api.PullRequestError{
+		Code:    string(pullRequestError.Code),
+		Message: pullRequestError.Message,
 	}
 }
 
@@ -74,5 +198,99 @@ func ContributorsFromTypeToAPI(contributors types.Contributors) api.Contributors
 func ContributorFromTypeToAPI(contributor types.Contributor) api.Contributor {
 	return api.Contributor{
 		ProfileURL: contributor.ProfileURL,
+		Login:      contributor.Login,
+		Name:       contributor.Name,
+		Role:       string(contributor.Role),
+	}
+}
+
+// OpenWorkFromTypeToAPI maps given open work result to open work API type.
+func OpenWorkFromTypeToAPI(openWork *metrics.OpenWorkResult) api.OpenWork {
+	result := api.OpenWork{
+		Percentile:      openWork.Percentile,
+		ThresholdInDays: openWork.Threshold.Hours() / 24,
+		Items:           []api.OpenWorkItem{},
+	}
+
+	for _, item := range openWork.Items {
+		result.Items = append(result.Items, api.OpenWorkItem{
+			PullRequest:        PullRequestFromTypeToAPI(item.PullRequest),
+			AgeInDays:          item.Age.Hours() / 24,
+			LastActivityAt:     item.PullRequest.UpdatedAt,
+			RequestedReviewers: item.PullRequest.RequestedReviewers,
+			ReviewState:        item.PullRequest.ReviewDecision,
+			CIStatus:           item.PullRequest.CIStatus,
+			ExceedsThreshold:   item.ExceedsThreshold,
+		})
+	}
+
+	return result
+}
+
+// StatisticsFromTypeToAPI maps given pull requests statistics result to statistics API type.
+func StatisticsFromTypeToAPI(statistics *metrics.PullRequestsStatisticsResult) api.Statistics {
+	result := api.Statistics{
+		Combined:     RepositoryStatisticsFromTypeToAPI(statistics.Combined),
+		Repositories: []api.RepositoryStatistics{},
+		Truncated:    statistics.Truncated,
+	}
+
+	for _, repositoryStatistics := range statistics.Repositories {
+		result.Repositories = append(result.Repositories, RepositoryStatisticsFromTypeToAPI(repositoryStatistics))
+	}
+
+	return result
+}
+
+// RepositoryStatisticsFromTypeToAPI maps given repository pull requests statistics to repository statistics API type.
+func RepositoryStatisticsFromTypeToAPI(statistics metrics.PullRequestsStatistics) api.RepositoryStatistics {
+	result := api.RepositoryStatistics{
+		URL:                    statistics.RepositoryURL,
+		Count:                  statistics.Count,
+		OpenCount:              statistics.OpenCount,
+		MergedCount:            statistics.MergedCount,
+		ClosedCount:            statistics.ClosedCount,
+		AverageCycleTimeInDays: statistics.AverageCycleTime.Hours() / 24,
+		MedianCycleTimeInDays:  statistics.MedianCycleTime.Hours() / 24,
+	}
+
+	if ref, err := github.ParseRepoRef(statistics.RepositoryURL); err == nil {
+		result.Repository = ref.Owner + "/" + ref.Repo
+	}
+
+	return result
+}
+
+// RateLimitFromTypeToAPI maps given rate limit internal type to rate limit API type.
+func RateLimitFromTypeToAPI(rateLimit *types.RateLimit) api.RateLimit {
+	return api.RateLimit{
+		Limit:     rateLimit.Limit,
+		Cost:      rateLimit.Cost,
+		Remaining: rateLimit.Remaining,
+		Used:      rateLimit.Used,
+		ResetAt:   rateLimit.ResetAt,
 	}
 }
+
+func TrackedRepositoryFromTypeToAPI(trackedRepository *types.TrackedRepository) api.TrackedRepository {
+	return api.TrackedRepository{
+		ID:             strconv.Itoa(trackedRepository.ID),
+		Owner:          trackedRepository.Owner,
+		Repo:           trackedRepository.Repo,
+		URL:            trackedRepository.URL,
+		TrackedAt:      trackedRepository.TrackedAt,
+		LastSyncedAt:   trackedRepository.LastSyncedAt,
+		LastSyncStatus: string(trackedRepository.LastSyncStatus),
+		LastSyncError:  trackedRepository.LastSyncError,
+	}
+}
+
+func TrackedRepositoriesFromTypeToAPI(trackedRepositories []*types.TrackedRepository) []api.TrackedRepository {
+	result := []api.TrackedRepository{}
+
+	for _, trackedRepository := range trackedRepositories {
+		result = append(result, TrackedRepositoryFromTypeToAPI(trackedRepository))
+	}
+
+	return result
+}
@@ -0,0 +1,117 @@
+package metrics
+
+import (
+	"context"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/chris-ramon/golang-scaffolding/domain/metrics/types"
+)
+
+// DefaultOpenWorkPercentile is the default historical cycle time percentile past which open pull requests are flagged.
+const DefaultOpenWorkPercentile = 85
+
+type OpenWorkParams struct {
+	RepositoryURL string
+
+	// Percentile is the historical cycle time percentile past which open pull requests are flagged,
+	// defaults to `DefaultOpenWorkPercentile`.
+	Percentile float64
+}
+
+type OpenWorkItem struct {
+	PullRequest *types.PullRequest
+
+	// Age is the time elapsed since the pull request was created.
+	Age time.Duration
+
+	// ExceedsThreshold reports whether the age exceeds the historical cycle time threshold.
+	ExceedsThreshold bool
+}
+
+type OpenWorkResult struct {
+	// Percentile is the historical cycle time percentile used as threshold.
+	Percentile float64
+
+	// Threshold is the merged pull requests cycle time at the percentile, zero without history.
+	Threshold time.Duration
+
+	// Items are the open pull requests, oldest first.
+	Items []OpenWorkItem
+}
+
+// OpenWork returns the open pull requests of a repository with their age, flagging the ones
+// older than the given percentile of the merged pull requests cycle time.
+func (s *service) OpenWork(ctx context.Context, params OpenWorkParams) (*OpenWorkResult, error) {
+	percentile := params.Percentile
+	if percentile <= 0 || percentile > 100 {
+		percentile = DefaultOpenWorkPercentile
+	}
+
+	openPullRequests, err := s.FindAllPullRequests(ctx, FindAllPullRequestsParams{
+		RepositoryURL: params.RepositoryURL,
+		States:        []types.PullRequestState{types.OpenPullRequestState},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	mergedPullRequests, err := s.FindAllPullRequests(ctx, FindAllPullRequestsParams{
+		RepositoryURL: params.RepositoryURL,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	cycleTimes := []time.Duration{}
+	for _, pr := range mergedPullRequests.PullRequests {
+		cycleTimes = append(cycleTimes, pr.Duration)
+	}
+
+	result := &OpenWorkResult{
+		Percentile: percentile,
+		Threshold:  durationPercentile(cycleTimes, percentile),
+	}
+
+	now := time.Now().UTC()
+
+	for _, pr := range openPullRequests.PullRequests {
+		age := now.Sub(pr.CreatedAt.UTC())
+
+		result.Items = append(result.Items, OpenWorkItem{
+			PullRequest:      pr,
+			Age:              age,
+			ExceedsThreshold: result.Threshold > 0 && age > result.Threshold,
+		})
+	}
+
+	slices.SortFunc(result.Items, func(a, b OpenWorkItem) int {
+		if a.Age > b.Age {
+			return -1
+		}
+		if a.Age < b.Age {
+			return 1
+		}
+		return 0
+	})
+
+	return result, nil
+}
+
+// durationPercentile returns the nearest-rank percentile of the given durations, zero when empty.
+func durationPercentile(durations []time.Duration, percentile float64) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+
+	sorted := slices.Clone(durations)
+	slices.Sort(sorted)
+
+	rank := int(math.Ceil(percentile / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+
+	return sorted[rank-1]
+}
@@ -0,0 +1,118 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+
+	cachePkg "github.com/chris-ramon/golang-scaffolding/cache"
+	"github.com/chris-ramon/golang-scaffolding/domain/metrics/github"
+	"github.com/chris-ramon/golang-scaffolding/domain/metrics/types"
+)
+
+func TestOpenWork(t *testing.T) {
+	now := time.Now()
+	day := 24 * time.Hour
+
+	mockGH := &mockGitHub{
+		allPullRequests: func(params github.AllPullRequestsParams) (github.AllPullRequestsQuery, error) {
+			nodes := github.AllPullRequestsNodes{}
+
+			if len(params.States) == 1 && params.States[0] == types.OpenPullRequestState {
+				nodes = append(nodes,
+					github.AllPullRequestsNode{
+						Number:    githubv4.Int(10),
+						CreatedAt: githubv4.DateTime{Time: now.Add(-1 * day)},
+						UpdatedAt: githubv4.DateTime{Time: now},
+						State:     githubv4.PullRequestStateOpen,
+					},
+					github.AllPullRequestsNode{
+						Number:         githubv4.Int(11),
+						CreatedAt:      githubv4.DateTime{Time: now.Add(-10 * day)},
+						State:          githubv4.PullRequestStateOpen,
+						ReviewDecision: githubv4.PullRequestReviewDecisionReviewRequired,
+						ReviewRequests: github.ReviewRequests{
+							Nodes: github.ReviewRequestsNodes{
+								{RequestedReviewer: github.RequestedReviewer{User: struct{ Login githubv4.String }{Login: "reviewer"}}},
+							},
+						},
+					},
+				)
+			} else {
+				for i := 1; i <= 4; i++ {
+					nodes = append(nodes, github.AllPullRequestsNode{
+						Number:    githubv4.Int(i),
+						CreatedAt: githubv4.DateTime{Time: now.Add(-time.Duration(i) * day)},
+						MergedAt:  githubv4.DateTime{Time: now},
+					})
+				}
+			}
+
+			return github.AllPullRequestsQuery{
+				Repository: github.AllPullRequestsRepository{
+					PullRequests: github.AllPullRequestsPullRequests{Nodes: nodes},
+				},
+			}, nil
+		},
+	}
+
+	srv := &service{
+		cache:  cachePkg.New(),
+		GitHub: mockGH,
+	}
+
+	result, err := srv.OpenWork(context.Background(), OpenWorkParams{
+		RepositoryURL: "https://github.com/test/repo",
+		Percentile:    75,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.Threshold.Round(time.Hour) != 3*day {
+		t.Errorf("expected threshold of 3 days, got %v", result.Threshold)
+	}
+
+	if len(result.Items) != 2 {
+		t.Fatalf("expected 2 open pull requests, got %d", len(result.Items))
+	}
+
+	oldest := result.Items[0]
+	if oldest.PullRequest.Number != 11 || !oldest.ExceedsThreshold {
+		t.Errorf("expected oldest pull request #11 to exceed threshold, got #%d (%v)", oldest.PullRequest.Number, oldest.ExceedsThreshold)
+	}
+
+	if len(oldest.PullRequest.RequestedReviewers) != 1 || oldest.PullRequest.RequestedReviewers[0] != "reviewer" {
+		t.Errorf("expected requested reviewer, got %v", oldest.PullRequest.RequestedReviewers)
+	}
+
+	if result.Items[1].ExceedsThreshold {
+		t.Errorf("expected newest pull request not to exceed threshold")
+	}
+}
+
+func TestDurationPercentile(t *testing.T) {
+	durations := []time.Duration{4, 1, 3, 2}
+
+	testCases := []struct {
+		percentile float64
+		expected   time.Duration
+	}{
+		{percentile: 50, expected: 2},
+		{percentile: 75, expected: 3},
+		{percentile: 100, expected: 4},
+		{percentile: 1, expected: 1},
+	}
+
+	for _, tc := range testCases {
+		if result := durationPercentile(durations, tc.percentile); result != tc.expected {
+			t.Errorf("expected percentile %v to be %v, got %v", tc.percentile, tc.expected, result)
+		}
+	}
+
+	if result := durationPercentile(nil, 50); result != 0 {
+		t.Errorf("expected zero percentile without durations, got %v", result)
+	}
+}
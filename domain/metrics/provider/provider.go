@@ -0,0 +1,147 @@
+package provider
+
+import (
+	"context"
+	"errors"
+
+	"github.com/chris-ramon/golang-scaffolding/domain/metrics/types"
+)
+
+// Provider defines the interface of the code hosting providers the pull requests, or merge requests, are
+// fetched from.
+type Provider interface {
+	// AllPullRequests returns the pull requests of the given states of a repository.
+	AllPullRequests(ctx context.Context, params AllPullRequestsParams) (*AllPullRequestsResult, error)
+
+	// PullRequests returns the given pull requests in the same order, reporting the ones that could not be
+	// fetched in their result instead of failing them all.
+	PullRequests(ctx context.Context, params PullRequestsParams) ([]PullRequestResult, error)
+}
+
+// IssueProvider defines the interface of the providers the issues of a repository are also fetched from.
+type IssueProvider interface {
+	// AllIssues returns the issues of the given states of a repository.
+	AllIssues(ctx context.Context, params AllIssuesParams) (*AllIssuesResult, error)
+}
+
+// RepositoryProvider defines the interface of the providers the repositories of an organization, or user, are
+// also listed from.
+type RepositoryProvider interface {
+	// AllRepositories returns the URLs of the active repositories of an owner.
+	AllRepositories(ctx context.Context, params AllRepositoriesParams) (*AllRepositoriesResult, error)
+}
+
+// ReleaseProvider defines the interface of the providers the releases of a repository are also fetched from.
+type ReleaseProvider interface {
+	// AllReleases returns the published releases of a repository.
+	AllReleases(ctx context.Context, params AllReleasesParams) (*AllReleasesResult, error)
+}
+
+// AllPullRequestsParams represents the AllPullRequests parameters.
+type AllPullRequestsParams struct {
+	// Host is the repository host.
+	Host string
+	// Owner is the repository owner, the namespace of GitLab projects.
+	Owner string
+	// Repo is the repository name.
+	Repo string
+
+	// States are the pull request states to fetch, defaults to merged pull requests.
+	States []types.PullRequestState
+}
+
+// AllPullRequestsResult represents the AllPullRequests result.
+type AllPullRequestsResult struct {
+	PullRequests []*types.PullRequest
+
+	// Truncated reports whether pull requests were left out by the maximum number of fetched pages.
+	Truncated bool
+}
+
+// AllIssuesParams represents the AllIssues parameters.
+type AllIssuesParams struct {
+	// Host is the repository host.
+	Host string
+	// Owner is the repository owner.
+	Owner string
+	// Repo is the repository name.
+	Repo string
+
+	// States are the issue states to fetch, defaults to every state.
+	States []types.IssueState
+}
+
+// AllIssuesResult represents the AllIssues result.
+type AllIssuesResult struct {
+	Issues []*types.Issue
+
+	// Truncated reports whether issues were left out by the maximum number of fetched pages.
+	Truncated bool
+}
+
+// AllReleasesParams represents the AllReleases parameters.
+type AllReleasesParams struct {
+	// Host is the repository host.
+	Host string
+	// Owner is the repository owner, the namespace of GitLab projects.
+	Owner string
+	// Repo is the repository name.
+	Repo string
+}
+
+// AllReleasesResult represents the AllReleases result.
+type AllReleasesResult struct {
+	// Releases are the releases, most recently created first.
+	Releases []*types.Release
+
+	// Truncated reports whether releases were left out by the maximum number of fetched pages.
+	Truncated bool
+}
+
+// AllRepositoriesParams represents the AllRepositories parameters.
+type AllRepositoriesParams struct {
+	// Host is the owner host.
+	Host string
+	// Owner is the organization or user owning the repositories.
+	Owner string
+}
+
+// AllRepositoriesResult represents the AllRepositories result.
+type AllRepositoriesResult struct {
+	// RepositoryURLs are the URLs of the repositories sorted by name.
+	RepositoryURLs []string
+
+	// Truncated reports whether repositories were left out by the maximum number of fetched pages.
+	Truncated bool
+}
+
+// PullRequestParams represents a pull request of the PullRequests parameters.
+type PullRequestParams struct {
+	// Owner is the repository owner.
+	Owner string
+	// Repo is the repository name.
+	Repo string
+	// Number is the pull request number.
+	Number int
+}
+
+// PullRequestsParams represents the PullRequests parameters.
+type PullRequestsParams struct {
+	// Host is the host of the pull requests.
+	Host string
+
+	// PullRequests are the pull requests to fetch, of a single owner.
+	PullRequests []PullRequestParams
+}
+
+// PullRequestResult represents a pull request of the PullRequests result.
+type PullRequestResult struct {
+	// PullRequest is the fetched pull request, nil when Err is set.
+	PullRequest *types.PullRequest
+
+	// Err is the error fetching the pull request.
+	Err error
+}
+
+// ErrPullRequestNotFound is the error of the pull requests that do not exist.
+var ErrPullRequestNotFound = errors.New("pull request not found")
@@ -0,0 +1,134 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/chris-ramon/golang-scaffolding/domain/metrics/github"
+	"github.com/chris-ramon/golang-scaffolding/domain/metrics/provider"
+)
+
+// gitHubProvider is the provider of the github.com and GitHub Enterprise Server repositories.
+type gitHubProvider struct {
+	client github.GitHubClient
+}
+
+// AllPullRequests returns the pull requests of the given states of a GitHub repository.
+func (p *gitHubProvider) AllPullRequests(ctx context.Context, params provider.AllPullRequestsParams) (*provider.AllPullRequestsResult, error) {
+	r, err := p.client.AllPullRequests(ctx, github.AllPullRequestsParams{
+		Owner:  params.Owner,
+		Repo:   params.Repo,
+		States: params.States,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &provider.AllPullRequestsResult{
+		PullRequests: pullRequestsFromNodes(params.Host, params.Owner, params.Repo, r.Repository.PullRequests.Nodes),
+		Truncated:    bool(r.Repository.PullRequests.PageInfo.HasNextPage),
+	}, nil
+}
+
+// PullRequests returns the given GitHub pull requests in the same order.
+func (p *gitHubProvider) PullRequests(ctx context.Context, params provider.PullRequestsParams) ([]provider.PullRequestResult, error) {
+	pullRequestsParams := github.PullRequestsParams{}
+	for _, param := range params.PullRequests {
+		pullRequestsParams.PullRequests = append(pullRequestsParams.PullRequests, github.PullRequestParams{
+			Owner:  param.Owner,
+			Repo:   param.Repo,
+			Number: param.Number,
+		})
+	}
+
+	r, err := p.client.PullRequests(ctx, pullRequestsParams)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]provider.PullRequestResult, len(r))
+	for i, param := range params.PullRequests {
+		switch {
+		case r[i].Err != nil:
+			results[i].Err = r[i].Err
+		case r[i].PullRequest.CreatedAt.Time.IsZero():
+			results[i].Err = fmt.Errorf("unexpected created at nil value")
+		default:
+			results[i].PullRequest = pullRequestFromNode(params.Host, param.Owner, param.Repo, r[i].PullRequest)
+		}
+	}
+
+	return results, nil
+}
+
+// AllIssues returns the issues of the given states of a GitHub repository.
+func (p *gitHubProvider) AllIssues(ctx context.Context, params provider.AllIssuesParams) (*provider.AllIssuesResult, error) {
+	r, err := p.client.AllIssues(ctx, github.AllIssuesParams{
+		Owner:  params.Owner,
+		Repo:   params.Repo,
+		States: params.States,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &provider.AllIssuesResult{
+		Issues:    issuesFromNodes(params.Host, r.Repository.Issues.Nodes),
+		Truncated: bool(r.Repository.Issues.PageInfo.HasNextPage),
+	}, nil
+}
+
+// AllReleases returns the published releases of a GitHub repository.
+func (p *gitHubProvider) AllReleases(ctx context.Context, params provider.AllReleasesParams) (*provider.AllReleasesResult, error) {
+	r, err := p.client.AllReleases(ctx, github.AllReleasesParams{
+		Owner: params.Owner,
+		Repo:  params.Repo,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &provider.AllReleasesResult{
+		Releases:  releasesFromNodes(params.Host, params.Owner, params.Repo, r.Repository.Releases.Nodes),
+		Truncated: bool(r.Repository.Releases.PageInfo.HasNextPage),
+	}, nil
+}
+
+// AllRepositories returns the URLs of the repositories of a GitHub organization or user, leaving out the
+// archived ones.
+func (p *gitHubProvider) AllRepositories(ctx context.Context, params provider.AllRepositoriesParams) (*provider.AllRepositoriesResult, error) {
+	r, err := p.client.AllRepositories(ctx, github.AllRepositoriesParams{
+		Owner: params.Owner,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	repositoryURLs := []string{}
+	for _, node := range r.RepositoryOwner.Repositories.Nodes {
+		if node.IsArchived {
+			continue
+		}
+		repositoryURLs = append(repositoryURLs, string(node.URL))
+	}
+
+	return &provider.AllRepositoriesResult{
+		RepositoryURLs: repositoryURLs,
+		Truncated:      bool(r.RepositoryOwner.Repositories.PageInfo.HasNextPage),
+	}, nil
+}
+
+// providerFor returns the provider of the given host: the GitLab, Gitea and local hosts first, GitHub otherwise.
+func (s *service) providerFor(host string) (provider.Provider, error) {
+	if p, ok := s.Providers[strings.ToLower(host)]; ok {
+		return p, nil
+	}
+
+	gh, err := s.gitHubFor(host)
+	if err != nil {
+		return nil, err
+	}
+
+	return &gitHubProvider{client: gh}, nil
+}
@@ -0,0 +1,137 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/chris-ramon/golang-scaffolding/domain/metrics/github"
+	"github.com/chris-ramon/golang-scaffolding/domain/metrics/provider"
+	"github.com/chris-ramon/golang-scaffolding/domain/metrics/types"
+)
+
+type FindAllReleasesParams struct {
+	RepositoryURL string
+}
+
+type FindAllReleasesResult struct {
+	Releases []*types.Release
+
+	// Truncated reports whether releases were left out by the maximum number of fetched pages.
+	Truncated bool
+}
+
+// `findAllReleasesCacheKey` returns cache key of `FindAllReleases`, whose parameters are named apart from the
+// `FindAllPullRequests` ones they share their fields with.
+func (s *service) findAllReleasesCacheKey(params FindAllReleasesParams) (string, error) {
+	key, err := json.Marshal(struct {
+		Releases   FindAllReleasesParams
+		Generation uint64
+	}{params, s.repositoryCacheGeneration(params.RepositoryURL)})
+	if err != nil {
+		return "", err
+	}
+
+	return string(key), nil
+}
+
+// `getFindAllReleasesCacheValue` returns cached data of `FindAllReleases`.
+func (s *service) getFindAllReleasesCacheValue(data any) (*FindAllReleasesResult, error) {
+	result, ok := data.(*FindAllReleasesResult)
+	if !ok {
+		return nil, errors.New("unexpected type")
+	}
+
+	return result, nil
+}
+
+// `cacheFindAllReleasesValue` caches given result of `FindAllReleases`.
+func (s *service) cacheFindAllReleasesValue(key string, data any) {
+	s.cache.Add(key, data)
+}
+
+// FindAllReleases returns the published releases of a repository, most recently created first. Only the
+// providers implementing `provider.ReleaseProvider` have releases.
+func (s *service) FindAllReleases(ctx context.Context, params FindAllReleasesParams) (*FindAllReleasesResult, error) {
+	key, err := s.findAllReleasesCacheKey(params)
+	if err != nil {
+		return nil, err
+	}
+
+	findAllReleasesCacheVal, found := s.cache.Get(key)
+	if found {
+		return s.getFindAllReleasesCacheValue(findAllReleasesCacheVal)
+	}
+
+	ref, err := github.ParseRepoRef(params.RepositoryURL)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := s.providerFor(ref.Host)
+	if err != nil {
+		return nil, err
+	}
+
+	releaseProvider, ok := p.(provider.ReleaseProvider)
+	if !ok {
+		return nil, fmt.Errorf("releases are not supported on host %q", ref.Host)
+	}
+
+	r, err := releaseProvider.AllReleases(ctx, provider.AllReleasesParams{
+		Host:  ref.Host,
+		Owner: ref.Owner,
+		Repo:  ref.Repo,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &FindAllReleasesResult{
+		Releases:  r.Releases,
+		Truncated: r.Truncated,
+	}
+
+	s.cacheFindAllReleasesValue(key, result)
+
+	return result, nil
+}
+
+// releasesFromNodes returns the published releases of the given GitHub nodes of a repository, skipping the
+// drafts.
+func releasesFromNodes(host string, owner string, repo string, nodes github.ReleasesNodes) []*types.Release {
+	releases := []*types.Release{}
+
+	for _, releaseNode := range nodes {
+		if releaseNode.IsDraft {
+			continue
+		}
+
+		release := &types.Release{
+			TagName:      string(releaseNode.TagName),
+			Host:         host,
+			Owner:        owner,
+			Repo:         repo,
+			Name:         string(releaseNode.Name),
+			Description:  string(releaseNode.Description),
+			URL:          string(releaseNode.URL),
+			IsPrerelease: bool(releaseNode.IsPrerelease),
+			Author:       types.Author{Login: string(releaseNode.Author.Login)},
+		}
+
+		if !releaseNode.CreatedAt.Time.IsZero() {
+			createdAt := releaseNode.CreatedAt.UTC()
+			release.CreatedAt = &createdAt
+		}
+
+		if !releaseNode.PublishedAt.Time.IsZero() {
+			publishedAt := releaseNode.PublishedAt.UTC()
+			release.PublishedAt = &publishedAt
+		}
+
+		releases = append(releases, release)
+	}
+
+	return releases
+}
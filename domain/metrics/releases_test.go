@@ -0,0 +1,90 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+
+	cachePkg "github.com/chris-ramon/golang-scaffolding/cache"
+	"github.com/chris-ramon/golang-scaffolding/domain/metrics/github"
+	"github.com/chris-ramon/golang-scaffolding/domain/metrics/provider"
+)
+
+func TestFindAllReleases(t *testing.T) {
+	createdAt := time.Now().Add(-48 * time.Hour)
+	publishedAt := time.Now()
+	calls := 0
+
+	mockGH := &mockGitHub{
+		allReleases: func(params github.AllReleasesParams) (github.AllReleasesQuery, error) {
+			calls++
+
+			if params.Owner != "owner" || params.Repo != "repo" {
+				t.Errorf("unexpected params: %+v", params)
+			}
+
+			query := github.AllReleasesQuery{}
+			query.Repository.Releases.Nodes = github.ReleasesNodes{
+				{TagName: "v2.0.0", IsDraft: true, CreatedAt: githubv4.DateTime{Time: publishedAt}},
+				{
+					TagName:     "v1.0.0",
+					Name:        "First release",
+					URL:         "https://github.com/owner/repo/releases/tag/v1.0.0",
+					CreatedAt:   githubv4.DateTime{Time: createdAt},
+					PublishedAt: githubv4.DateTime{Time: publishedAt},
+					Author:      github.Author{Login: "author"},
+				},
+			}
+			query.Repository.Releases.PageInfo.HasNextPage = true
+			return query, nil
+		},
+	}
+
+	srv := &service{
+		cache:  cachePkg.New(),
+		GitHub: mockGH,
+	}
+
+	params := FindAllReleasesParams{RepositoryURL: "https://github.com/owner/repo"}
+
+	result, err := srv.FindAllReleases(context.Background(), params)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.Releases) != 1 || !result.Truncated {
+		t.Fatalf("expected the draft release to be skipped from a truncated result, got %d releases, truncated: %v", len(result.Releases), result.Truncated)
+	}
+
+	release := result.Releases[0]
+	if release.TagName != "v1.0.0" || release.Host != "github.com" || release.Owner != "owner" || release.Repo != "repo" || release.Author.Login != "author" {
+		t.Errorf("unexpected release identity: %+v", release)
+	}
+
+	if release.CreatedAt == nil || !release.CreatedAt.Equal(createdAt) || release.PublishedAt == nil || !release.PublishedAt.Equal(publishedAt) {
+		t.Errorf("unexpected release times: %+v", release)
+	}
+
+	if _, err := srv.FindAllReleases(context.Background(), params); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if calls != 1 {
+		t.Errorf("expected the second call to be cached, got %d calls", calls)
+	}
+}
+
+func TestFindAllReleasesUnsupportedHost(t *testing.T) {
+	srv := &service{
+		cache:     cachePkg.New(),
+		GitHub:    &mockGitHub{},
+		Providers: map[string]provider.Provider{"gitlab.com": &mockProvider{}},
+	}
+
+	_, err := srv.FindAllReleases(context.Background(), FindAllReleasesParams{RepositoryURL: "https://gitlab.com/group/project"})
+	if err == nil {
+		t.Errorf("expected error for a host without releases")
+	}
+}
@@ -0,0 +1,456 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/chris-ramon/golang-scaffolding/db"
+	"github.com/chris-ramon/golang-scaffolding/db/models"
+	"github.com/chris-ramon/golang-scaffolding/domain/metrics/types"
+)
+
+type repo struct {
+	db db.DB
+}
+
+type FindRepoPullRequestsParams struct {
+	Host  string
+	Owner string
+	Repo  string
+
+	// States are the pull request states to find.
+	States []types.PullRequestState
+
+	// SyncedAfter is the time the repository must have been synced after for the pull requests to be found.
+	SyncedAfter time.Time
+}
+
+type SaveRepoPullRequestsParams struct {
+	Host  string
+	Owner string
+	Repo  string
+
+	// States are the pull request states the pull requests were fetched for.
+	States []types.PullRequestState
+
+	PullRequests []*types.PullRequest
+
+	// SyncedAt is the time the pull requests were fetched.
+	SyncedAt time.Time
+}
+
+type TrackRepoRepositoryParams struct {
+	Host  string
+	Owner string
+	Repo  string
+	URL   string
+}
+
+// FindPullRequests returns the persisted pull requests of the given states, most recently created first.
+// It returns false when the repository was not synced for all the given states after the given time.
+func (r *repo) FindPullRequests(ctx context.Context, params FindRepoPullRequestsParams) ([]*types.PullRequest, bool, error) {
+	repository, err := r.db.Queries().GetRepository(ctx, models.GetRepositoryParams{
+		Host:  params.Host,
+		Owner: params.Owner,
+		Name:  params.Repo,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	syncs, err := r.db.Queries().ListRepositorySyncs(ctx, repository.ID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	syncedAtByState := map[string]time.Time{}
+	for _, sync := range syncs {
+		syncedAtByState[sync.State] = sync.SyncedAt
+	}
+
+	states := pullRequestStateStrings(params.States)
+	for _, state := range states {
+		syncedAt, ok := syncedAtByState[state]
+		if !ok || syncedAt.Before(params.SyncedAfter) {
+			return nil, false, nil
+		}
+	}
+
+	pullRequests, err := r.db.Queries().ListPullRequests(ctx, models.ListPullRequestsParams{
+		RepositoryID: repository.ID,
+		States:       states,
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	participants, err := r.db.Queries().ListPullRequestParticipants(ctx, repository.ID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	linkedIssues, err := r.db.Queries().ListPullRequestLinkedIssues(ctx, repository.ID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	linkedIssuesByPullRequest := map[int32][]types.Issue{}
+	for _, linkedIssue := range linkedIssues {
+		openedAt := linkedIssue.OpenedAt.UTC()
+		linkedIssuesByPullRequest[linkedIssue.PullRequestID] = append(linkedIssuesByPullRequest[linkedIssue.PullRequestID], types.Issue{
+			Number:    int(linkedIssue.Number),
+			Host:      repository.Host,
+			Owner:     linkedIssue.Owner,
+			Repo:      linkedIssue.Repo,
+			Title:     linkedIssue.Title,
+			Body:      linkedIssue.Body,
+			URL:       linkedIssue.Url,
+			State:     types.IssueState(linkedIssue.State),
+			CreatedAt: &openedAt,
+			ClosedAt:  timeFromNullTime(linkedIssue.ClosedAt),
+			Author:    types.Author{Login: linkedIssue.AuthorLogin},
+		})
+	}
+
+	contributorsByPullRequest := map[int32]types.Contributors{}
+	for _, participant := range participants {
+		contributorsByPullRequest[participant.PullRequestID] = append(contributorsByPullRequest[participant.PullRequestID], types.Contributor{
+			Login:      participant.Login,
+			Email:      participant.Email,
+			Name:       participant.Name,
+			ProfileURL: participant.ProfileUrl,
+			Role:       types.ContributorRole(participant.Role),
+		})
+	}
+
+	result := []*types.PullRequest{}
+
+	for _, pullRequest := range pullRequests {
+		contributors := contributorsByPullRequest[pullRequest.ID]
+
+		createdAt := pullRequest.OpenedAt.UTC()
+		pr := &types.PullRequest{
+			Number:                int(pullRequest.Number),
+			Host:                  repository.Host,
+			Owner:                 repository.Owner,
+			Repo:                  repository.Name,
+			Title:                 pullRequest.Title,
+			Body:                  pullRequest.Body,
+			CreatedAt:             &createdAt,
+			MergedAt:              timeFromNullTime(pullRequest.MergedAt),
+			ClosedAt:              timeFromNullTime(pullRequest.ClosedAt),
+			UpdatedAt:             timeFromNullTime(pullRequest.LastActivityAt),
+			State:                 types.PullRequestState(pullRequest.State),
+			IsDraft:               pullRequest.IsDraft,
+			RequestedReviewers:    pullRequest.RequestedReviewers,
+			ReviewDecision:        pullRequest.ReviewDecision,
+			CIStatus:              pullRequest.CiStatus,
+			URL:                   pullRequest.Url,
+			Contributors:          contributors,
+			HeadRefName:           pullRequest.HeadRefName,
+			BaseRefName:           pullRequest.BaseRefName,
+			MergeCommit:           pullRequest.MergeCommit,
+			Labels:                pullRequest.Labels,
+			Milestone:             pullRequest.Milestone,
+			Assignees:             pullRequest.Assignees,
+			Files:                 pullRequest.Files,
+			FormattedContributors: contributors.FormattedContributors(types.CommasFormatContributorType),
+			LinkedIssues:          linkedIssuesByPullRequest[pullRequest.ID],
+		}
+		pr.Duration = pullRequestDuration(pr)
+
+		result = append(result, pr)
+	}
+
+	return result, true, nil
+}
+
+// SavePullRequests persists the given pull requests with their contributors and linked issues, and marks the
+// repository as synced for the given states. The given pull requests must be all the pull requests of the given
+// states, the persisted ones of these states missing from them, like the open pull requests closed since, are
+// deleted.
+func (r *repo) SavePullRequests(ctx context.Context, params SaveRepoPullRequestsParams) error {
+	return r.db.WithTx(ctx, func(queries *models.Queries) error {
+		repository, err := queries.UpsertRepository(ctx, models.UpsertRepositoryParams{
+			Host:  params.Host,
+			Owner: params.Owner,
+			Name:  params.Repo,
+		})
+		if err != nil {
+			return err
+		}
+
+		pullRequestIDs := []int32{}
+
+		for _, pr := range params.PullRequests {
+			if pr.CreatedAt == nil {
+				continue
+			}
+
+			pullRequestID, err := queries.UpsertPullRequest(ctx, models.UpsertPullRequestParams{
+				RepositoryID:       repository.ID,
+				Number:             int32(pr.Number),
+				Url:                pr.URL,
+				Title:              pr.Title,
+				Body:               pr.Body,
+				State:              string(pr.State),
+				IsDraft:            pr.IsDraft,
+				HeadRefName:        pr.HeadRefName,
+				BaseRefName:        pr.BaseRefName,
+				MergeCommit:        pr.MergeCommit,
+				ReviewDecision:     pr.ReviewDecision,
+				CiStatus:           pr.CIStatus,
+				RequestedReviewers: append([]string{}, pr.RequestedReviewers...),
+				OpenedAt:           *pr.CreatedAt,
+				MergedAt:           nullTimeFromTime(pr.MergedAt),
+				ClosedAt:           nullTimeFromTime(pr.ClosedAt),
+				LastActivityAt:     nullTimeFromTime(pr.UpdatedAt),
+				Labels:             append([]string{}, pr.Labels...),
+				Milestone:          pr.Milestone,
+				Assignees:          append([]string{}, pr.Assignees...),
+				Files:              append([]string{}, pr.Files...),
+			})
+			if err != nil {
+				return err
+			}
+			pullRequestIDs = append(pullRequestIDs, pullRequestID)
+
+			if err := queries.DeletePullRequestParticipants(ctx, pullRequestID); err != nil {
+				return err
+			}
+
+			contributorIDs := map[int32]bool{}
+
+			for position, contributor := range pr.Contributors {
+				contributorID, err := upsertContributor(ctx, queries, params.Host, contributor)
+				if err != nil {
+					return err
+				}
+
+				// The same login can come with several emails, e.g. as author and as a co-author trailer.
+				if contributorIDs[contributorID] {
+					continue
+				}
+				contributorIDs[contributorID] = true
+
+				err = queries.CreatePullRequestParticipant(ctx, models.CreatePullRequestParticipantParams{
+					PullRequestID: pullRequestID,
+					ContributorID: contributorID,
+					Role:          string(contributor.Role),
+					Position:      int32(position),
+				})
+				if err != nil {
+					return err
+				}
+			}
+
+			if err := queries.DeletePullRequestLinkedIssues(ctx, pullRequestID); err != nil {
+				return err
+			}
+
+			for position, issue := range pr.LinkedIssues {
+				if issue.CreatedAt == nil {
+					continue
+				}
+
+				err := queries.CreatePullRequestLinkedIssue(ctx, models.CreatePullRequestLinkedIssueParams{
+					PullRequestID: pullRequestID,
+					Position:      int32(position),
+					Owner:         issue.Owner,
+					Repo:          issue.Repo,
+					Number:        int32(issue.Number),
+					Url:           issue.URL,
+					Title:         issue.Title,
+					Body:          issue.Body,
+					State:         string(issue.State),
+					AuthorLogin:   issue.Author.Login,
+					OpenedAt:      *issue.CreatedAt,
+					ClosedAt:      nullTimeFromTime(issue.ClosedAt),
+				})
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		states := pullRequestStateStrings(params.States)
+
+		err = queries.DeleteStalePullRequests(ctx, models.DeleteStalePullRequestsParams{
+			RepositoryID: repository.ID,
+			States:       states,
+			Ids:          pullRequestIDs,
+		})
+		if err != nil {
+			return err
+		}
+
+		// Each state has its own sync time, the ones of the other states are left as they are.
+		for _, state := range states {
+			err := queries.UpsertRepositorySync(ctx, models.UpsertRepositorySyncParams{
+				RepositoryID: repository.ID,
+				State:        state,
+				SyncedAt:     params.SyncedAt,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// upsertContributor persists the given contributor of a host, identified by its login, or by its email when it
+// has no login like the co-authors of commit trailers, and returns its ID. The same login on two hosts can be two
+// different people, each keeping the profile URL of its own host.
+func upsertContributor(ctx context.Context, queries *models.Queries, host string, contributor types.Contributor) (int32, error) {
+	if contributor.Login == "" {
+		return queries.UpsertContributorByEmail(ctx, models.UpsertContributorByEmailParams{
+			Host:       host,
+			Email:      contributor.Email,
+			Name:       contributor.Name,
+			ProfileUrl: contributor.ProfileURL,
+		})
+	}
+
+	return queries.UpsertContributorByLogin(ctx, models.UpsertContributorByLoginParams{
+		Host:       host,
+		Login:      contributor.Login,
+		Email:      contributor.Email,
+		Name:       contributor.Name,
+		ProfileUrl: contributor.ProfileURL,
+	})
+}
+
+// TrackRepository adds the given repository to the tracked repositories.
+func (r *repo) TrackRepository(ctx context.Context, params TrackRepoRepositoryParams) (*types.TrackedRepository, error) {
+	result := &types.TrackedRepository{}
+
+	err := r.db.WithTx(ctx, func(queries *models.Queries) error {
+		repository, err := queries.UpsertRepository(ctx, models.UpsertRepositoryParams{
+			Host:  params.Host,
+			Owner: params.Owner,
+			Name:  params.Repo,
+		})
+		if err != nil {
+			return err
+		}
+
+		trackedRepository, err := queries.TrackRepository(ctx, models.TrackRepositoryParams{
+			RepositoryID: repository.ID,
+			Url:          params.URL,
+		})
+		if err != nil {
+			return err
+		}
+
+		result = &types.TrackedRepository{
+			ID:             int(trackedRepository.ID),
+			Host:           repository.Host,
+			Owner:          repository.Owner,
+			Repo:           repository.Name,
+			URL:            trackedRepository.Url,
+			TrackedAt:      trackedRepository.CreatedAt.UTC(),
+			LastSyncedAt:   timeFromNullTime(trackedRepository.LastSyncedAt),
+			LastSyncStatus: types.SyncStatus(trackedRepository.LastSyncStatus),
+			LastSyncError:  trackedRepository.LastSyncError,
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// UntrackRepository removes the given repository from the tracked repositories, false when it was not tracked.
+func (r *repo) UntrackRepository(ctx context.Context, host string, owner string, repo string) (bool, error) {
+	rows, err := r.db.Queries().UntrackRepository(ctx, models.UntrackRepositoryParams{
+		Host:  host,
+		Owner: owner,
+		Name:  repo,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return rows > 0, nil
+}
+
+// FindTrackedRepositories returns the tracked repositories, least recently tracked first.
+func (r *repo) FindTrackedRepositories(ctx context.Context) ([]*types.TrackedRepository, error) {
+	trackedRepositories, err := r.db.Queries().ListTrackedRepositories(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []*types.TrackedRepository{}
+
+	for _, trackedRepository := range trackedRepositories {
+		result = append(result, &types.TrackedRepository{
+			ID:             int(trackedRepository.ID),
+			Host:           trackedRepository.Host,
+			Owner:          trackedRepository.Owner,
+			Repo:           trackedRepository.Name,
+			URL:            trackedRepository.Url,
+			TrackedAt:      trackedRepository.CreatedAt.UTC(),
+			LastSyncedAt:   timeFromNullTime(trackedRepository.LastSyncedAt),
+			LastSyncStatus: types.SyncStatus(trackedRepository.LastSyncStatus),
+			LastSyncError:  trackedRepository.LastSyncError,
+		})
+	}
+
+	return result, nil
+}
+
+// UpdateTrackedRepositorySync records the outcome of the last sync of a tracked repository.
+func (r *repo) UpdateTrackedRepositorySync(ctx context.Context, trackedRepository *types.TrackedRepository) error {
+	return r.db.Queries().UpdateTrackedRepositorySync(ctx, models.UpdateTrackedRepositorySyncParams{
+		ID:             int32(trackedRepository.ID),
+		LastSyncedAt:   nullTimeFromTime(trackedRepository.LastSyncedAt),
+		LastSyncStatus: string(trackedRepository.LastSyncStatus),
+		LastSyncError:  trackedRepository.LastSyncError,
+	})
+}
+
+// pullRequestStateStrings returns the given states as strings, defaults to merged pull requests.
+func pullRequestStateStrings(states []types.PullRequestState) []string {
+	if len(states) == 0 {
+		return []string{string(types.MergedPullRequestState)}
+	}
+
+	result := []string{}
+	for _, state := range states {
+		result = append(result, string(state))
+	}
+
+	return result
+}
+
+func timeFromNullTime(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+
+	utc := t.Time.UTC()
+	return &utc
+}
+
+func nullTimeFromTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+
+	return sql.NullTime{Time: *t, Valid: true}
+}
+
+func NewRepo(db db.DB) *repo {
+	return &repo{db: db}
+}
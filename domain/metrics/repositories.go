@@ -0,0 +1,340 @@
+package metrics
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chris-ramon/golang-scaffolding/domain/metrics/github"
+	"github.com/chris-ramon/golang-scaffolding/domain/metrics/provider"
+	"github.com/chris-ramon/golang-scaffolding/domain/metrics/types"
+)
+
+// DefaultRepositoriesConcurrency is the default number of repositories whose pull requests are fetched at once.
+const DefaultRepositoriesConcurrency = 4
+
+type FindRepositoriesPullRequestsParams struct {
+	// RepositoryURLs are the URLs of the repositories to aggregate.
+	RepositoryURLs []string
+
+	// OrganizationURL is the URL of the organization, or user, whose repositories are aggregated along with
+	// the given ones.
+	OrganizationURL string
+
+	// States are the pull request states to find, defaults to merged pull requests.
+	States []types.PullRequestState
+
+	// Labels are the labels the pull requests must all have, any pull request when empty.
+	Labels []string
+
+	// Milestone is the milestone title of the pull requests, any pull request when empty.
+	Milestone string
+
+	// Paths are the glob patterns of the files the pull requests must change one of, any pull request when empty.
+	Paths []string
+
+	// Component is the name of the configured component whose paths the pull requests must change one of,
+	// along with the given paths.
+	Component string
+}
+
+type RepositoryPullRequests struct {
+	RepositoryURL string
+
+	PullRequests []*types.PullRequest
+
+	// Truncated reports whether pull requests were left out by the maximum number of fetched pages.
+	Truncated bool
+}
+
+type FindRepositoriesPullRequestsResult struct {
+	// Repositories are the pull requests of each repository, in the order of the repositories.
+	Repositories []RepositoryPullRequests
+
+	// PullRequests are the pull requests of every repository, most recently created first.
+	PullRequests []*types.PullRequest
+
+	// Truncated reports whether repositories or pull requests were left out by the maximum number of fetched pages.
+	Truncated bool
+}
+
+// FindRepositoriesPullRequests returns the pull requests of the given repositories and of the repositories of
+// the given organization, fetching a bounded number of repositories at once. Each repository goes through
+// `FindAllPullRequests`, so its pull requests are persisted and cached as if it was analyzed alone.
+func (s *service) FindRepositoriesPullRequests(ctx context.Context, params FindRepositoriesPullRequestsParams) (*FindRepositoriesPullRequestsResult, error) {
+	repositoryURLs, truncated, err := s.repositoryURLs(ctx, params.RepositoryURLs, params.OrganizationURL)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &FindRepositoriesPullRequestsResult{
+		Repositories: make([]RepositoryPullRequests, len(repositoryURLs)),
+		PullRequests: []*types.PullRequest{},
+		Truncated:    truncated,
+	}
+
+	concurrency := s.RepositoriesConcurrency
+	if concurrency <= 0 {
+		concurrency = DefaultRepositoriesConcurrency
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	var errOnce sync.Once
+	var firstErr error
+	sem := make(chan struct{}, concurrency)
+
+	for i, repositoryURL := range repositoryURLs {
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(i int, repositoryURL string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			r, err := s.FindAllPullRequests(ctx, FindAllPullRequestsParams{
+				RepositoryURL: repositoryURL,
+				States:        params.States,
+				Labels:        params.Labels,
+				Milestone:     params.Milestone,
+				Paths:         params.Paths,
+				Component:     params.Component,
+			})
+			if err != nil {
+				errOnce.Do(func() {
+					firstErr = fmt.Errorf("repository %s: %w", repositoryURL, err)
+					cancel()
+				})
+				return
+			}
+
+			result.Repositories[i] = RepositoryPullRequests{
+				RepositoryURL: repositoryURL,
+				PullRequests:  r.PullRequests,
+				Truncated:     r.Truncated,
+			}
+		}(i, repositoryURL)
+	}
+
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	for _, repository := range result.Repositories {
+		result.PullRequests = append(result.PullRequests, repository.PullRequests...)
+		result.Truncated = result.Truncated || repository.Truncated
+	}
+
+	slices.SortStableFunc(result.PullRequests, func(a, b *types.PullRequest) int {
+		return b.CreatedAt.Compare(*a.CreatedAt)
+	})
+
+	return result, nil
+}
+
+// repositoryURLs returns the canonical URLs of the given repositories followed by the ones of the given
+// organization, at most `MaxOrganizationRepositories` of them, without duplicates, and whether the repositories
+// of the organization were truncated.
+func (s *service) repositoryURLs(ctx context.Context, repositoryURLs []string, organizationURL string) ([]string, bool, error) {
+	result := []string{}
+	seen := map[string]bool{}
+	truncated := false
+
+	add := func(repositoryURL string) error {
+		ref, err := github.ParseRepoRef(repositoryURL)
+		if err != nil {
+			return err
+		}
+
+		key := strings.ToLower(ref.String())
+		if seen[key] {
+			return nil
+		}
+		seen[key] = true
+		result = append(result, ref.URL())
+
+		return nil
+	}
+
+	for _, repositoryURL := range repositoryURLs {
+		if err := add(repositoryURL); err != nil {
+			return nil, false, err
+		}
+	}
+
+	if organizationURL != "" {
+		ref, err := github.ParseOwnerRef(organizationURL)
+		if err != nil {
+			return nil, false, err
+		}
+
+		p, err := s.providerFor(ref.Host)
+		if err != nil {
+			return nil, false, err
+		}
+
+		repositoryProvider, ok := p.(provider.RepositoryProvider)
+		if !ok {
+			return nil, false, fmt.Errorf("organizations are not supported on host %q", ref.Host)
+		}
+
+		r, err := repositoryProvider.AllRepositories(ctx, provider.AllRepositoriesParams{
+			Host:  ref.Host,
+			Owner: ref.Owner,
+		})
+		if err != nil {
+			return nil, false, err
+		}
+
+		repositoryURLs := r.RepositoryURLs
+		truncated = r.Truncated
+		if s.MaxOrganizationRepositories > 0 && len(repositoryURLs) > s.MaxOrganizationRepositories {
+			repositoryURLs = repositoryURLs[:s.MaxOrganizationRepositories]
+			truncated = true
+		}
+
+		for _, repositoryURL := range repositoryURLs {
+			if err := add(repositoryURL); err != nil {
+				return nil, false, err
+			}
+		}
+	}
+
+	if len(result) == 0 {
+		return nil, false, fmt.Errorf("no repositories to analyze")
+	}
+
+	return result, truncated, nil
+}
+
+// generateRepositoriesPullRequestsGantt generates the Gantt DrawIO files of the pull requests of several
+// repositories, grouped by repository in the order of the repositories. Unlike the Gantt of a repository it is
+// not cached, since the repositories of an organization are only known once listed.
+func (s *service) generateRepositoriesPullRequestsGantt(ctx context.Context, params GeneratePullRequestsGanttParams) (*GeneratePullRequestsGanttResult, error) {
+	repositoryURLs := params.RepositoryURLs
+	if params.RepositoryURL != "" {
+		repositoryURLs = append([]string{params.RepositoryURL}, repositoryURLs...)
+	}
+
+	r, err := s.FindRepositoriesPullRequests(ctx, FindRepositoriesPullRequestsParams{
+		RepositoryURLs:  repositoryURLs,
+		OrganizationURL: params.OrganizationURL,
+		States:          params.States,
+		Labels:          params.Labels,
+		Milestone:       params.Milestone,
+		Paths:           params.Paths,
+		Component:       params.Component,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	pullRequests := []*types.PullRequest{}
+	hash := sha256.New()
+
+	for _, repository := range r.Repositories {
+		pullRequests = append(pullRequests, s.sortPullRequestsAsc(repository.PullRequests)...)
+		fmt.Fprintln(hash, strings.ToLower(repository.RepositoryURL))
+	}
+
+	dirName := fmt.Sprintf("repositories-%x", hash.Sum(nil)[:4])
+
+	// Decided once for the whole Gantt, so that every part draws its swimlanes even when it only has pull
+	// requests of a single repository.
+	swimlanes := len(r.Repositories) > 1
+
+	return s.writePullRequestsGantt(pullRequests, dirName, params.Limit, swimlanes)
+}
+
+type PullRequestsStatistics struct {
+	// RepositoryURL is the URL of the repository of the statistics, empty for the combined statistics.
+	RepositoryURL string
+
+	// Count is the number of pull requests.
+	Count int
+
+	// OpenCount is the number of open pull requests.
+	OpenCount int
+
+	// MergedCount is the number of merged pull requests.
+	MergedCount int
+
+	// ClosedCount is the number of pull requests closed without being merged.
+	ClosedCount int
+
+	// AverageCycleTime is the average time from creation to merge of the merged pull requests.
+	AverageCycleTime time.Duration
+
+	// MedianCycleTime is the median time from creation to merge of the merged pull requests.
+	MedianCycleTime time.Duration
+}
+
+type PullRequestsStatisticsResult struct {
+	// Combined are the statistics of the pull requests of every repository.
+	Combined PullRequestsStatistics
+
+	// Repositories are the statistics of each repository, in the order of the repositories.
+	Repositories []PullRequestsStatistics
+
+	// Truncated reports whether repositories or pull requests were left out by the maximum number of fetched pages.
+	Truncated bool
+}
+
+// PullRequestsStatistics returns the statistics of the pull requests of each of the given repositories and
+// of all of them combined.
+func (s *service) PullRequestsStatistics(ctx context.Context, params FindRepositoriesPullRequestsParams) (*PullRequestsStatisticsResult, error) {
+	r, err := s.FindRepositoriesPullRequests(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &PullRequestsStatisticsResult{
+		Combined:  pullRequestsStatistics("", r.PullRequests),
+		Truncated: r.Truncated,
+	}
+
+	for _, repository := range r.Repositories {
+		result.Repositories = append(result.Repositories, pullRequestsStatistics(repository.RepositoryURL, repository.PullRequests))
+	}
+
+	return result, nil
+}
+
+// pullRequestsStatistics returns the statistics of the given pull requests.
+func pullRequestsStatistics(repositoryURL string, pullRequests []*types.PullRequest) PullRequestsStatistics {
+	statistics := PullRequestsStatistics{
+		RepositoryURL: repositoryURL,
+		Count:         len(pullRequests),
+	}
+
+	cycleTimes := []time.Duration{}
+	var total time.Duration
+
+	for _, pr := range pullRequests {
+		switch pr.State {
+		case types.OpenPullRequestState:
+			statistics.OpenCount++
+		case types.ClosedPullRequestState:
+			statistics.ClosedCount++
+		case types.MergedPullRequestState:
+			statistics.MergedCount++
+			cycleTimes = append(cycleTimes, pr.Duration)
+			total += pr.Duration
+		}
+	}
+
+	if len(cycleTimes) > 0 {
+		statistics.AverageCycleTime = total / time.Duration(len(cycleTimes))
+		statistics.MedianCycleTime = durationPercentile(cycleTimes, 50)
+	}
+
+	return statistics
+}
@@ -0,0 +1,256 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+
+	cachePkg "github.com/chris-ramon/golang-scaffolding/cache"
+	"github.com/chris-ramon/golang-scaffolding/domain/metrics/github"
+	"github.com/chris-ramon/golang-scaffolding/domain/metrics/types"
+)
+
+func newRepositoriesMockGitHub(now time.Time, running *atomic.Int32, maxRunning *atomic.Int32) *mockGitHub {
+	day := 24 * time.Hour
+
+	return &mockGitHub{
+		allRepositories: func(params github.AllRepositoriesParams) (github.AllRepositoriesQuery, error) {
+			query := github.AllRepositoriesQuery{}
+			query.RepositoryOwner.Repositories.Nodes = github.RepositoriesNodes{
+				{Name: "api", URL: "https://github.com/org/api"},
+				{Name: "archive", URL: "https://github.com/org/archive", IsArchived: true},
+				{Name: "infra", URL: "https://github.com/org/infra"},
+				{Name: "web", URL: "https://github.com/org/web"},
+			}
+			return query, nil
+		},
+		allPullRequests: func(params github.AllPullRequestsParams) (github.AllPullRequestsQuery, error) {
+			if n := running.Add(1); n > maxRunning.Load() {
+				maxRunning.Store(n)
+			}
+			time.Sleep(10 * time.Millisecond)
+			defer running.Add(-1)
+
+			nodes := github.AllPullRequestsNodes{}
+			switch params.Repo {
+			case "api":
+				nodes = append(nodes, github.AllPullRequestsNode{
+					Number:    githubv4.Int(1),
+					CreatedAt: githubv4.DateTime{Time: now.Add(-3 * day)},
+					MergedAt:  githubv4.DateTime{Time: now.Add(-1 * day)},
+				})
+			case "infra":
+				nodes = append(nodes, github.AllPullRequestsNode{
+					Number:    githubv4.Int(2),
+					CreatedAt: githubv4.DateTime{Time: now.Add(-2 * day)},
+					MergedAt:  githubv4.DateTime{Time: now},
+				}, github.AllPullRequestsNode{
+					Number:    githubv4.Int(3),
+					CreatedAt: githubv4.DateTime{Time: now.Add(-5 * day)},
+					MergedAt:  githubv4.DateTime{Time: now},
+				})
+			case "web":
+				nodes = append(nodes, github.AllPullRequestsNode{
+					Number:    githubv4.Int(4),
+					CreatedAt: githubv4.DateTime{Time: now.Add(-1 * day)},
+					State:     githubv4.PullRequestStateOpen,
+				})
+			}
+
+			return github.AllPullRequestsQuery{
+				Repository: github.AllPullRequestsRepository{
+					PullRequests: github.AllPullRequestsPullRequests{Nodes: nodes},
+				},
+			}, nil
+		},
+	}
+}
+
+func TestFindRepositoriesPullRequests(t *testing.T) {
+	var running, maxRunning atomic.Int32
+
+	srv := &service{
+		cache:                   cachePkg.New(),
+		GitHub:                  newRepositoriesMockGitHub(time.Now(), &running, &maxRunning),
+		RepositoriesConcurrency: 2,
+	}
+
+	result, err := srv.FindRepositoriesPullRequests(context.Background(), FindRepositoriesPullRequestsParams{
+		RepositoryURLs:  []string{"https://github.com/org/web", "org/api"},
+		OrganizationURL: "https://github.com/org",
+		States:          []types.PullRequestState{types.MergedPullRequestState, types.OpenPullRequestState},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	repositoryURLs := []string{}
+	for _, repository := range result.Repositories {
+		repositoryURLs = append(repositoryURLs, repository.RepositoryURL)
+	}
+	expectedURLs := "https://github.com/org/web,https://github.com/org/api,https://github.com/org/infra"
+	if strings.Join(repositoryURLs, ",") != expectedURLs {
+		t.Errorf("expected repositories %s without duplicates nor archived ones, got %v", expectedURLs, repositoryURLs)
+	}
+
+	numbers := []int{}
+	for _, pr := range result.PullRequests {
+		numbers = append(numbers, pr.Number)
+	}
+	if len(numbers) != 4 || numbers[0] != 4 || numbers[1] != 2 || numbers[2] != 1 || numbers[3] != 3 {
+		t.Errorf("expected the pull requests of every repository newest first, got %v", numbers)
+	}
+
+	if maxRunning.Load() > 2 {
+		t.Errorf("expected at most 2 repositories fetched at once, got %d", maxRunning.Load())
+	}
+
+	if result.Truncated {
+		t.Errorf("expected no repository left out")
+	}
+}
+
+func TestFindRepositoriesPullRequestsMaxOrganizationRepositories(t *testing.T) {
+	var running, maxRunning atomic.Int32
+
+	srv := &service{
+		cache:                       cachePkg.New(),
+		GitHub:                      newRepositoriesMockGitHub(time.Now(), &running, &maxRunning),
+		MaxOrganizationRepositories: 1,
+	}
+
+	result, err := srv.FindRepositoriesPullRequests(context.Background(), FindRepositoriesPullRequestsParams{
+		RepositoryURLs:  []string{"https://github.com/org/web"},
+		OrganizationURL: "https://github.com/org",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	repositoryURLs := []string{}
+	for _, repository := range result.Repositories {
+		repositoryURLs = append(repositoryURLs, repository.RepositoryURL)
+	}
+	expectedURLs := "https://github.com/org/web,https://github.com/org/api"
+	if strings.Join(repositoryURLs, ",") != expectedURLs {
+		t.Errorf("expected the given repositories and the first repository of the organization %s, got %v", expectedURLs, repositoryURLs)
+	}
+
+	if !result.Truncated {
+		t.Errorf("expected the repositories of the organization past the maximum to be reported as truncated")
+	}
+}
+
+func TestFindRepositoriesPullRequestsError(t *testing.T) {
+	mockGH := &mockGitHub{
+		allPullRequests: func(params github.AllPullRequestsParams) (github.AllPullRequestsQuery, error) {
+			if params.Repo == "broken" {
+				return github.AllPullRequestsQuery{}, errors.New("internal error")
+			}
+			return github.AllPullRequestsQuery{}, nil
+		},
+	}
+
+	srv := &service{
+		cache:  cachePkg.New(),
+		GitHub: mockGH,
+	}
+
+	_, err := srv.FindRepositoriesPullRequests(context.Background(), FindRepositoriesPullRequestsParams{
+		RepositoryURLs: []string{"https://github.com/org/api", "https://github.com/org/broken"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "https://github.com/org/broken") {
+		t.Errorf("expected error of the broken repository, got %v", err)
+	}
+
+	_, err = srv.FindRepositoriesPullRequests(context.Background(), FindRepositoriesPullRequestsParams{})
+	if err == nil {
+		t.Errorf("expected error without repositories")
+	}
+}
+
+func TestPullRequestsStatistics(t *testing.T) {
+	var running, maxRunning atomic.Int32
+	day := 24 * time.Hour
+
+	srv := &service{
+		cache:  cachePkg.New(),
+		GitHub: newRepositoriesMockGitHub(time.Now(), &running, &maxRunning),
+	}
+
+	result, err := srv.PullRequestsStatistics(context.Background(), FindRepositoriesPullRequestsParams{
+		OrganizationURL: "github.com/org",
+		States:          []types.PullRequestState{types.MergedPullRequestState, types.OpenPullRequestState},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.Repositories) != 3 {
+		t.Fatalf("expected statistics of 3 repositories, got %d", len(result.Repositories))
+	}
+
+	infra := result.Repositories[1]
+	if infra.RepositoryURL != "https://github.com/org/infra" || infra.Count != 2 || infra.MergedCount != 2 {
+		t.Errorf("unexpected infra statistics: %+v", infra)
+	}
+	if infra.AverageCycleTime.Round(time.Hour) != 3*day+12*time.Hour || infra.MedianCycleTime.Round(time.Hour) != 2*day {
+		t.Errorf("unexpected infra cycle times: average %v, median %v", infra.AverageCycleTime, infra.MedianCycleTime)
+	}
+
+	combined := result.Combined
+	if combined.RepositoryURL != "" || combined.Count != 4 || combined.MergedCount != 3 || combined.OpenCount != 1 {
+		t.Errorf("unexpected combined statistics: %+v", combined)
+	}
+	if combined.MedianCycleTime.Round(time.Hour) != 2*day {
+		t.Errorf("expected combined median cycle time of 2 days, got %v", combined.MedianCycleTime)
+	}
+}
+
+func TestGenerateGanttDrawIOSwimlanes(t *testing.T) {
+	createdAt := time.Now().Add(-48 * time.Hour)
+	mergedAt := time.Now()
+	srv := &service{}
+
+	pullRequests := []*types.PullRequest{
+		{Number: 1, Owner: "org", Repo: "api", Title: "API change", CreatedAt: &createdAt, MergedAt: &mergedAt, State: types.MergedPullRequestState},
+		{Number: 2, Owner: "org", Repo: "api", Title: "API fix", CreatedAt: &createdAt, MergedAt: &mergedAt, State: types.MergedPullRequestState},
+		{Number: 1, Owner: "org", Repo: "web", Title: "Web change", CreatedAt: &createdAt, MergedAt: &mergedAt, State: types.MergedPullRequestState},
+	}
+
+	drawioContent, err := srv.generateGanttDrawIOFromPullRequests(pullRequests, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	content := string(drawioContent)
+	for _, lane := range []string{`value="org/api"`, `value="org/web"`} {
+		if strings.Count(content, lane) != 1 {
+			t.Errorf("expected a single %s swimlane header", lane)
+		}
+	}
+
+	// A part of a multi-repository Gantt keeps its swimlane even with the pull requests of a single repository.
+	part, err := srv.generateGanttDrawIOFromPullRequests(pullRequests[:2], true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if strings.Count(string(part), `value="org/api"`) != 1 {
+		t.Errorf("expected a swimlane header for the part of a single repository")
+	}
+
+	single, err := srv.generateGanttDrawIOFromPullRequests(pullRequests[:2], false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if strings.Contains(string(single), `value="org/api"`) {
+		t.Errorf("expected no swimlane header without swimlanes")
+	}
+}
@@ -0,0 +1,167 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chris-ramon/golang-scaffolding/domain/metrics/github"
+	"github.com/chris-ramon/golang-scaffolding/domain/metrics/provider"
+)
+
+// DefaultConcurrency is the default number of requests fetching the details of the listed resources at once.
+const DefaultConcurrency = 4
+
+// Client represents a client of the JSON REST API of a code hosting service, like GitLab or Gitea.
+type Client struct {
+	// HTTPClient is the HTTP client of the requests, `http.DefaultClient` when nil.
+	HTTPClient *http.Client
+
+	// APIURL is the REST API endpoint, e.g. `https://gitlab.com/api/v4`.
+	APIURL string
+
+	// Service is the name of the code hosting service in the errors, e.g. `GitLab`.
+	Service string
+
+	// TokenHeader is the header the token is sent in, e.g. `Authorization`.
+	TokenHeader string
+
+	// TokenPrefix is the prefix of the token in its header, e.g. `token `.
+	TokenPrefix string
+
+	// Token is the access token the requests are authenticated with, anonymous when empty.
+	Token string
+}
+
+// linkNextRegexp matches the `next` link of a `Link` response header.
+var linkNextRegexp = regexp.MustCompile(`<([^>]+)>\s*;\s*rel="?next"?`)
+
+// Get decodes the JSON response of the given API path into v and returns the response header. Missing
+// resources are reported as `provider.ErrPullRequestNotFound` and rate limited requests as a
+// `github.RateLimitError`.
+func (c *Client) Get(ctx context.Context, path string, v any) (http.Header, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(c.APIURL, "/")+"/"+path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	if c.Token != "" {
+		req.Header.Set(c.TokenHeader, c.TokenPrefix+c.Token)
+	}
+
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, provider.ErrPullRequestNotFound
+	case resp.StatusCode == http.StatusTooManyRequests:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, &github.RateLimitError{StatusCode: resp.StatusCode, Body: string(body)}
+	case resp.StatusCode != http.StatusOK:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("%s API request failed with status %d: %s", c.Service, resp.StatusCode, body)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return nil, err
+	}
+
+	return resp.Header, nil
+}
+
+// NextPage returns the number of the page following the given one from the pagination headers of its response:
+// the `next` link of the `Link` header, the GitLab `X-Next-Page` header, or the Gitea `X-Total-Count` header
+// along with the number of items fetched so far. It returns zero after the last page.
+func NextPage(header http.Header, page int, fetched int) int {
+	if m := linkNextRegexp.FindStringSubmatch(header.Get("Link")); m != nil {
+		if u, err := url.Parse(m[1]); err == nil {
+			if next, err := strconv.Atoi(u.Query().Get("page")); err == nil && next > page {
+				return next
+			}
+		}
+	}
+
+	if next, err := strconv.Atoi(header.Get("X-Next-Page")); err == nil && next > page {
+		return next
+	}
+
+	if total, err := strconv.Atoi(header.Get("X-Total-Count")); err == nil && fetched < total {
+		return page + 1
+	}
+
+	return 0
+}
+
+// Each calls f with each index below n, at most the given number of calls at once, and returns the first error.
+// The context of the other calls is cancelled on the first error.
+func Each(ctx context.Context, n int, concurrency int, f func(ctx context.Context, i int) error) error {
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	var errOnce sync.Once
+	var firstErr error
+	sem := make(chan struct{}, concurrency)
+
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if err := f(ctx, i); err != nil {
+				errOnce.Do(func() {
+					firstErr = err
+					cancel()
+				})
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	return firstErr
+}
+
+// UTC returns the given time in UTC, nil when nil.
+func UTC(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+
+	u := t.UTC()
+	return &u
+}
+
+// NewHTTPClient returns an HTTP client of the given timeout, throttling and retrying the rate limited and failed
+// requests like the GitHub ones.
+func NewHTTPClient(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: github.NewRateLimiter().Transport(nil),
+	}
+}
@@ -0,0 +1,42 @@
+package rest
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestNextPage(t *testing.T) {
+	testCases := []struct {
+		name     string
+		header   http.Header
+		page     int
+		fetched  int
+		expected int
+	}{
+		{
+			name:     "link header",
+			header:   http.Header{"Link": {`<https://gitea.example.com/api/v1/repos/o/r/pulls?limit=10&page=3>; rel="next", <https://gitea.example.com/api/v1/repos/o/r/pulls?limit=10&page=9>; rel="last"`}},
+			page:     2,
+			expected: 3,
+		},
+		{
+			name:     "last page link header",
+			header:   http.Header{"Link": {`<https://gitea.example.com/api/v1/repos/o/r/pulls?limit=10&page=1>; rel="first"`}},
+			page:     9,
+			expected: 0,
+		},
+		{name: "gitlab next page header", header: http.Header{"X-Next-Page": {"2"}}, page: 1, expected: 2},
+		{name: "gitlab last page", header: http.Header{"X-Next-Page": {""}}, page: 2, expected: 0},
+		{name: "total count", header: http.Header{"X-Total-Count": {"25"}}, page: 1, fetched: 10, expected: 2},
+		{name: "total count fetched", header: http.Header{"X-Total-Count": {"25"}}, page: 3, fetched: 25, expected: 0},
+		{name: "no pagination headers", header: http.Header{}, page: 1, fetched: 50, expected: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := NextPage(tc.header, tc.page, tc.fetched); got != tc.expected {
+				t.Errorf("expected next page %d, got %d", tc.expected, got)
+			}
+		})
+	}
+}
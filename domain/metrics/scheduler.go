@@ -0,0 +1,45 @@
+package metrics
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// TrackedRepositoriesSyncer syncs the tracked repositories.
+type TrackedRepositoriesSyncer interface {
+	SyncTrackedRepositories(ctx context.Context) error
+}
+
+// Scheduler syncs the tracked repositories in the background on a fixed interval.
+type Scheduler struct {
+	syncer TrackedRepositoriesSyncer
+
+	// Interval is the time between two syncs.
+	Interval time.Duration
+}
+
+// Run syncs the tracked repositories right away and then on every interval until the given context is done.
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.syncer.SyncTrackedRepositories(ctx); err != nil {
+			log.Printf("failed to sync tracked repositories: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func NewScheduler(syncer TrackedRepositoriesSyncer, interval time.Duration) *Scheduler {
+	return &Scheduler{
+		syncer:   syncer,
+		Interval: interval,
+	}
+}
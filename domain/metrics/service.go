@@ -6,18 +6,25 @@ import (
 	"encoding/xml"
 	"errors"
 	"fmt"
-	"net/http"
 	"os"
 	"path/filepath"
 	"runtime"
 	"slices"
 	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
-	githubClient "github.com/google/go-github/github"
 	"github.com/google/uuid"
 
 	cachePkg "github.com/chris-ramon/golang-scaffolding/cache"
+	"github.com/chris-ramon/golang-scaffolding/config"
+	"github.com/chris-ramon/golang-scaffolding/domain/metrics/gitea"
 	"github.com/chris-ramon/golang-scaffolding/domain/metrics/github"
+	"github.com/chris-ramon/golang-scaffolding/domain/metrics/gitlab"
+	"github.com/chris-ramon/golang-scaffolding/domain/metrics/gitlog"
+	"github.com/chris-ramon/golang-scaffolding/domain/metrics/provider"
 	"github.com/chris-ramon/golang-scaffolding/domain/metrics/types"
 	"github.com/chris-ramon/golang-scaffolding/drawio/gantt"
 	"github.com/chris-ramon/golang-scaffolding/pkg/markdown"
@@ -27,27 +34,85 @@ type service struct {
 	// cache is the internal cache component.
 	cache *cachePkg.Cache
 
-	// HTTPClient is the HTTP client used for GitHub API requests.
-	HTTPClient *http.Client
-
-	// GitHub is the github component.
+	// GitHub is the github component of github.com.
 	GitHub github.GitHubClient
+
+	// GitHubHosts are the github components of the GitHub Enterprise Server hosts.
+	GitHubHosts map[string]github.GitHubClient
+
+	// Providers are the providers of the hosts of other code hosting services, like GitLab or Gitea, and of
+	// the local repositories.
+	Providers map[string]provider.Provider
+
+	// repo persists the fetched pull requests, persistence is disabled when nil.
+	repo Repo
+
+	// RepoTTL is the time persisted pull requests are served before fetching them from GitHub again.
+	RepoTTL time.Duration
+
+	// PullRequestsBatchSize is the number of pull requests fetched per GitHub request.
+	PullRequestsBatchSize int
+
+	// PullRequestsConcurrency is the number of GitHub requests fetching pull requests at once.
+	PullRequestsConcurrency int
+
+	// RepositoriesConcurrency is the number of repositories whose pull requests are fetched at once.
+	RepositoriesConcurrency int
+
+	// cacheGenerations are the cache generations of the repositories, bumped to invalidate their cached results.
+	cacheGenerations sync.Map
+
+	// Components are the glob patterns of the paths of the named components of monorepos.
+	Components map[string][]string
+
+	// MaxOrganizationRepositories is the maximum number of repositories of an organization analyzed at once, zero
+	// means every repository.
+	MaxOrganizationRepositories int
 }
 
+// DefaultRepoTTL is the default time persisted pull requests are served before fetching them from GitHub again.
+const DefaultRepoTTL = 15 * time.Minute
+
+// DefaultPullRequestsBatchSize is the default number of pull requests fetched per GitHub request.
+const DefaultPullRequestsBatchSize = 25
+
+// DefaultPullRequestsConcurrency is the default number of GitHub requests fetching pull requests at once.
+const DefaultPullRequestsConcurrency = 4
+
+// DefaultGanttLimit is the default maximum number of pull requests of each Gantt part.
+const DefaultGanttLimit = 25
+
 type FindPullRequestsResult struct {
+	// PullRequests are the pull requests in the order of the parameters, the ones that could not be
+	// fetched carry their error.
 	PullRequests []*types.PullRequest
 }
 
-type findPullRequestsResult struct {
-	PullRequest *types.PullRequest
-}
-
 type FindAllPullRequestsResult struct {
 	PullRequests []*types.PullRequest
+
+	// Truncated reports whether pull requests were left out by the maximum number of fetched pages.
+	Truncated bool
 }
 
 type FindAllPullRequestsParams struct {
 	RepositoryURL string
+
+	// States are the pull request states to find, defaults to merged pull requests.
+	States []types.PullRequestState
+
+	// Labels are the labels the pull requests must all have, any pull request when empty.
+	Labels []string
+
+	// Milestone is the milestone title of the pull requests, any pull request when empty.
+	Milestone string
+
+	// Paths are the glob patterns of the files the pull requests must change one of, any pull request when empty.
+	Paths []string
+
+	// Component is the name of the configured component whose paths the pull requests must change one of,
+	// along with the given paths.
+	Component string
 }
 
 type GeneratePullRequestsGanttPart struct {
@@ -63,11 +128,48 @@ type GeneratePullRequestsGanttResult struct {
 type GeneratePullRequestsGanttParams struct {
 	RepositoryURL string
 	Limit         int
+
+	// RepositoryURLs are the URLs of the repositories whose pull requests are drawn in a swimlane each, instead
+	// of the repository URL.
+	RepositoryURLs []string
+
+	// OrganizationURL is the URL of the organization, or user, whose repositories are drawn in a swimlane each
+	// along with the repository URLs.
+	OrganizationURL string
+
+	// States are the pull request states to include, defaults to merged pull requests.
+	States []types.PullRequestState
+
+	// Labels are the labels the pull requests must all have, any pull request when empty.
+	Labels []string
+
+	// Milestone is the milestone title of the pull requests, any pull request when empty.
+	Milestone string
+
+	// Paths are the glob patterns of the files the pull requests must change one of, any pull request when empty.
+	Paths []string
+
+	// Component is the name of the configured component whose paths the pull requests must change one of,
+	// along with the given paths.
+	Component string
 }
 
 // `findPullRequestsCacheKey` returns cache key of `FindPullRequests`.
 func (s *service) findPullRequestsCacheKey(params types.FindPullRequestsParams) (string, error) {
-	key, err := json.Marshal(params)
+	// The generations of the repositories of the pull requests, so that invalidating one of them misses the cache.
+	generations := make([]uint64, len(params))
+	for i, param := range params {
+		host := param.Host
+		if host == "" {
+			host = github.DefaultHost
+		}
+		generations[i] = s.cacheGeneration(host, param.Owner, param.Repo)
+	}
+
+	key, err := json.Marshal(struct {
+		Params      types.FindPullRequestsParams
+		Generations []uint64
+	}{params, generations})
 	if err != nil {
 		return "", err
 	}
@@ -90,6 +192,9 @@ func (s *service) cacheFindPullRequestsValue(key string, data any) {
 	s.cache.Add(key, data)
 }
 
+// FindPullRequests fetches the given pull requests from GitHub in batches of aliased GraphQL fields, running
+// a bounded number of batches at once. The result is in the order of the given pull requests and reports the
+// pull requests that could not be found instead of failing them all, only complete results are cached.
 func (s *service) FindPullRequests(ctx context.Context, params types.FindPullRequestsParams) (*FindPullRequestsResult, error) {
 	key, err := s.findPullRequestsCacheKey(params)
 	if err != nil {
@@ -101,106 +206,204 @@ func (s *service) FindPullRequests(ctx context.Context, params types.FindPullReq
 		return s.getFindPullRequestsCacheValue(findPullRequestsCacheVal)
 	}
 
-	result := &FindPullRequestsResult{}
+	result := &FindPullRequestsResult{
+		PullRequests: make([]*types.PullRequest, len(params)),
+	}
 
-	for _, pr := range params {
-		r, err := s.findPullRequests(ctx, pr)
-		if err != nil {
-			return nil, err
-		}
+	concurrency := s.PullRequestsConcurrency
+	if concurrency <= 0 {
+		concurrency = DefaultPullRequestsConcurrency
+	}
 
-		result.PullRequests = append(result.PullRequests, r.PullRequest)
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+
+	for _, batch := range pullRequestsBatches(params, s.PullRequestsBatchSize) {
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(batch []int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			s.findPullRequestsBatch(ctx, params, batch, result)
+		}(batch)
 	}
 
-	s.cacheFindPullRequestsValue(key, result)
+	wg.Wait()
+
+	if !slices.ContainsFunc(result.PullRequests, func(pr *types.PullRequest) bool { return pr.Error != nil }) {
+		s.cacheFindPullRequestsValue(key, result)
+	}
 
 	return result, nil
 }
 
-func (s *service) findPullRequests(ctx context.Context, param types.FindPullRequestParam) (*findPullRequestsResult, error) {
-	// Create a GitHub client using the provided HTTP client.
-	client := githubClient.NewClient(s.HTTPClient)
-
-	// Fetch pull request information from GitHub.
-	pullRequest, _, err := client.PullRequests.Get(ctx, param.Owner, param.Repo, param.Number)
-	if err != nil {
-		return nil, err
+// pullRequestsBatches returns the indexes of the given pull requests split in batches of at most the given
+// size. The pull requests of a batch share the host and owner, since a GitHub App authenticates a request as
+// the installation of a single owner.
+func pullRequestsBatches(params types.FindPullRequestsParams, size int) [][]int {
+	if size <= 0 {
+		size = DefaultPullRequestsBatchSize
 	}
 
-	if pullRequest.MergedAt == nil {
-		return nil, fmt.Errorf("unexpected merged at nil value")
+	groups := [][]int{}
+	groupByOwner := map[string]int{}
+
+	for i, param := range params {
+		owner := strings.ToLower(param.Host + "/" + param.Owner)
+
+		group, ok := groupByOwner[owner]
+		if !ok {
+			group = len(groups)
+			groupByOwner[owner] = group
+			groups = append(groups, []int{})
+		}
+
+		groups[group] = append(groups[group], i)
 	}
 
-	if pullRequest.CreatedAt == nil {
-		return nil, fmt.Errorf("unexpected created at nil value")
+	batches := [][]int{}
+	for _, group := range groups {
+		for len(group) > size {
+			batches = append(batches, group[:size])
+			group = group[size:]
+		}
+		batches = append(batches, group)
 	}
 
-	if pullRequest.Head == nil {
-		return nil, fmt.Errorf("unexpected head nil value")
+	return batches
+}
+
+// findPullRequestsBatch fetches the pull requests of the given indexes and sets them, or their error, at the
+// same indexes of the result.
+func (s *service) findPullRequestsBatch(ctx context.Context, params types.FindPullRequestsParams, batch []int, result *FindPullRequestsResult) {
+	setErr := func(i int, code types.PullRequestErrorCode, err error) {
+		param := params[i]
+		result.PullRequests[i] = &types.PullRequest{
+			Number: param.Number,
+			Host:   param.Host,
+			Owner:  param.Owner,
+			Repo:   param.Repo,
+			URL:    param.URL,
+			Error:  &types.PullRequestError{Code: code, Message: err.Error()},
+		}
 	}
 
-	if pullRequest.Head.Ref == nil {
-		return nil, fmt.Errorf("unexpected head ref nil value")
+	p, err := s.providerFor(params[batch[0]].Host)
+	if err != nil {
+		for _, i := range batch {
+			setErr(i, types.InvalidURLPullRequestErrorCode, err)
+		}
+		return
 	}
 
-	pullRequestContributorsParams := github.PullRequestContributorsParams{
-		PullRequest: types.PullRequest{
-			Owner:       param.Owner,
-			Repo:        param.Repo,
-			HeadRefName: *pullRequest.Head.Ref,
-		},
+	pullRequestsParams := provider.PullRequestsParams{Host: params[batch[0]].Host}
+	for _, i := range batch {
+		pullRequestsParams.PullRequests = append(pullRequestsParams.PullRequests, provider.PullRequestParams{
+			Owner:  params[i].Owner,
+			Repo:   params[i].Repo,
+			Number: params[i].Number,
+		})
 	}
-	r, err := s.GitHub.PullRequestContributors(pullRequestContributorsParams)
+
+	// Fetch pull requests information from the provider.
+	r, err := p.PullRequests(ctx, pullRequestsParams)
 	if err != nil {
-		return nil, err
+		for _, i := range batch {
+			setErr(i, pullRequestErrorCode(err), err)
+		}
+		return
 	}
 
-	contributors := types.Contributors{}
+	if len(r) != len(batch) {
+		err := fmt.Errorf("expected %d pull requests, got %d", len(batch), len(r))
+		for _, i := range batch {
+			setErr(i, types.InternalPullRequestErrorCode, err)
+		}
+		return
+	}
 
-	for _, prNode := range r.Repository.PullRequests.Nodes {
-		for _, participant := range prNode.Participants.Nodes {
-			c := types.Contributor{
-				ProfileURL: string(participant.URL),
-				ID:         string(participant.ID),
-				Login:      string(participant.Login),
-			}
-			contributors = append(contributors, c)
+	for j, i := range batch {
+		param := params[i]
+		pr := r[j].PullRequest
+
+		switch {
+		case r[j].Err != nil:
+			setErr(i, pullRequestErrorCode(r[j].Err), r[j].Err)
+		case pr.MergedAt == nil:
+			setErr(i, types.NotMergedPullRequestErrorCode, fmt.Errorf("pull request is not merged"))
+		default:
+			pr.URL = param.URL
+			pr.FormattedContributors = pr.Contributors.FormattedContributors(types.DefaultFormatContributorType)
+			result.PullRequests[i] = pr
 		}
 	}
+}
 
-	// Extract pull request metrics.
-	duration := pullRequest.MergedAt.UTC().Sub(pullRequest.CreatedAt.UTC())
+// pullRequestErrorCode returns the code of the given error fetching a pull request.
+func pullRequestErrorCode(err error) types.PullRequestErrorCode {
+	switch {
+	case github.IsRateLimitError(err):
+		return types.RateLimitedPullRequestErrorCode
+	case errors.Is(err, provider.ErrPullRequestNotFound):
+		return types.NotFoundPullRequestErrorCode
+	default:
+		return types.InternalPullRequestErrorCode
+	}
+}
 
-	var title, body string
-	if pullRequest.Title != nil {
-		title = *pullRequest.Title
+// repositoryCacheGeneration returns the cache generation of the repository of the given URL.
+func (s *service) repositoryCacheGeneration(repositoryURL string) uint64 {
+	ref, err := github.ParseRepoRef(repositoryURL)
+	if err != nil {
+		return 0
 	}
-	if pullRequest.Body != nil {
-		body = *pullRequest.Body
+
+	return s.cacheGeneration(ref.Host, ref.Owner, ref.Repo)
+}
+
+// cacheGeneration returns the cache generation of the given repository.
+func (s *service) cacheGeneration(host string, owner string, repo string) uint64 {
+	generation, ok := s.cacheGenerations.Load(repositoryCacheGenerationKey(host, owner, repo))
+	if !ok {
+		return 0
 	}
 
-	pr := &types.PullRequest{
-		Duration:              duration,
-		CreatedAt:             pullRequest.CreatedAt,
-		MergedAt:              pullRequest.MergedAt,
-		URL:                   param.URL,
-		Title:                 title,
-		Body:                  body,
-		Contributors:          contributors,
-		FormattedContributors: contributors.FormattedContributors(types.DefaultFormatContributorType),
+	return generation.(*atomic.Uint64).Load()
+}
+
+// invalidateRepositoryCache invalidates the cached results of the given repository, which are left
+// to be evicted by the cache.
+func (s *service) invalidateRepositoryCache(host string, owner string, repo string) {
+	generation, _ := s.cacheGenerations.LoadOrStore(repositoryCacheGenerationKey(host, owner, repo), &atomic.Uint64{})
+	generation.(*atomic.Uint64).Add(1)
+}
+
+func repositoryCacheGenerationKey(host string, owner string, repo string) string {
+	return strings.ToLower(fmt.Sprintf("%s/%s/%s", host, owner, repo))
+}
+
+// gitHubFor returns the github component of the given host, github.com when empty.
+func (s *service) gitHubFor(host string) (github.GitHubClient, error) {
+	if host == "" || strings.EqualFold(host, github.DefaultHost) {
+		return s.GitHub, nil
 	}
 
-	// Create the result.
-	result := &findPullRequestsResult{
-		PullRequest: pr,
+	gh, ok := s.GitHubHosts[strings.ToLower(host)]
+	if !ok {
+		return nil, fmt.Errorf("host %q is not configured", host)
 	}
 
-	return result, nil
+	return gh, nil
 }
 
 // `findAllPullRequestsCacheKey` returns cache key of `FindAllPullRequests`.
 func (s *service) findAllPullRequestsCacheKey(params FindAllPullRequestsParams) (string, error) {
-	key, err := json.Marshal(params)
+	key, err := json.Marshal(struct {
+		Params     FindAllPullRequestsParams
+		Generation uint64
+	}{params, s.repositoryCacheGeneration(params.RepositoryURL)})
 	if err != nil {
 		return "", err
 	}
@@ -223,7 +426,24 @@ func (s *service) cacheFindAllPullRequestsValue(key string, data any) {
 	s.cache.Add(key, data)
 }
 
+// FindAllPullRequests returns the pull requests of the given states of a repository narrowed down by the given
+// labels, milestone, paths and component. The pull requests are fetched, persisted and cached regardless of the
+// filters, the filtered results are cached apart.
 func (s *service) FindAllPullRequests(ctx context.Context, params FindAllPullRequestsParams) (*FindAllPullRequestsResult, error) {
+	filter, err := s.pullRequestsFilter(params)
+	if err != nil {
+		return nil, err
+	}
+
+	unfilteredParams := FindAllPullRequestsParams{
+		RepositoryURL: params.RepositoryURL,
+		States:        params.States,
+	}
+
+	if filter.empty() {
+		return s.findAllPullRequests(ctx, unfilteredParams)
+	}
+
 	key, err := s.findAllPullRequestsCacheKey(params)
 	if err != nil {
 		return nil, err
@@ -234,73 +454,225 @@ func (s *service) FindAllPullRequests(ctx context.Context, params FindAllPullReq
 		return s.getFindAllPullRequestsCacheValue(findAllPullRequestsCacheVal)
 	}
 
-	owner, repo, err := github.RepositoryFromURL(params.RepositoryURL)
+	result, err := s.findAllPullRequests(ctx, unfilteredParams)
 	if err != nil {
 		return nil, err
 	}
 
-	allPullRequestsParams := github.AllPullRequestsParams{
-		Owner: owner,
-		Repo:  repo,
+	filtered := filter.apply(result)
+
+	s.cacheFindAllPullRequestsValue(key, filtered)
+
+	return filtered, nil
+}
+
+// findAllPullRequests returns the pull requests of the given states of a repository, from the cache, the
+// persisted pull requests or the repository provider.
+func (s *service) findAllPullRequests(ctx context.Context, params FindAllPullRequestsParams) (*FindAllPullRequestsResult, error) {
+	key, err := s.findAllPullRequestsCacheKey(params)
+	if err != nil {
+		return nil, err
 	}
 
-	r, err := s.GitHub.AllPullRequests(allPullRequestsParams)
+	findAllPullRequestsCacheVal, found := s.cache.Get(key)
+	if found {
+		return s.getFindAllPullRequestsCacheValue(findAllPullRequestsCacheVal)
+	}
+
+	ref, err := github.ParseRepoRef(params.RepositoryURL)
 	if err != nil {
 		return nil, err
 	}
 
-	result := &FindAllPullRequestsResult{}
+	p, err := s.providerFor(ref.Host)
+	if err != nil {
+		return nil, err
+	}
 
-	for _, prNode := range r.Repository.PullRequests.Nodes {
-		if prNode.MergedAt.Time.IsZero() || prNode.CreatedAt.Time.IsZero() {
-			continue
+	if s.repo != nil {
+		pullRequests, found, err := s.repo.FindPullRequests(ctx, FindRepoPullRequestsParams{
+			Host:        ref.Host,
+			Owner:       ref.Owner,
+			Repo:        ref.Repo,
+			States:      params.States,
+			SyncedAfter: time.Now().Add(-s.RepoTTL),
+		})
+		if err != nil {
+			return nil, err
 		}
+		if found {
+			detectStacks(pullRequests)
+			result := &FindAllPullRequestsResult{PullRequests: pullRequests}
+			s.cacheFindAllPullRequestsValue(key, result)
+			return result, nil
+		}
+	}
+
+	syncedAt := time.Now().UTC()
+
+	allPullRequestsParams := provider.AllPullRequestsParams{
+		Host:   ref.Host,
+		Owner:  ref.Owner,
+		Repo:   ref.Repo,
+		States: params.States,
+	}
 
-		contributors := types.Contributors{}
-		contributors = append(contributors, types.Contributor{
-			Login: string(prNode.Author.Login),
+	r, err := p.AllPullRequests(ctx, allPullRequestsParams)
+	if err != nil {
+		return nil, err
+	}
+
+	detectStacks(r.PullRequests)
+
+	result := &FindAllPullRequestsResult{
+		PullRequests: r.PullRequests,
+		Truncated:    r.Truncated,
+	}
+
+	// Truncated results are not persisted so the remaining pull requests are fetched next time.
+	if s.repo != nil && !result.Truncated {
+		err := s.repo.SavePullRequests(ctx, SaveRepoPullRequestsParams{
+			Host:         ref.Host,
+			Owner:        ref.Owner,
+			Repo:         ref.Repo,
+			States:       params.States,
+			PullRequests: result.PullRequests,
+			SyncedAt:     syncedAt,
 		})
-		for _, participant := range prNode.Participants.Nodes {
-			if participant.Login == prNode.Author.Login {
-				continue
-			}
-			c := types.Contributor{
-				ProfileURL: string(participant.URL),
-				ID:         string(participant.ID),
-				Login:      string(participant.Login),
-			}
-			contributors = append(contributors, c)
+		if err != nil {
+			return nil, err
 		}
+	}
 
-		duration := prNode.MergedAt.UTC().Sub(prNode.CreatedAt.UTC())
-		createdAt := prNode.CreatedAt.UTC()
-		mergedAt := prNode.MergedAt.UTC()
-		pr := &types.PullRequest{
-			Number:                int(prNode.Number),
-			Owner:                 owner,
-			Repo:                  repo,
-			Title:                 string(prNode.Title),
-			Body:                  string(prNode.Body),
-			Duration:              duration,
-			CreatedAt:             &createdAt,
-			MergedAt:              &mergedAt,
-			URL:                   string(prNode.URL),
-			Contributors:          contributors,
-			HeadRefName:           string(prNode.HeadRef.Name),
-			FormattedContributors: contributors.FormattedContributors(types.CommasFormatContributorType),
+	s.cacheFindAllPullRequestsValue(key, result)
+
+	return result, nil
+}
+
+// pullRequestsFromNodes returns the pull requests of the given GitHub nodes, skipping the incomplete ones.
+func pullRequestsFromNodes(host string, owner string, repo string, nodes github.AllPullRequestsNodes) []*types.PullRequest {
+	pullRequests := []*types.PullRequest{}
+
+	for _, prNode := range nodes {
+		if prNode.CreatedAt.Time.IsZero() {
+			continue
+		}
+
+		state := pullRequestNodeState(prNode)
+		if state == types.MergedPullRequestState && prNode.MergedAt.Time.IsZero() {
+			continue
 		}
 
-		result.PullRequests = append(result.PullRequests, pr)
+		pullRequests = append(pullRequests, pullRequestFromNode(host, owner, repo, prNode))
 	}
 
-	s.cacheFindAllPullRequestsValue(key, result)
+	return pullRequests
+}
 
-	return result, nil
+// pullRequestFromNode returns the pull request of the given GitHub node.
+func pullRequestFromNode(host string, owner string, repo string, prNode github.AllPullRequestsNode) *types.PullRequest {
+	contributors := github.Contributors(github.ContributorsParams{
+		Host:         host,
+		Author:       prNode.Author,
+		Participants: prNode.Participants.Nodes,
+		Reviews:      prNode.Reviews.Nodes,
+		Commits:      prNode.Commits.Nodes,
+	})
+
+	createdAt := prNode.CreatedAt.UTC()
+	pr := &types.PullRequest{
+		Number:                int(prNode.Number),
+		Host:                  host,
+		Owner:                 owner,
+		Repo:                  repo,
+		Title:                 string(prNode.Title),
+		Body:                  string(prNode.Body),
+		CreatedAt:             &createdAt,
+		State:                 pullRequestNodeState(prNode),
+		IsDraft:               bool(prNode.IsDraft),
+		URL:                   string(prNode.URL),
+		Contributors:          contributors,
+		HeadRefName:           string(prNode.HeadRef.Name),
+		BaseRefName:           string(prNode.BaseRefName),
+		FormattedContributors: contributors.FormattedContributors(types.CommasFormatContributorType),
+	}
+
+	// The head reference is gone once its branch is deleted, unlike its name.
+	if pr.HeadRefName == "" {
+		pr.HeadRefName = string(prNode.HeadRefName)
+	}
+
+	if !prNode.MergedAt.Time.IsZero() {
+		mergedAt := prNode.MergedAt.UTC()
+		pr.MergedAt = &mergedAt
+	}
+
+	if !prNode.ClosedAt.Time.IsZero() {
+		closedAt := prNode.ClosedAt.UTC()
+		pr.ClosedAt = &closedAt
+	}
+
+	if !prNode.UpdatedAt.Time.IsZero() {
+		updatedAt := prNode.UpdatedAt.UTC()
+		pr.UpdatedAt = &updatedAt
+	}
+
+	for _, reviewRequest := range prNode.ReviewRequests.Nodes {
+		pr.RequestedReviewers = append(pr.RequestedReviewers, reviewRequest.RequestedReviewer.Name())
+	}
+	for _, label := range prNode.Labels.Nodes {
+		pr.Labels = append(pr.Labels, string(label.Name))
+	}
+	pr.Milestone = string(prNode.Milestone.Title)
+	for _, assignee := range prNode.Assignees.Nodes {
+		pr.Assignees = append(pr.Assignees, string(assignee.Login))
+	}
+	for _, file := range prNode.Files.Nodes {
+		pr.Files = append(pr.Files, string(file.Path))
+	}
+	for _, issueNode := range prNode.ClosingIssuesReferences.Nodes {
+		pr.LinkedIssues = append(pr.LinkedIssues, issueFromNode(host, issueNode))
+	}
+	pr.ReviewDecision = string(prNode.ReviewDecision)
+	pr.CIStatus = prNode.LastCommit.CIStatus()
+	pr.Duration = pullRequestDuration(pr)
+
+	return pr
+}
+
+// pullRequestNodeState returns the state of the given pull request node, inferring it from
+// its merged and closed times when the state was not fetched.
+func pullRequestNodeState(prNode github.AllPullRequestsNode) types.PullRequestState {
+	if prNode.State != "" {
+		return types.PullRequestState(prNode.State)
+	}
+
+	switch {
+	case !prNode.MergedAt.Time.IsZero():
+		return types.MergedPullRequestState
+	case !prNode.ClosedAt.Time.IsZero():
+		return types.ClosedPullRequestState
+	default:
+		return types.OpenPullRequestState
+	}
+}
+
+// pullRequestDuration returns the time from the pull request creation until it ended, open pull requests age up to now.
+func pullRequestDuration(pr *types.PullRequest) time.Duration {
+	endedAt := time.Now().UTC()
+	if e := pr.EndedAt(); e != nil {
+		endedAt = *e
+	}
+
+	return endedAt.Sub(pr.CreatedAt.UTC())
 }
 
 // `generatePullRequestsGanttCacheKey` returns cache key of `GeneratePullRequestsGantt`.
 func (s *service) generatePullRequestsGanttCacheKey(params GeneratePullRequestsGanttParams) (string, error) {
-	key, err := json.Marshal(params)
+	key, err := json.Marshal(struct {
+		Params     GeneratePullRequestsGanttParams
+		Generation uint64
+	}{params, s.repositoryCacheGeneration(params.RepositoryURL)})
 	if err != nil {
 		return "", err
 	}
@@ -323,7 +695,13 @@ func (s *service) cacheGeneratePullRequestsGanttValue(key string, data any) {
 	s.cache.Add(key, data)
 }
 
+// GeneratePullRequestsGantt generates the Gantt DrawIO files of the pull requests of a repository, or of
+// several repositories drawn in a swimlane each, in parts of at most the given limit of pull requests.
 func (s *service) GeneratePullRequestsGantt(ctx context.Context, params GeneratePullRequestsGanttParams) (*GeneratePullRequestsGanttResult, error) {
+	if len(params.RepositoryURLs) > 0 || params.OrganizationURL != "" {
+		return s.generateRepositoriesPullRequestsGantt(ctx, params)
+	}
+
 	key, err := s.generatePullRequestsGanttCacheKey(params)
 	if err != nil {
 		return nil, err
@@ -337,6 +715,11 @@ func (s *service) GeneratePullRequestsGantt(ctx context.Context, params Generate
 	// Get all pull requests for the repository
 	findAllPRParams := FindAllPullRequestsParams{
 		RepositoryURL: params.RepositoryURL,
+		States:        params.States,
+		Labels:        params.Labels,
+		Milestone:     params.Milestone,
+		Paths:         params.Paths,
+		Component:     params.Component,
 	}
 
 	findAllPullRequestsResult, err := s.FindAllPullRequests(ctx, findAllPRParams)
@@ -352,12 +735,29 @@ func (s *service) GeneratePullRequestsGantt(ctx context.Context, params Generate
 	if err != nil {
 		return nil, fmt.Errorf("failed to extract repository info: %w", err)
 	}
-	repoName := fmt.Sprintf("%s-%s", owner, repo)
+	repoName := strings.Trim(strings.ReplaceAll(fmt.Sprintf("%s-%s", owner, repo), "/", "-"), "-")
+
+	result, err := s.writePullRequestsGantt(pullRequests, repoName, params.Limit, false)
+	if err != nil {
+		return nil, err
+	}
+
+	s.cacheGeneratePullRequestsGanttValue(key, result)
+
+	return result, nil
+}
+
+// writePullRequestsGantt writes the Gantt DrawIO files of the given pull requests to the generated diagrams
+// directory of the given name, in parts of at most the given limit of pull requests. The pull requests are drawn
+// in a swimlane per repository in every part when swimlanes is set.
+func (s *service) writePullRequestsGantt(pullRequests []*types.PullRequest, dirName string, limit int, swimlanes bool) (*GeneratePullRequestsGanttResult, error) {
+	// Keep stacked pull requests right after the ones they are based on
+	pullRequests = groupStacks(pullRequests)
 
 	// Create the base directory path
 	_, filename, _, _ := runtime.Caller(0)
 	repoRoot := filepath.Dir(filepath.Dir(filepath.Dir(filename)))
-	baseDir := filepath.Join(repoRoot, "diagrams", "gantt", "generated", repoName)
+	baseDir := filepath.Join(repoRoot, "diagrams", "gantt", "generated", dirName)
 
 	// Ensure the directory exists
 	if err := os.MkdirAll(baseDir, 0755); err != nil {
@@ -366,9 +766,8 @@ func (s *service) GeneratePullRequestsGantt(ctx context.Context, params Generate
 
 	// Divide pull requests into chunks based on limit
 	var parts []GeneratePullRequestsGanttPart
-	limit := params.Limit
 	if limit <= 0 {
-		limit = 25 // Default limit
+		limit = DefaultGanttLimit
 	}
 
 	for i := 0; i < len(pullRequests); i += limit {
@@ -383,7 +782,7 @@ func (s *service) GeneratePullRequestsGantt(ctx context.Context, params Generate
 		fileUUID := uuid.New().String()
 
 		// Generate the Gantt DrawIO file for this chunk
-		drawioContent, err := s.generateGanttDrawIOFromPullRequests(chunk)
+		drawioContent, err := s.generateGanttDrawIOFromPullRequests(chunk, swimlanes)
 		if err != nil {
 			return nil, fmt.Errorf("failed to generate Gantt for chunk %d: %w", i/limit+1, err)
 		}
@@ -403,16 +802,14 @@ func (s *service) GeneratePullRequestsGantt(ctx context.Context, params Generate
 		})
 	}
 
-	result := &GeneratePullRequestsGanttResult{
+	return &GeneratePullRequestsGanttResult{
 		Parts: parts,
-	}
-
-	s.cacheGeneratePullRequestsGanttValue(key, result)
-
-	return result, nil
+	}, nil
 }
 
-func (s *service) generateGanttDrawIOFromPullRequests(pullRequests []*types.PullRequest) ([]byte, error) {
+// generateGanttDrawIOFromPullRequests returns the Gantt DrawIO file of the given pull requests, drawn in a
+// swimlane per repository when swimlanes is set.
+func (s *service) generateGanttDrawIOFromPullRequests(pullRequests []*types.PullRequest, swimlanes bool) ([]byte, error) {
 	// Get the repository root directory using runtime.Caller
 	_, filename, _, _ := runtime.Caller(0)
 	repoRoot := filepath.Dir(filepath.Dir(filepath.Dir(filename)))
@@ -493,12 +890,48 @@ func (s *service) generateGanttDrawIOFromPullRequests(pullRequests []*types.Pull
 		nextID = 63 // Ensure we start at least at 63 for task rows
 	}
 
+	// Pull requests of several repositories are drawn in a swimlane per repository, each starting with a
+	// header row spanning from the number to the task details columns.
+	laneX, _ := strconv.ParseFloat(templatePrNumberCell.MxGeometry.X, 64)
+	taskDetailsX, _ := strconv.ParseFloat(templateTaskDetailsCell.MxGeometry.X, 64)
+	taskDetailsWidth, _ := strconv.ParseFloat(templateTaskDetailsCell.MxGeometry.Width, 64)
+	laneWidth := strconv.FormatFloat(taskDetailsX+taskDetailsWidth-laneX, 'f', -1, 64)
+	lanes := 0
+	lane := ""
+
+	// Stacked pull requests are indented by their position and connected to the pull request they are based on.
+	numberCellIDs := map[string]string{}
+
 	for i, pr := range pullRequests {
-		if pr.CreatedAt == nil || pr.MergedAt == nil {
+		if pr.CreatedAt == nil || (!pr.IsOpen() && pr.EndedAt() == nil) {
 			continue
 		}
 
-		y := startY + i*rowHeight
+		rowStyle := ganttRowStyle(pr)
+
+		if repository := pr.Owner + "/" + pr.Repo; swimlanes && !strings.EqualFold(repository, lane) {
+			lane = repository
+
+			diagram.MxGraphModel.Root.Cells = append(diagram.MxGraphModel.Root.Cells, gantt.MxCell{
+				ID:     strconv.Itoa(nextID),
+				Value:  repository,
+				Style:  "align=left;fontStyle=1;fillColor=#DAE8FC;strokeColor=#6C8EBF",
+				Parent: "1",
+				Vertex: "1",
+				MxGeometry: &gantt.MxGeometry{
+					X:      templatePrNumberCell.MxGeometry.X,
+					Y:      strconv.Itoa(startY + (i+lanes)*rowHeight),
+					Width:  laneWidth,
+					Height: "20",
+					As:     "geometry",
+				},
+			})
+
+			nextID++
+			lanes++
+		}
+
+		y := startY + (i+lanes)*rowHeight
 		yStr := strconv.Itoa(y)
 		baseID := nextID // Use current nextID for this PR
 
@@ -506,7 +939,7 @@ func (s *service) generateGanttDrawIOFromPullRequests(pullRequests []*types.Pull
 		numberCell := gantt.MxCell{
 			ID:     strconv.Itoa(baseID),
 			Value:  fmt.Sprintf("#%d", pr.Number),
-			Style:  "strokeColor=#DEEDFF;fillColor=#FFF",
+			Style:  rowStyle,
 			Parent: "1",
 			Vertex: "1",
 			MxGeometry: &gantt.MxGeometry{
@@ -519,10 +952,15 @@ func (s *service) generateGanttDrawIOFromPullRequests(pullRequests []*types.Pull
 		}
 
 		// Task name cell (PR title)
+		title := pr.Title
+		if pr.Stack != nil && pr.Stack.Position > 1 {
+			title = strings.Repeat("  ", pr.Stack.Position-2) + "↳ " + title
+		}
+
 		nameCell := gantt.MxCell{
 			ID:     strconv.Itoa(baseID + 1),
-			Value:  pr.Title,
-			Style:  "align=left;strokeColor=#DEEDFF;fillColor=#FFF",
+			Value:  title,
+			Style:  "align=left;" + rowStyle,
 			Parent: "1",
 			Vertex: "1",
 			MxGeometry: &gantt.MxGeometry{
@@ -538,7 +976,7 @@ func (s *service) generateGanttDrawIOFromPullRequests(pullRequests []*types.Pull
 		contributorsCell := gantt.MxCell{
 			ID:     strconv.Itoa(baseID + 2),
 			Value:  pr.FormattedContributors,
-			Style:  "align=left;strokeColor=#DEEDFF;fillColor=#FFF",
+			Style:  "align=left;" + rowStyle,
 			Parent: "1",
 			Vertex: "1",
 			MxGeometry: &gantt.MxGeometry{
@@ -550,8 +988,12 @@ func (s *service) generateGanttDrawIOFromPullRequests(pullRequests []*types.Pull
 			},
 		}
 
-		// Duration cell
-		duration := pr.MergedAt.UTC().Sub(pr.CreatedAt.UTC())
+		// Duration cell, open pull requests last up to now
+		endedAt := time.Now().UTC()
+		if e := pr.EndedAt(); e != nil {
+			endedAt = e.UTC()
+		}
+		duration := endedAt.Sub(pr.CreatedAt.UTC())
 		durationDays := int(duration.Hours() / 24)
 		if durationDays == 0 {
 			durationDays = 1
@@ -564,7 +1006,7 @@ func (s *service) generateGanttDrawIOFromPullRequests(pullRequests []*types.Pull
 		durationCell := gantt.MxCell{
 			ID:     strconv.Itoa(baseID + 3),
 			Value:  durationText,
-			Style:  "strokeColor=#DEEDFF;fillColor=#FFF",
+			Style:  rowStyle,
 			Parent: "1",
 			Vertex: "1",
 			MxGeometry: &gantt.MxGeometry{
@@ -580,7 +1022,7 @@ func (s *service) generateGanttDrawIOFromPullRequests(pullRequests []*types.Pull
 		startDateCell := gantt.MxCell{
 			ID:     strconv.Itoa(baseID + 4),
 			Value:  pr.CreatedAt.UTC().Format("02.01.06"),
-			Style:  "strokeColor=#DEEDFF;fillColor=#FFF",
+			Style:  rowStyle,
 			Parent: "1",
 			Vertex: "1",
 			MxGeometry: &gantt.MxGeometry{
@@ -592,11 +1034,21 @@ func (s *service) generateGanttDrawIOFromPullRequests(pullRequests []*types.Pull
 			},
 		}
 
-		// End date cell
+		// End date cell, open pull requests are drawn open-ended
+		endDateValue := endedAt.Format("02.01.06")
+		endDateStyle := rowStyle
+		if pr.IsOpen() {
+			endDateValue = "Open"
+			if pr.IsDraft {
+				endDateValue = "Draft"
+			}
+			endDateStyle = rowStyle + ";gradientColor=#FFFFFF;gradientDirection=east"
+		}
+
 		endDateCell := gantt.MxCell{
 			ID:     strconv.Itoa(baseID + 5),
-			Value:  pr.MergedAt.UTC().Format("02.01.06"),
-			Style:  "strokeColor=#DEEDFF;fillColor=#FFF",
+			Value:  endDateValue,
+			Style:  endDateStyle,
 			Parent: "1",
 			Vertex: "1",
 			MxGeometry: &gantt.MxGeometry{
@@ -612,7 +1064,7 @@ func (s *service) generateGanttDrawIOFromPullRequests(pullRequests []*types.Pull
 		taskDetailCell := gantt.MxCell{
 			ID:     strconv.Itoa(baseID + 6),
 			Value:  markdown.StripMarkdown(pr.AbbreviatedBody()),
-			Style:  "align=left;strokeColor=#DEEDFF;fillColor=#FFF",
+			Style:  "align=left;" + rowStyle,
 			Parent: "1",
 			Vertex: "1",
 			MxGeometry: &gantt.MxGeometry{
@@ -630,6 +1082,27 @@ func (s *service) generateGanttDrawIOFromPullRequests(pullRequests []*types.Pull
 
 		// Increment nextID by 7 for the next PR
 		nextID += 7
+
+		numberCellIDs[stackKey(pr, pr.Number)] = numberCell.ID
+
+		// Stack connector from the pull request it is based on
+		if pr.Stack != nil && pr.Stack.Parent != 0 {
+			if parentID, ok := numberCellIDs[stackKey(pr, pr.Stack.Parent)]; ok {
+				diagram.MxGraphModel.Root.Cells = append(diagram.MxGraphModel.Root.Cells, gantt.MxCell{
+					ID:     strconv.Itoa(nextID),
+					Style:  "edgeStyle=elbowEdgeStyle;elbow=horizontal;endArrow=block;html=1;strokeColor=#6C8EBF;exitX=0;exitY=0.5;entryX=0;entryY=0.5",
+					Parent: "1",
+					Edge:   "1",
+					Source: parentID,
+					Target: numberCell.ID,
+					MxGeometry: &gantt.MxGeometry{
+						Relative: "1",
+						As:       "geometry",
+					},
+				})
+				nextID++
+			}
+		}
 	}
 
 	// Marshal back to XML
@@ -643,6 +1116,21 @@ func (s *service) generateGanttDrawIOFromPullRequests(pullRequests []*types.Pull
 	return append(xmlDeclaration, output...), nil
 }
 
+// ganttRowStyle returns the style of the Gantt row cells of the given pull request,
+// open pull requests are dashed, drafts greyed out and closed unmerged ones red.
+func ganttRowStyle(pr *types.PullRequest) string {
+	switch {
+	case pr.IsOpen() && pr.IsDraft:
+		return "strokeColor=#B3B3B3;fillColor=#F5F5F5;dashed=1;fontStyle=2"
+	case pr.IsOpen():
+		return "strokeColor=#D6B656;fillColor=#FFF2CC;dashed=1"
+	case pr.State == types.ClosedPullRequestState:
+		return "strokeColor=#B85450;fillColor=#F8CECC"
+	default:
+		return "strokeColor=#DEEDFF;fillColor=#FFF"
+	}
+}
+
 // sortPullRequests sorts given pull requests by given
 func (s *service) sortPullRequestsAsc(p []*types.PullRequest) []*types.PullRequest {
 	prs := []types.PullRequest{}
@@ -670,13 +1158,78 @@ func (s *service) sortPullRequestsAsc(p []*types.PullRequest) []*types.PullReque
 	return result
 }
 
-func NewService(cache *cachePkg.Cache, HTTPClient *http.Client) (*service, error) {
-	gh := github.NewGitHub()
+// GitHubRateLimit returns the GitHub API rate limit status of the given host, github.com when empty.
+func (s *service) GitHubRateLimit(ctx context.Context, host string) (*types.RateLimit, error) {
+	gh, err := s.gitHubFor(host)
+	if err != nil {
+		return nil, err
+	}
+
+	rateLimit, err := gh.RateLimit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rateLimit, nil
+}
+
+type Repo interface {
+	FindPullRequests(ctx context.Context, params FindRepoPullRequestsParams) ([]*types.PullRequest, bool, error)
+	SavePullRequests(ctx context.Context, params SaveRepoPullRequestsParams) error
+	TrackRepository(ctx context.Context, params TrackRepoRepositoryParams) (*types.TrackedRepository, error)
+	UntrackRepository(ctx context.Context, host string, owner string, repo string) (bool, error)
+	FindTrackedRepositories(ctx context.Context) ([]*types.TrackedRepository, error)
+	UpdateTrackedRepositorySync(ctx context.Context, trackedRepository *types.TrackedRepository) error
+}
+
+// NewService returns the metrics service of the given configuration, whose GitHub configuration is required
+// and whose other configurations are optional.
+func NewService(cache *cachePkg.Cache, repo Repo, conf *config.Config) (*service, error) {
+	gh, err := github.NewGitHub(conf.GitHubConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	gitHubHosts := map[string]github.GitHubClient{}
+	for _, hostConf := range conf.GitHubConfig.Hosts {
+		gitHubHosts[strings.ToLower(hostConf.Host)] = github.NewEnterpriseGitHub(conf.GitHubConfig, hostConf)
+	}
+
+	providers := map[string]provider.Provider{}
+	if conf.GitLabConfig != nil {
+		for _, hostConf := range conf.GitLabConfig.Hosts {
+			providers[strings.ToLower(hostConf.Host)] = gitlab.NewGitLab(hostConf, conf.GitLabConfig.Timeout)
+		}
+	}
+	if conf.GiteaConfig != nil {
+		for _, hostConf := range conf.GiteaConfig.Hosts {
+			providers[strings.ToLower(hostConf.Host)] = gitea.NewGitea(hostConf, conf.GiteaConfig.Timeout)
+		}
+	}
+	if conf.LocalConfig != nil && conf.LocalConfig.RepositoriesDir != "" {
+		providers[github.LocalHost] = gitlog.NewGitLog(conf.LocalConfig)
+	}
+
+	metricsConfig := conf.MetricsConfig
+	if metricsConfig == nil {
+		metricsConfig = &config.MetricsConfig{}
+	}
 
 	srv := &service{
-		cache:      cache,
-		HTTPClient: HTTPClient,
-		GitHub:     gh,
+		cache:       cache,
+		GitHub:      gh,
+		GitHubHosts: gitHubHosts,
+		Providers:   providers,
+		repo:        repo,
+		RepoTTL:     DefaultRepoTTL,
+
+		PullRequestsBatchSize:   DefaultPullRequestsBatchSize,
+		PullRequestsConcurrency: DefaultPullRequestsConcurrency,
+		RepositoriesConcurrency: DefaultRepositoriesConcurrency,
+
+		Components: metricsConfig.Components,
+
+		MaxOrganizationRepositories: metricsConfig.MaxOrganizationRepositories,
 	}
 
 	return srv, nil
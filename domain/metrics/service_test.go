@@ -2,27 +2,53 @@ package metrics
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	cachePkg "github.com/chris-ramon/golang-scaffolding/cache"
 	"github.com/chris-ramon/golang-scaffolding/domain/metrics/github"
+	"github.com/chris-ramon/golang-scaffolding/domain/metrics/provider"
+	"github.com/chris-ramon/golang-scaffolding/domain/metrics/types"
 	"github.com/shurcooL/githubv4"
 )
 
 type mockGitHub struct {
 	allPullRequests func(params github.AllPullRequestsParams) (github.AllPullRequestsQuery, error)
+	pullRequests    func(params github.PullRequestsParams) ([]github.PullRequestResult, error)
+	allIssues       func(params github.AllIssuesParams) (github.AllIssuesQuery, error)
+	allReleases     func(params github.AllReleasesParams) (github.AllReleasesQuery, error)
+	allRepositories func(params github.AllRepositoriesParams) (github.AllRepositoriesQuery, error)
+	rateLimit       types.RateLimit
 }
 
-func (m *mockGitHub) AllPullRequests(params github.AllPullRequestsParams) (github.AllPullRequestsQuery, error) {
+func (m *mockGitHub) AllPullRequests(ctx context.Context, params github.AllPullRequestsParams) (github.AllPullRequestsQuery, error) {
 	return m.allPullRequests(params)
 }
 
-func (m *mockGitHub) PullRequestContributors(params github.PullRequestContributorsParams) (github.PullRequestContributorsQuery, error) {
-	return github.PullRequestContributorsQuery{}, nil
+func (m *mockGitHub) PullRequests(ctx context.Context, params github.PullRequestsParams) ([]github.PullRequestResult, error) {
+	return m.pullRequests(params)
 }
 
-func (m *mockGitHub) Query(query any) error {
+func (m *mockGitHub) AllIssues(ctx context.Context, params github.AllIssuesParams) (github.AllIssuesQuery, error) {
+	return m.allIssues(params)
+}
+
+func (m *mockGitHub) AllReleases(ctx context.Context, params github.AllReleasesParams) (github.AllReleasesQuery, error) {
+	return m.allReleases(params)
+}
+
+func (m *mockGitHub) AllRepositories(ctx context.Context, params github.AllRepositoriesParams) (github.AllRepositoriesQuery, error) {
+	return m.allRepositories(params)
+}
+
+func (m *mockGitHub) RateLimit(ctx context.Context) (types.RateLimit, error) {
+	return m.rateLimit, nil
+}
+
+func (m *mockGitHub) Query(ctx context.Context, query any, variables map[string]any) error {
 	return nil
 }
 
@@ -117,6 +143,153 @@ func TestFindAllPullRequests(t *testing.T) {
 	}
 }
 
+func TestFindPullRequests(t *testing.T) {
+	createdAt := time.Now().Add(-2 * time.Hour)
+	mergedAt := time.Now()
+
+	var requests atomic.Int32
+
+	mockGH := &mockGitHub{
+		pullRequests: func(params github.PullRequestsParams) ([]github.PullRequestResult, error) {
+			requests.Add(1)
+
+			if len(params.PullRequests) > 2 {
+				t.Errorf("expected batches of at most 2 pull requests, got %d", len(params.PullRequests))
+			}
+
+			results := []github.PullRequestResult{}
+			for _, pr := range params.PullRequests {
+				if pr.Owner == "broken" {
+					return nil, fmt.Errorf("query failed: %w", &github.RateLimitError{StatusCode: 403})
+				}
+
+				switch pr.Number {
+				case 404:
+					results = append(results, github.PullRequestResult{Err: github.ErrPullRequestNotFound})
+				case 1:
+					results = append(results, github.PullRequestResult{PullRequest: github.AllPullRequestsNode{
+						Number:    1,
+						CreatedAt: githubv4.DateTime{Time: createdAt},
+						State:     githubv4.PullRequestStateOpen,
+					}})
+				default:
+					results = append(results, github.PullRequestResult{PullRequest: github.AllPullRequestsNode{
+						Number:    githubv4.Int(pr.Number),
+						Title:     githubv4.String(fmt.Sprintf("%s/%s#%d", pr.Owner, pr.Repo, pr.Number)),
+						CreatedAt: githubv4.DateTime{Time: createdAt},
+						MergedAt:  githubv4.DateTime{Time: mergedAt},
+						State:     githubv4.PullRequestStateMerged,
+						Author:    github.Author{Login: "author", URL: "https://github.com/author"},
+						Participants: github.Participants{
+							Nodes: github.ParticipantsNodes{
+								{URL: "https://github.com/author", Login: "author"},
+								{URL: "https://github.com/reviewer", Login: "reviewer"},
+							},
+						},
+					}})
+				}
+			}
+			return results, nil
+		},
+	}
+
+	srv := &service{cache: cachePkg.New(), GitHub: mockGH, PullRequestsBatchSize: 2}
+
+	params := types.FindPullRequestsParams{
+		{Owner: "test", Repo: "repo", Number: 7, URL: "https://github.com/test/repo/pull/7"},
+		{Owner: "other", Repo: "repo", Number: 8, URL: "https://github.com/other/repo/pull/8"},
+		{Owner: "test", Repo: "tools", Number: 404, URL: "https://github.com/test/tools/pull/404"},
+		{Owner: "test", Repo: "repo", Number: 1, URL: "https://github.com/test/repo/pull/1"},
+		{Owner: "test", Repo: "repo", Number: 9, URL: "https://github.com/test/repo/pull/9"},
+		{Owner: "broken", Repo: "repo", Number: 2, URL: "https://github.com/broken/repo/pull/2"},
+	}
+
+	result, err := srv.FindPullRequests(context.Background(), params)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// test has 4 pull requests split in 2 batches, other and broken 1 batch each.
+	if requests.Load() != 4 {
+		t.Errorf("expected 4 batched requests, got %d", requests.Load())
+	}
+
+	if len(result.PullRequests) != len(params) {
+		t.Fatalf("expected a result per pull request, got %d", len(result.PullRequests))
+	}
+
+	expected := []struct {
+		title string
+		code  types.PullRequestErrorCode
+	}{
+		{title: "test/repo#7"},
+		{title: "other/repo#8"},
+		{code: types.NotFoundPullRequestErrorCode},
+		{code: types.NotMergedPullRequestErrorCode},
+		{title: "test/repo#9"},
+		{code: types.RateLimitedPullRequestErrorCode},
+	}
+
+	for i, e := range expected {
+		pr := result.PullRequests[i]
+
+		if pr.Title != e.title {
+			t.Errorf("expected pull request %d to be %q, got %q", i, e.title, pr.Title)
+		}
+
+		if pr.URL != params[i].URL {
+			t.Errorf("expected pull request %d URL %s, got %s", i, params[i].URL, pr.URL)
+		}
+
+		var code types.PullRequestErrorCode
+		if pr.Error != nil {
+			code = pr.Error.Code
+		}
+		if code != e.code {
+			t.Errorf("expected pull request %d error code %q, got %q", i, e.code, code)
+		}
+	}
+
+	pr := result.PullRequests[0]
+	if pr.State != types.MergedPullRequestState || len(pr.Contributors) != 2 {
+		t.Errorf("expected merged pull request with its 2 participants, got %s with %d", pr.State, len(pr.Contributors))
+	}
+
+	if pr.Duration != mergedAt.UTC().Sub(createdAt.UTC()) {
+		t.Errorf("expected duration from creation to merge, got %s", pr.Duration)
+	}
+
+	if _, err := srv.FindPullRequests(context.Background(), params); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if requests.Load() != 8 {
+		t.Errorf("expected results with errors not to be cached, got %d requests", requests.Load())
+	}
+
+	// Without the failing pull request the result is cached, until one of its repositories is invalidated.
+	params = params[:2]
+	for i := 0; i < 2; i++ {
+		if _, err := srv.FindPullRequests(context.Background(), params); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if requests.Load() != 10 {
+		t.Errorf("expected complete results to be cached, got %d requests", requests.Load())
+	}
+
+	srv.invalidateRepositoryCache("github.com", "Other", "Repo")
+
+	if _, err := srv.FindPullRequests(context.Background(), params); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if requests.Load() != 12 {
+		t.Errorf("expected the cached result of an invalidated repository to be fetched again, got %d requests", requests.Load())
+	}
+}
+
 func TestRepositoryFromURL(t *testing.T) {
 	testCases := []struct {
 		name          string
@@ -132,6 +305,20 @@ func TestRepositoryFromURL(t *testing.T) {
 			expectedRepo:  "repo",
 			expectError:   false,
 		},
+		{
+			name:          "GitHub Enterprise Server repository URL",
+			url:           "https://github.example.com/owner/repo.git",
+			expectedOwner: "owner",
+			expectedRepo:  "repo",
+			expectError:   false,
+		},
+		{
+			name:          "SSH remote",
+			url:           "git@github.com:owner/repo.git",
+			expectedOwner: "owner",
+			expectedRepo:  "repo",
+			expectError:   false,
+		},
 		{
 			name:        "invalid URL - too short",
 			url:         "https://github.com/owner",
@@ -171,6 +358,198 @@ func TestRepositoryFromURL(t *testing.T) {
 	}
 }
 
+func TestFindAllPullRequestsEnterpriseHost(t *testing.T) {
+	createdAt := time.Now().Add(-time.Hour)
+
+	mockGHES := &mockGitHub{
+		allPullRequests: func(params github.AllPullRequestsParams) (github.AllPullRequestsQuery, error) {
+			query := github.AllPullRequestsQuery{}
+			query.Repository.PullRequests.Nodes = github.AllPullRequestsNodes{
+				{
+					Number:    1,
+					URL:       "https://github.example.com/test/repo/pull/1",
+					State:     githubv4.PullRequestStateOpen,
+					CreatedAt: githubv4.DateTime{Time: createdAt},
+				},
+			}
+			return query, nil
+		},
+	}
+
+	srv := &service{
+		cache:       cachePkg.New(),
+		GitHub:      &mockGitHub{},
+		GitHubHosts: map[string]github.GitHubClient{"github.example.com": mockGHES},
+	}
+
+	result, err := srv.FindAllPullRequests(context.Background(), FindAllPullRequestsParams{
+		RepositoryURL: "https://GitHub.example.com/test/repo",
+		States:        []types.PullRequestState{types.OpenPullRequestState},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.PullRequests) != 1 {
+		t.Fatalf("expected 1 pull request from the enterprise host, got %d", len(result.PullRequests))
+	}
+
+	if result.PullRequests[0].Host != "github.example.com" {
+		t.Errorf("expected host github.example.com, got %s", result.PullRequests[0].Host)
+	}
+
+	_, err = srv.FindAllPullRequests(context.Background(), FindAllPullRequestsParams{
+		RepositoryURL: "https://unknown.example.com/test/repo",
+	})
+	if err == nil {
+		t.Errorf("expected error for an unconfigured host")
+	}
+}
+
+func TestGitHubRateLimitEnterpriseHost(t *testing.T) {
+	srv := &service{
+		cache:       cachePkg.New(),
+		GitHub:      &mockGitHub{rateLimit: types.RateLimit{Remaining: 5000}},
+		GitHubHosts: map[string]github.GitHubClient{"github.example.com": &mockGitHub{rateLimit: types.RateLimit{Remaining: 42}}},
+	}
+
+	tests := []struct {
+		host      string
+		remaining int
+	}{
+		{"", 5000},
+		{"github.com", 5000},
+		{"GitHub.example.com", 42},
+	}
+
+	for _, test := range tests {
+		rateLimit, err := srv.GitHubRateLimit(context.Background(), test.host)
+		if err != nil {
+			t.Fatalf("unexpected error for host %q: %v", test.host, err)
+		}
+
+		if rateLimit.Remaining != test.remaining {
+			t.Errorf("expected %d remaining for host %q, got %d", test.remaining, test.host, rateLimit.Remaining)
+		}
+	}
+
+	if _, err := srv.GitHubRateLimit(context.Background(), "unknown.example.com"); err == nil {
+		t.Errorf("expected error for an unconfigured host")
+	}
+}
+
+type mockProvider struct {
+	allPullRequests func(params provider.AllPullRequestsParams) (*provider.AllPullRequestsResult, error)
+	pullRequests    func(params provider.PullRequestsParams) ([]provider.PullRequestResult, error)
+}
+
+func (m *mockProvider) AllPullRequests(ctx context.Context, params provider.AllPullRequestsParams) (*provider.AllPullRequestsResult, error) {
+	return m.allPullRequests(params)
+}
+
+func (m *mockProvider) PullRequests(ctx context.Context, params provider.PullRequestsParams) ([]provider.PullRequestResult, error) {
+	return m.pullRequests(params)
+}
+
+func TestFindPullRequestsMissingResults(t *testing.T) {
+	mockGitLab := &mockProvider{
+		pullRequests: func(params provider.PullRequestsParams) ([]provider.PullRequestResult, error) {
+			// A single result for the whole batch.
+			return []provider.PullRequestResult{{PullRequest: &types.PullRequest{Number: 1}}}, nil
+		},
+	}
+
+	srv := &service{
+		cache:     cachePkg.New(),
+		GitHub:    &mockGitHub{},
+		Providers: map[string]provider.Provider{"gitlab.com": mockGitLab},
+	}
+
+	result, err := srv.FindPullRequests(context.Background(), types.FindPullRequestsParams{
+		{Host: "gitlab.com", Owner: "group", Repo: "project", Number: 1, URL: "https://gitlab.com/group/project/-/merge_requests/1"},
+		{Host: "gitlab.com", Owner: "group", Repo: "project", Number: 2, URL: "https://gitlab.com/group/project/-/merge_requests/2"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for i, pr := range result.PullRequests {
+		if pr.Error == nil || pr.Error.Code != types.InternalPullRequestErrorCode {
+			t.Errorf("expected pull request %d to fail with an internal error, got %+v", i, pr.Error)
+		}
+	}
+}
+
+func TestFindPullRequestsGitLabHost(t *testing.T) {
+	createdAt := time.Now().Add(-time.Hour)
+	mergedAt := time.Now()
+
+	mockGitLab := &mockProvider{
+		allPullRequests: func(params provider.AllPullRequestsParams) (*provider.AllPullRequestsResult, error) {
+			if params.Host != "gitlab.com" || params.Owner != "group/subgroup" || params.Repo != "project" {
+				t.Errorf("unexpected params: %+v", params)
+			}
+
+			return &provider.AllPullRequestsResult{PullRequests: []*types.PullRequest{
+				{Number: 1, Host: params.Host, CreatedAt: &createdAt, MergedAt: &mergedAt, State: types.MergedPullRequestState},
+			}}, nil
+		},
+		pullRequests: func(params provider.PullRequestsParams) ([]provider.PullRequestResult, error) {
+			results := []provider.PullRequestResult{}
+			for _, param := range params.PullRequests {
+				if param.Number == 404 {
+					results = append(results, provider.PullRequestResult{Err: provider.ErrPullRequestNotFound})
+					continue
+				}
+
+				results = append(results, provider.PullRequestResult{PullRequest: &types.PullRequest{
+					Number: param.Number, Host: params.Host, Owner: param.Owner, Repo: param.Repo, CreatedAt: &createdAt, MergedAt: &mergedAt,
+				}})
+			}
+			return results, nil
+		},
+	}
+
+	srv := &service{
+		cache:     cachePkg.New(),
+		GitHub:    &mockGitHub{},
+		Providers: map[string]provider.Provider{"gitlab.com": mockGitLab},
+	}
+
+	allResult, err := srv.FindAllPullRequests(context.Background(), FindAllPullRequestsParams{
+		RepositoryURL: "https://gitlab.com/group/subgroup/project/-/merge_requests",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(allResult.PullRequests) != 1 || allResult.PullRequests[0].Host != "gitlab.com" {
+		t.Fatalf("expected 1 merge request from the GitLab host, got %+v", allResult.PullRequests)
+	}
+
+	params := types.FindPullRequestsParams{}
+	for _, url := range []string{"https://gitlab.com/group/subgroup/project/-/merge_requests/7", "gitlab.com/group/subgroup/project!404"} {
+		pr, err := github.PullRequestFromURL(url)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		params = append(params, types.FindPullRequestParam{Host: pr.Host, Owner: pr.Owner, Repo: pr.Repo, Number: pr.Number, URL: pr.URL})
+	}
+
+	result, err := srv.FindPullRequests(context.Background(), params)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if pr := result.PullRequests[0]; pr.Error != nil || pr.URL != "https://gitlab.com/group/subgroup/project/-/merge_requests/7" {
+		t.Errorf("expected merge request with its canonical URL, got %+v", pr)
+	}
+
+	if pr := result.PullRequests[1]; pr.Error == nil || pr.Error.Code != types.NotFoundPullRequestErrorCode {
+		t.Errorf("expected not found merge request, got %+v", pr)
+	}
+}
+
 func TestAllPullRequestsPagination(t *testing.T) {
 	cache := cachePkg.New()
 
@@ -260,3 +639,454 @@ func TestAllPullRequestsPagination(t *testing.T) {
 		t.Errorf("expected second PR number 124, got %d", pr2.Number)
 	}
 }
+
+func TestFindAllPullRequestsContributorRoles(t *testing.T) {
+	cache := cachePkg.New()
+
+	createdAt := time.Now().Add(-7 * 24 * time.Hour)
+	mergedAt := time.Now()
+
+	mockGH := &mockGitHub{
+		allPullRequests: func(params github.AllPullRequestsParams) (github.AllPullRequestsQuery, error) {
+			return github.AllPullRequestsQuery{
+				Repository: github.AllPullRequestsRepository{
+					PullRequests: github.AllPullRequestsPullRequests{
+						Nodes: github.AllPullRequestsNodes{
+							{
+								Author:    github.Author{Login: "author", URL: "https://github.com/author"},
+								Number:    githubv4.Int(125),
+								CreatedAt: githubv4.DateTime{Time: createdAt},
+								MergedAt:  githubv4.DateTime{Time: mergedAt},
+								Participants: github.Participants{
+									Nodes: github.ParticipantsNodes{
+										{URL: "https://github.com/author", Login: "author"},
+										{URL: "https://github.com/reviewer", Login: "reviewer"},
+										{URL: "https://github.com/commenter", Login: "commenter"},
+										{URL: "https://github.com/pair", Login: "pair"},
+									},
+								},
+								Reviews: github.Reviews{
+									Nodes: github.ReviewsNodes{
+										{Author: github.Author{Login: "reviewer"}},
+									},
+								},
+								Commits: github.Commits{
+									Nodes: github.CommitsNodes{
+										{Commit: github.Commit{Message: "Add feature\n\nCo-authored-by: Pair <1+pair@users.noreply.github.com>"}},
+										{Commit: github.Commit{Message: "Fix tests\n\nCo-authored-by: Jane Doe <jane@example.com>"}},
+									},
+								},
+							},
+						},
+					},
+				},
+			}, nil
+		},
+	}
+
+	srv := &service{
+		cache:  cache,
+		GitHub: mockGH,
+	}
+
+	result, err := srv.FindAllPullRequests(context.Background(), FindAllPullRequestsParams{
+		RepositoryURL: "https://github.com/test/repo",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedRoles := map[string]types.ContributorRole{
+		"author":    types.AuthorContributorRole,
+		"reviewer":  types.ReviewerContributorRole,
+		"commenter": types.CommenterContributorRole,
+		"pair":      types.CoAuthorContributorRole,
+		"Jane Doe":  types.CoAuthorContributorRole,
+	}
+
+	contributors := result.PullRequests[0].Contributors
+	if len(contributors) != len(expectedRoles) {
+		t.Fatalf("expected %d contributors, got %d", len(expectedRoles), len(contributors))
+	}
+
+	for _, c := range contributors {
+		if c.Role != expectedRoles[c.DisplayName()] {
+			t.Errorf("expected %s role to be %s, got %s", c.DisplayName(), expectedRoles[c.DisplayName()], c.Role)
+		}
+	}
+}
+
+func TestFindAllPullRequestsStates(t *testing.T) {
+	cache := cachePkg.New()
+
+	createdAt := time.Now().Add(-7 * 24 * time.Hour)
+	closedAt := time.Now().Add(-5 * 24 * time.Hour)
+
+	var requestedStates []types.PullRequestState
+
+	mockGH := &mockGitHub{
+		allPullRequests: func(params github.AllPullRequestsParams) (github.AllPullRequestsQuery, error) {
+			requestedStates = params.States
+
+			return github.AllPullRequestsQuery{
+				Repository: github.AllPullRequestsRepository{
+					PullRequests: github.AllPullRequestsPullRequests{
+						Nodes: github.AllPullRequestsNodes{
+							{
+								Number:    githubv4.Int(1),
+								CreatedAt: githubv4.DateTime{Time: createdAt},
+								State:     githubv4.PullRequestStateOpen,
+								IsDraft:   githubv4.Boolean(true),
+							},
+							{
+								Number:    githubv4.Int(2),
+								CreatedAt: githubv4.DateTime{Time: createdAt},
+								ClosedAt:  githubv4.DateTime{Time: closedAt},
+								State:     githubv4.PullRequestStateClosed,
+							},
+						},
+					},
+				},
+			}, nil
+		},
+	}
+
+	srv := &service{
+		cache:  cache,
+		GitHub: mockGH,
+	}
+
+	states := []types.PullRequestState{types.OpenPullRequestState, types.ClosedPullRequestState}
+	result, err := srv.FindAllPullRequests(context.Background(), FindAllPullRequestsParams{
+		RepositoryURL: "https://github.com/test/repo",
+		States:        states,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(requestedStates) != 2 {
+		t.Fatalf("expected states to be requested, got %v", requestedStates)
+	}
+
+	if len(result.PullRequests) != 2 {
+		t.Fatalf("expected 2 pull requests, got %d", len(result.PullRequests))
+	}
+
+	open := result.PullRequests[0]
+	if !open.IsOpen() || !open.IsDraft {
+		t.Errorf("expected open draft pull request, got state %s and draft %v", open.State, open.IsDraft)
+	}
+	if open.Duration < 7*24*time.Hour {
+		t.Errorf("expected open pull request to age up to now, got %v", open.Duration)
+	}
+
+	closed := result.PullRequests[1]
+	if closed.State != types.ClosedPullRequestState || closed.EndedAt() == nil {
+		t.Fatalf("expected closed pull request with end time, got state %s", closed.State)
+	}
+	if closed.Duration.Round(time.Hour) != 2*24*time.Hour {
+		t.Errorf("expected closed pull request duration of 2 days, got %v", closed.Duration)
+	}
+
+	drawioContent, err := srv.generateGanttDrawIOFromPullRequests(result.PullRequests, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(string(drawioContent), `value="Draft"`) {
+		t.Errorf("expected open draft pull request to be drawn open-ended")
+	}
+}
+
+func TestFindAllPullRequestsLabelsAndMilestone(t *testing.T) {
+	createdAt := time.Now().Add(-48 * time.Hour)
+	mergedAt := time.Now().Add(-24 * time.Hour)
+	fetches := 0
+
+	node := func(number int, milestone string, labels ...string) github.AllPullRequestsNode {
+		n := github.AllPullRequestsNode{
+			Number:    githubv4.Int(number),
+			CreatedAt: githubv4.DateTime{Time: createdAt},
+			MergedAt:  githubv4.DateTime{Time: mergedAt},
+			Milestone: github.Milestone{Title: githubv4.String(milestone)},
+			Assignees: github.Assignees{Nodes: github.AssigneesNodes{{Login: githubv4.String("octocat")}}},
+		}
+		for _, label := range labels {
+			n.Labels.Nodes = append(n.Labels.Nodes, github.LabelsNode{Name: githubv4.String(label)})
+		}
+		return n
+	}
+
+	mockGH := &mockGitHub{
+		allPullRequests: func(params github.AllPullRequestsParams) (github.AllPullRequestsQuery, error) {
+			fetches++
+
+			query := github.AllPullRequestsQuery{}
+			query.Repository.PullRequests.Nodes = github.AllPullRequestsNodes{
+				node(1, "v1.0", "bug", "backend"),
+				node(2, "v1.0", "bug"),
+				node(3, "v2.0", "Bug", "backend"),
+				node(4, ""),
+			}
+			return query, nil
+		},
+	}
+
+	srv := &service{
+		cache:  cachePkg.New(),
+		GitHub: mockGH,
+	}
+
+	tests := []struct {
+		name      string
+		labels    []string
+		milestone string
+		expected  []int
+	}{
+		{name: "no filters", expected: []int{1, 2, 3, 4}},
+		{name: "every label", labels: []string{"bug", "BACKEND"}, expected: []int{1, 3}},
+		{name: "milestone", milestone: "V1.0", expected: []int{1, 2}},
+		{name: "labels and milestone", labels: []string{"backend"}, milestone: "v2.0", expected: []int{3}},
+		{name: "no match", labels: []string{"docs"}, expected: []int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := srv.FindAllPullRequests(context.Background(), FindAllPullRequestsParams{
+				RepositoryURL: "https://github.com/owner/repo",
+				Labels:        tt.labels,
+				Milestone:     tt.milestone,
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			numbers := []int{}
+			for _, pr := range result.PullRequests {
+				numbers = append(numbers, pr.Number)
+			}
+
+			if fmt.Sprint(numbers) != fmt.Sprint(tt.expected) {
+				t.Errorf("expected pull requests %v, got %v", tt.expected, numbers)
+			}
+		})
+	}
+
+	if fetches != 1 {
+		t.Errorf("expected the pull requests to be fetched once regardless of the filters, got %d fetches", fetches)
+	}
+
+	result, err := srv.FindAllPullRequests(context.Background(), FindAllPullRequestsParams{RepositoryURL: "https://github.com/owner/repo"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	pr := result.PullRequests[0]
+	if fmt.Sprint(pr.Labels) != "[bug backend]" || pr.Milestone != "v1.0" || fmt.Sprint(pr.Assignees) != "[octocat]" {
+		t.Errorf("unexpected pull request metadata: labels %v, milestone %q, assignees %v", pr.Labels, pr.Milestone, pr.Assignees)
+	}
+}
+
+func TestFindAllPullRequestsPaths(t *testing.T) {
+	createdAt := time.Now().Add(-48 * time.Hour)
+	mergedAt := time.Now().Add(-24 * time.Hour)
+	fetches := 0
+
+	node := func(number int, paths ...string) github.AllPullRequestsNode {
+		n := github.AllPullRequestsNode{
+			Number:    githubv4.Int(number),
+			CreatedAt: githubv4.DateTime{Time: createdAt},
+			MergedAt:  githubv4.DateTime{Time: mergedAt},
+		}
+		for _, path := range paths {
+			n.Files.Nodes = append(n.Files.Nodes, github.FilesNode{Path: githubv4.String(path)})
+		}
+		return n
+	}
+
+	mockGH := &mockGitHub{
+		allPullRequests: func(params github.AllPullRequestsParams) (github.AllPullRequestsQuery, error) {
+			fetches++
+
+			query := github.AllPullRequestsQuery{}
+			query.Repository.PullRequests.Nodes = github.AllPullRequestsNodes{
+				node(1, "services/billing/invoice.go", "README.md"),
+				node(2, "services/auth/token.go"),
+				node(3, "libs/money/money.go"),
+				node(4, "docs/billing.md"),
+			}
+			return query, nil
+		},
+	}
+
+	srv := &service{
+		cache:  cachePkg.New(),
+		GitHub: mockGH,
+		Components: map[string][]string{
+			"billing": {"services/billing", "libs/money/**/*.go"},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		paths     []string
+		component string
+		expected  []int
+	}{
+		{name: "directory", paths: []string{"services/auth"}, expected: []int{2}},
+		{name: "glob", paths: []string{"services/*/*.go"}, expected: []int{1, 2}},
+		{name: "component", component: "billing", expected: []int{1, 3}},
+		{name: "component and paths", paths: []string{"docs/**"}, component: "billing", expected: []int{1, 3, 4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := FindAllPullRequestsParams{
+				RepositoryURL: "https://github.com/owner/repo",
+				Paths:         tt.paths,
+				Component:     tt.component,
+			}
+
+			result, err := srv.FindAllPullRequests(context.Background(), params)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			numbers := []int{}
+			for _, pr := range result.PullRequests {
+				numbers = append(numbers, pr.Number)
+			}
+
+			if fmt.Sprint(numbers) != fmt.Sprint(tt.expected) {
+				t.Errorf("expected pull requests %v, got %v", tt.expected, numbers)
+			}
+
+			cached, err := srv.FindAllPullRequests(context.Background(), params)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if cached != result {
+				t.Errorf("expected the filtered result to be cached")
+			}
+		})
+	}
+
+	if fetches != 1 {
+		t.Errorf("expected the pull requests to be fetched once regardless of the filters, got %d fetches", fetches)
+	}
+
+	_, err := srv.FindAllPullRequests(context.Background(), FindAllPullRequestsParams{RepositoryURL: "https://github.com/owner/repo", Component: "search"})
+	if err == nil || !strings.Contains(err.Error(), `unknown component "search"`) {
+		t.Errorf("expected an unknown component error, got %v", err)
+	}
+
+	_, err = srv.FindAllPullRequests(context.Background(), FindAllPullRequestsParams{RepositoryURL: "https://github.com/owner/repo", Paths: []string{"services/["}})
+	if err == nil || !strings.Contains(err.Error(), `invalid path "services/["`) {
+		t.Errorf("expected an invalid path error, got %v", err)
+	}
+}
+
+type mockRepo struct {
+	pullRequests        []*types.PullRequest
+	found               bool
+	saved               []SaveRepoPullRequestsParams
+	trackedRepositories []*types.TrackedRepository
+}
+
+func (m *mockRepo) FindPullRequests(ctx context.Context, params FindRepoPullRequestsParams) ([]*types.PullRequest, bool, error) {
+	return m.pullRequests, m.found, nil
+}
+
+func (m *mockRepo) SavePullRequests(ctx context.Context, params SaveRepoPullRequestsParams) error {
+	m.saved = append(m.saved, params)
+	return nil
+}
+
+func (m *mockRepo) TrackRepository(ctx context.Context, params TrackRepoRepositoryParams) (*types.TrackedRepository, error) {
+	trackedRepository := &types.TrackedRepository{
+		ID:             len(m.trackedRepositories) + 1,
+		Host:           params.Host,
+		Owner:          params.Owner,
+		Repo:           params.Repo,
+		URL:            params.URL,
+		LastSyncStatus: types.PendingSyncStatus,
+	}
+	m.trackedRepositories = append(m.trackedRepositories, trackedRepository)
+	return trackedRepository, nil
+}
+
+func (m *mockRepo) UntrackRepository(ctx context.Context, host string, owner string, repo string) (bool, error) {
+	for i, trackedRepository := range m.trackedRepositories {
+		if trackedRepository.Host == host && trackedRepository.Owner == owner && trackedRepository.Repo == repo {
+			m.trackedRepositories = append(m.trackedRepositories[:i], m.trackedRepositories[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *mockRepo) FindTrackedRepositories(ctx context.Context) ([]*types.TrackedRepository, error) {
+	result := []*types.TrackedRepository{}
+	for _, trackedRepository := range m.trackedRepositories {
+		copied := *trackedRepository
+		result = append(result, &copied)
+	}
+	return result, nil
+}
+
+func (m *mockRepo) UpdateTrackedRepositorySync(ctx context.Context, trackedRepository *types.TrackedRepository) error {
+	for i, t := range m.trackedRepositories {
+		if t.ID == trackedRepository.ID {
+			copied := *trackedRepository
+			m.trackedRepositories[i] = &copied
+		}
+	}
+	return nil
+}
+
+func TestFindAllPullRequestsReadsThroughRepo(t *testing.T) {
+	createdAt := time.Now().Add(-time.Hour)
+	gitHubCalls := 0
+
+	mockGH := &mockGitHub{
+		allPullRequests: func(params github.AllPullRequestsParams) (github.AllPullRequestsQuery, error) {
+			gitHubCalls++
+			query := github.AllPullRequestsQuery{}
+			query.Repository.PullRequests.Nodes = github.AllPullRequestsNodes{
+				{Number: 1, State: githubv4.PullRequestStateMerged, CreatedAt: githubv4.DateTime{Time: createdAt}, MergedAt: githubv4.DateTime{Time: time.Now()}},
+			}
+			return query, nil
+		},
+	}
+
+	repo := &mockRepo{}
+	srv := &service{cache: cachePkg.New(), GitHub: mockGH, repo: repo}
+
+	result, err := srv.FindAllPullRequests(context.Background(), FindAllPullRequestsParams{RepositoryURL: "https://github.com/test/repo"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if gitHubCalls != 1 || len(repo.saved) != 1 || len(repo.saved[0].PullRequests) != len(result.PullRequests) {
+		t.Fatalf("expected pull requests fetched from GitHub to be persisted, got %d GitHub calls and %d saves", gitHubCalls, len(repo.saved))
+	}
+
+	repo.found = true
+	repo.pullRequests = []*types.PullRequest{{Number: 2, CreatedAt: &createdAt}}
+	srv.cache = cachePkg.New()
+
+	result, err = srv.FindAllPullRequests(context.Background(), FindAllPullRequestsParams{RepositoryURL: "https://github.com/test/repo"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if gitHubCalls != 1 {
+		t.Errorf("expected persisted pull requests to be served without calling GitHub, got %d calls", gitHubCalls)
+	}
+
+	if len(result.PullRequests) != 1 || result.PullRequests[0].Number != 2 {
+		t.Errorf("expected persisted pull request 2, got %+v", result.PullRequests)
+	}
+}
@@ -0,0 +1,129 @@
+package metrics
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/chris-ramon/golang-scaffolding/domain/metrics/types"
+)
+
+// detectStacks sets the stack of the given pull requests of a repository based on the head reference of another
+// one, and of the ones others are based on. A pull request is based on the most recently created pull request
+// whose head reference is its base reference, created before it and still open when it was. The pull requests
+// whose head reference is their own base reference, like the ones of forks from their default branch, never
+// start a stack.
+func detectStacks(pullRequests []*types.PullRequest) {
+	byHeadRefName := map[string][]*types.PullRequest{}
+	for _, pr := range pullRequests {
+		pr.Stack = nil
+
+		if pr.CreatedAt == nil || pr.HeadRefName == "" || pr.HeadRefName == pr.BaseRefName {
+			continue
+		}
+		byHeadRefName[pr.HeadRefName] = append(byHeadRefName[pr.HeadRefName], pr)
+	}
+
+	parents := map[*types.PullRequest]*types.PullRequest{}
+	children := map[*types.PullRequest][]*types.PullRequest{}
+
+	for _, pr := range pullRequests {
+		if pr.CreatedAt == nil || pr.BaseRefName == "" {
+			continue
+		}
+
+		var parent *types.PullRequest
+		for _, candidate := range byHeadRefName[pr.BaseRefName] {
+			if candidate == pr || !createdBefore(candidate, pr) {
+				continue
+			}
+			if endedAt := candidate.EndedAt(); endedAt != nil && endedAt.Before(*pr.CreatedAt) {
+				continue
+			}
+			if parent == nil || createdBefore(parent, candidate) {
+				parent = candidate
+			}
+		}
+
+		if parent != nil {
+			parents[pr] = parent
+			children[parent] = append(children[parent], pr)
+		}
+	}
+
+	for _, pr := range pullRequests {
+		if _, ok := parents[pr]; ok || len(children[pr]) == 0 {
+			continue
+		}
+
+		members := []*types.PullRequest{}
+
+		var walk func(member *types.PullRequest, parent int, position int)
+		walk = func(member *types.PullRequest, parent int, position int) {
+			member.Stack = &types.PullRequestStack{Root: pr.Number, Position: position, Parent: parent}
+			members = append(members, member)
+
+			for _, child := range children[member] {
+				walk(child, member.Number, position+1)
+			}
+		}
+		walk(pr, 0, 1)
+
+		for _, member := range members {
+			member.Stack.Size = len(members)
+		}
+	}
+}
+
+// createdBefore reports whether the pull request a was created before b, the lowest number first when they were
+// created at the same time.
+func createdBefore(a *types.PullRequest, b *types.PullRequest) bool {
+	if a.CreatedAt.Equal(*b.CreatedAt) {
+		return a.Number < b.Number
+	}
+
+	return a.CreatedAt.Before(*b.CreatedAt)
+}
+
+// groupStacks returns the given pull requests with the stacked ones right after the pull request they are based
+// on, the other pull requests are kept in the same order.
+func groupStacks(pullRequests []*types.PullRequest) []*types.PullRequest {
+	present := map[string]bool{}
+	for _, pr := range pullRequests {
+		present[stackKey(pr, pr.Number)] = true
+	}
+
+	children := map[string][]*types.PullRequest{}
+	for _, pr := range pullRequests {
+		if pr.Stack == nil || pr.Stack.Parent == 0 {
+			continue
+		}
+
+		if parentKey := stackKey(pr, pr.Stack.Parent); present[parentKey] {
+			children[parentKey] = append(children[parentKey], pr)
+		}
+	}
+
+	result := make([]*types.PullRequest, 0, len(pullRequests))
+
+	var add func(pr *types.PullRequest)
+	add = func(pr *types.PullRequest) {
+		result = append(result, pr)
+		for _, child := range children[stackKey(pr, pr.Number)] {
+			add(child)
+		}
+	}
+
+	for _, pr := range pullRequests {
+		if pr.Stack != nil && pr.Stack.Parent != 0 && present[stackKey(pr, pr.Stack.Parent)] {
+			continue
+		}
+		add(pr)
+	}
+
+	return result
+}
+
+// stackKey returns the key of the pull request of the given number of the repository of the given pull request.
+func stackKey(pr *types.PullRequest, number int) string {
+	return strings.ToLower(fmt.Sprintf("%s/%s#%d", pr.Owner, pr.Repo, number))
+}
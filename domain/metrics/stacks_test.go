@@ -0,0 +1,136 @@
+package metrics
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+
+	cachePkg "github.com/chris-ramon/golang-scaffolding/cache"
+	"github.com/chris-ramon/golang-scaffolding/domain/metrics/github"
+	"github.com/chris-ramon/golang-scaffolding/domain/metrics/types"
+)
+
+func TestDetectStacks(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(days int) *time.Time {
+		t := start.Add(time.Duration(days) * 24 * time.Hour)
+		return &t
+	}
+
+	pr := func(number int, head string, base string, createdAt *time.Time, mergedAt *time.Time) *types.PullRequest {
+		state := types.OpenPullRequestState
+		if mergedAt != nil {
+			state = types.MergedPullRequestState
+		}
+		return &types.PullRequest{Number: number, Owner: "org", Repo: "api", HeadRefName: head, BaseRefName: base, CreatedAt: createdAt, MergedAt: mergedAt, State: state}
+	}
+
+	reused := pr(1, "feature", "main", at(0), at(1))
+	root := pr(2, "feature", "main", at(2), at(6))
+	middle := pr(3, "feature-api", "feature", at(3), nil)
+	top := pr(4, "feature-ui", "feature-api", at(4), nil)
+	sibling := pr(5, "feature-docs", "feature", at(4), nil)
+	fork := pr(6, "main", "main", at(1), nil)
+	unrelated := pr(7, "fix", "main", at(5), nil)
+
+	pullRequests := []*types.PullRequest{top, sibling, middle, root, reused, fork, unrelated}
+	detectStacks(pullRequests)
+
+	expected := map[*types.PullRequest]types.PullRequestStack{
+		root:    {Root: 2, Position: 1, Parent: 0, Size: 4},
+		middle:  {Root: 2, Position: 2, Parent: 2, Size: 4},
+		top:     {Root: 2, Position: 3, Parent: 3, Size: 4},
+		sibling: {Root: 2, Position: 2, Parent: 2, Size: 4},
+	}
+
+	for _, pr := range pullRequests {
+		stack, stacked := expected[pr]
+
+		if !stacked {
+			if pr.Stack != nil {
+				t.Errorf("expected #%d not to be stacked, got %+v", pr.Number, *pr.Stack)
+			}
+			continue
+		}
+
+		if pr.Stack == nil || *pr.Stack != stack {
+			t.Errorf("expected #%d stack %+v, got %+v", pr.Number, stack, pr.Stack)
+		}
+	}
+
+	numbers := []int{}
+	for _, pr := range groupStacks([]*types.PullRequest{reused, root, middle, top, sibling, fork, unrelated}) {
+		numbers = append(numbers, pr.Number)
+	}
+	if len(numbers) != 7 || numbers[1] != 2 || numbers[2] != 3 || numbers[3] != 4 || numbers[4] != 5 {
+		t.Errorf("expected stacked pull requests right after the ones they are based on, got %v", numbers)
+	}
+}
+
+func TestFindAllPullRequestsStacks(t *testing.T) {
+	createdAt := time.Now().Add(-72 * time.Hour)
+
+	mockGH := &mockGitHub{
+		allPullRequests: func(params github.AllPullRequestsParams) (github.AllPullRequestsQuery, error) {
+			nodes := github.AllPullRequestsNodes{
+				{
+					Number:      githubv4.Int(2),
+					CreatedAt:   githubv4.DateTime{Time: createdAt.Add(time.Hour)},
+					State:       githubv4.PullRequestStateOpen,
+					HeadRefName: "feature-api",
+					BaseRefName: "feature",
+				},
+				{
+					Number:      githubv4.Int(1),
+					CreatedAt:   githubv4.DateTime{Time: createdAt},
+					State:       githubv4.PullRequestStateOpen,
+					HeadRefName: "feature",
+					BaseRefName: "main",
+				},
+			}
+			nodes[1].HeadRef.Name = "feature"
+
+			return github.AllPullRequestsQuery{
+				Repository: github.AllPullRequestsRepository{
+					PullRequests: github.AllPullRequestsPullRequests{Nodes: nodes},
+				},
+			}, nil
+		},
+	}
+
+	srv := &service{
+		cache:  cachePkg.New(),
+		GitHub: mockGH,
+	}
+
+	result, err := srv.FindAllPullRequests(context.Background(), FindAllPullRequestsParams{
+		RepositoryURL: "https://github.com/org/api",
+		States:        []types.PullRequestState{types.OpenPullRequestState},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	top, root := result.PullRequests[0], result.PullRequests[1]
+
+	if top.HeadRefName != "feature-api" || top.BaseRefName != "feature" {
+		t.Errorf("expected the head reference name of deleted branches, got %q into %q", top.HeadRefName, top.BaseRefName)
+	}
+
+	if root.Stack == nil || root.Stack.Position != 1 || top.Stack == nil || top.Stack.Parent != 1 || top.Stack.Size != 2 {
+		t.Fatalf("expected #2 stacked on #1, got %+v and %+v", root.Stack, top.Stack)
+	}
+
+	drawioContent, err := srv.generateGanttDrawIOFromPullRequests(groupStacks(srv.sortPullRequestsAsc(result.PullRequests)), false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	content := string(drawioContent)
+	if strings.Count(content, `edge="1"`) != 1 || !strings.Contains(content, "↳ ") {
+		t.Errorf("expected the stacked pull request indented and connected to the one it is based on")
+	}
+}
@@ -0,0 +1,172 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/chris-ramon/golang-scaffolding/domain/metrics/github"
+	"github.com/chris-ramon/golang-scaffolding/domain/metrics/provider"
+	"github.com/chris-ramon/golang-scaffolding/domain/metrics/types"
+)
+
+// errRepoNotConfigured is returned by the operations requiring the persistence of the pull requests.
+var errRepoNotConfigured = errors.New("pull requests persistence is not configured")
+
+// allPullRequestStates are the pull request states synced for the tracked repositories.
+var allPullRequestStates = []types.PullRequestState{
+	types.OpenPullRequestState,
+	types.ClosedPullRequestState,
+	types.MergedPullRequestState,
+}
+
+type TrackRepositoryParams struct {
+	RepositoryURL string
+}
+
+type UntrackRepositoryParams struct {
+	RepositoryURL string
+}
+
+type RefreshRepositoryParams struct {
+	Host  string
+	Owner string
+	Repo  string
+}
+
+// TrackRepository adds a repository to the tracked repositories synced in the background.
+func (s *service) TrackRepository(ctx context.Context, params TrackRepositoryParams) (*types.TrackedRepository, error) {
+	if s.repo == nil {
+		return nil, errRepoNotConfigured
+	}
+
+	ref, err := github.ParseRepoRef(params.RepositoryURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.providerFor(ref.Host); err != nil {
+		return nil, err
+	}
+
+	return s.repo.TrackRepository(ctx, TrackRepoRepositoryParams{
+		Host:  ref.Host,
+		Owner: ref.Owner,
+		Repo:  ref.Repo,
+		URL:   ref.URL(),
+	})
+}
+
+// UntrackRepository removes a repository from the tracked repositories, false when it was not tracked.
+func (s *service) UntrackRepository(ctx context.Context, params UntrackRepositoryParams) (bool, error) {
+	if s.repo == nil {
+		return false, errRepoNotConfigured
+	}
+
+	ref, err := github.ParseRepoRef(params.RepositoryURL)
+	if err != nil {
+		return false, err
+	}
+
+	return s.repo.UntrackRepository(ctx, ref.Host, ref.Owner, ref.Repo)
+}
+
+// TrackedRepositories returns the tracked repositories with the outcome of their last sync.
+func (s *service) TrackedRepositories(ctx context.Context) ([]*types.TrackedRepository, error) {
+	if s.repo == nil {
+		return nil, errRepoNotConfigured
+	}
+
+	return s.repo.FindTrackedRepositories(ctx)
+}
+
+// SyncTrackedRepositories syncs every tracked repository and records the outcome of each sync. A failed
+// sync does not stop the following ones, the returned error only reports failures to record them.
+func (s *service) SyncTrackedRepositories(ctx context.Context) error {
+	trackedRepositories, err := s.TrackedRepositories(ctx)
+	if err != nil {
+		return err
+	}
+
+	errs := []error{}
+
+	for _, trackedRepository := range trackedRepositories {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		syncedAt := time.Now().UTC()
+		trackedRepository.LastSyncedAt = &syncedAt
+		trackedRepository.LastSyncStatus = types.SucceededSyncStatus
+		trackedRepository.LastSyncError = ""
+
+		if err := s.syncRepository(ctx, trackedRepository.Host, trackedRepository.Owner, trackedRepository.Repo); err != nil {
+			trackedRepository.LastSyncStatus = types.FailedSyncStatus
+			trackedRepository.LastSyncError = err.Error()
+		}
+
+		if err := s.repo.UpdateTrackedRepositorySync(ctx, trackedRepository); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// RefreshRepository refreshes the data of a repository after it changed on GitHub: the persisted pull requests
+// are synced when persistence is configured and the cached results of the repository are invalidated. The host
+// defaults to github.com.
+func (s *service) RefreshRepository(ctx context.Context, params RefreshRepositoryParams) error {
+	if params.Host == "" {
+		params.Host = github.DefaultHost
+	}
+
+	if s.repo == nil {
+		s.invalidateRepositoryCache(params.Host, params.Owner, params.Repo)
+		return nil
+	}
+
+	return s.syncRepository(ctx, params.Host, params.Owner, params.Repo)
+}
+
+// syncRepository fetches the pull requests of every state of the given repository from GitHub, persists
+// them and invalidates the cached results of the repository.
+func (s *service) syncRepository(ctx context.Context, host string, owner string, repo string) error {
+	syncedAt := time.Now().UTC()
+
+	p, err := s.providerFor(host)
+	if err != nil {
+		return err
+	}
+
+	r, err := p.AllPullRequests(ctx, provider.AllPullRequestsParams{
+		Host:   host,
+		Owner:  owner,
+		Repo:   repo,
+		States: allPullRequestStates,
+	})
+	if err != nil {
+		return err
+	}
+
+	if r.Truncated {
+		return fmt.Errorf("pull requests truncated by the maximum number of pages")
+	}
+
+	err = s.repo.SavePullRequests(ctx, SaveRepoPullRequestsParams{
+		Host:         host,
+		Owner:        owner,
+		Repo:         repo,
+		States:       allPullRequestStates,
+		PullRequests: r.PullRequests,
+		SyncedAt:     syncedAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	s.invalidateRepositoryCache(host, owner, repo)
+
+	return nil
+}
@@ -0,0 +1,144 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	cachePkg "github.com/chris-ramon/golang-scaffolding/cache"
+	"github.com/chris-ramon/golang-scaffolding/domain/metrics/github"
+	"github.com/chris-ramon/golang-scaffolding/domain/metrics/types"
+	"github.com/shurcooL/githubv4"
+)
+
+func TestSyncTrackedRepositories(t *testing.T) {
+	createdAt := time.Now().Add(-time.Hour)
+
+	mockGH := &mockGitHub{
+		allPullRequests: func(params github.AllPullRequestsParams) (github.AllPullRequestsQuery, error) {
+			if params.Repo == "broken" {
+				return github.AllPullRequestsQuery{}, errors.New("repository not found")
+			}
+
+			if len(params.States) != len(allPullRequestStates) {
+				t.Errorf("expected every state to be synced, got %v", params.States)
+			}
+
+			query := github.AllPullRequestsQuery{}
+			query.Repository.PullRequests.Nodes = github.AllPullRequestsNodes{
+				{Number: 1, State: githubv4.PullRequestStateOpen, CreatedAt: githubv4.DateTime{Time: createdAt}},
+			}
+			return query, nil
+		},
+	}
+
+	repo := &mockRepo{}
+	srv := &service{cache: cachePkg.New(), GitHub: mockGH, repo: repo}
+
+	for _, url := range []string{"https://github.com/test/repo", "https://github.com/test/broken"} {
+		if _, err := srv.TrackRepository(context.Background(), TrackRepositoryParams{RepositoryURL: url}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	keyBeforeSync, err := srv.findAllPullRequestsCacheKey(FindAllPullRequestsParams{RepositoryURL: "https://github.com/test/repo"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := srv.SyncTrackedRepositories(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	trackedRepositories, err := srv.TrackedRepositories(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(trackedRepositories) != 2 {
+		t.Fatalf("expected 2 tracked repositories, got %d", len(trackedRepositories))
+	}
+
+	synced, broken := trackedRepositories[0], trackedRepositories[1]
+
+	if synced.LastSyncStatus != types.SucceededSyncStatus || synced.LastSyncedAt == nil || synced.LastSyncError != "" {
+		t.Errorf("expected successful sync, got %+v", synced)
+	}
+
+	if broken.LastSyncStatus != types.FailedSyncStatus || broken.LastSyncError != "repository not found" {
+		t.Errorf("expected failed sync with its error, got %+v", broken)
+	}
+
+	if len(repo.saved) != 1 || len(repo.saved[0].PullRequests) != 1 {
+		t.Fatalf("expected synced pull requests to be persisted, got %+v", repo.saved)
+	}
+
+	keyAfterSync, err := srv.findAllPullRequestsCacheKey(FindAllPullRequestsParams{RepositoryURL: "https://github.com/test/repo"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if keyBeforeSync == keyAfterSync {
+		t.Errorf("expected sync to invalidate the cached results of the repository")
+	}
+
+	untracked, err := srv.UntrackRepository(context.Background(), UntrackRepositoryParams{RepositoryURL: "https://github.com/test/broken"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !untracked || len(repo.trackedRepositories) != 1 {
+		t.Errorf("expected repository to be untracked")
+	}
+}
+
+type mockSyncer struct {
+	calls  int
+	cancel context.CancelFunc
+}
+
+func (m *mockSyncer) SyncTrackedRepositories(ctx context.Context) error {
+	m.calls++
+	if m.calls == 2 {
+		m.cancel()
+	}
+	return nil
+}
+
+func TestSchedulerRun(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	syncer := &mockSyncer{cancel: cancel}
+
+	NewScheduler(syncer, time.Millisecond).Run(ctx)
+
+	if syncer.calls != 2 {
+		t.Errorf("expected 2 syncs until cancelled, got %d", syncer.calls)
+	}
+}
+
+func TestRefreshRepositoryWithoutRepo(t *testing.T) {
+	srv := &service{cache: cachePkg.New(), GitHub: &mockGitHub{}}
+
+	params := FindAllPullRequestsParams{RepositoryURL: "https://github.com/Test/Repo"}
+
+	keyBeforeRefresh, err := srv.findAllPullRequestsCacheKey(params)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := srv.RefreshRepository(context.Background(), RefreshRepositoryParams{Owner: "test", Repo: "repo"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	keyAfterRefresh, err := srv.findAllPullRequestsCacheKey(params)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if keyBeforeRefresh == keyAfterRefresh {
+		t.Errorf("expected refresh to invalidate the cached results of the repository")
+	}
+}
@@ -15,6 +15,9 @@ type PullRequest struct {
 	// Number is the unique number of the pull request.
 	Number int
 
+	// Host is the GitHub host of the pull request repository, e.g. `github.com`.
+	Host string
+
 	// Owner is the owner of the pull request.
 	Owner string
 
@@ -33,6 +36,27 @@ type PullRequest struct {
 	// MergedAt is the pull request merged at time.
 	MergedAt *time.Time
 
+	// ClosedAt is the pull request closed at time, set for merged and closed pull requests.
+	ClosedAt *time.Time
+
+	// State is the pull request state.
+	State PullRequestState
+
+	// IsDraft reports whether the pull request is a draft.
+	IsDraft bool
+
+	// UpdatedAt is the pull request last activity time.
+	UpdatedAt *time.Time
+
+	// RequestedReviewers are the users and teams with pending review requests.
+	RequestedReviewers []string
+
+	// ReviewDecision is the pull request review state: APPROVED, CHANGES_REQUESTED or REVIEW_REQUIRED.
+	ReviewDecision string
+
+	// CIStatus is the combined CI status of the pull request last commit: SUCCESS, FAILURE, PENDING, ERROR or EXPECTED.
+	CIStatus string
+
 	// URL is the pull request url.
 	URL string
 
@@ -42,11 +66,205 @@ type PullRequest struct {
 	// HeadRefName is the pull request head reference name.
 	HeadRefName string
 
+	// BaseRefName is the name of the reference the pull request is merged into.
+	BaseRefName string
+
+	// MergeCommit is the hash of the merge commit of the pull requests of local repositories, which identifies
+	// the merges without pull request number.
+	MergeCommit string
+
+	// Stack is the stack of dependent pull requests the pull request belongs to, nil when it is not stacked.
+	Stack *PullRequestStack
+
+	// Labels are the names of the pull request labels.
+	Labels []string
+
+	// Milestone is the title of the pull request milestone, empty when there is none.
+	Milestone string
+
+	// Assignees are the logins of the users assigned to the pull request.
+	Assignees []string
+
+	// Files are the paths of the files changed by the pull request.
+	Files []string
+
 	// FormattedContributors are the pull request's formatted contributors.
 	FormattedContributors string
 
 	// Author is pull request's author.
 	Author Author
+
+	// LinkedIssues are the issues the pull request closes when merged.
+	LinkedIssues []Issue
+
+	// Error is the error of the pull request when it could not be fetched, in which case only its
+	// identity is set.
+	Error *PullRequestError
+}
+
+// PullRequestStack represents the position of a pull request in a stack of pull requests, each one based on
+// the head reference of the previous one.
+type PullRequestStack struct {
+	// Root is the number of the bottom pull request of the stack.
+	Root int
+
+	// Position is the position of the pull request in the stack, 1 for the root.
+	Position int
+
+	// Parent is the number of the pull request the pull request is based on, zero for the root.
+	Parent int
+
+	// Size is the number of pull requests of the stack.
+	Size int
+}
+
+// PullRequestErrorCode is the machine-readable code of the error of a pull request.
+type PullRequestErrorCode string
+
+const (
+	// NotFoundPullRequestErrorCode is the code of the pull requests missing from GitHub.
+	NotFoundPullRequestErrorCode PullRequestErrorCode = "NOT_FOUND"
+
+	// NotMergedPullRequestErrorCode is the code of the pull requests expected to be merged.
+	NotMergedPullRequestErrorCode PullRequestErrorCode = "NOT_MERGED"
+
+	// RateLimitedPullRequestErrorCode is the code of the pull requests not fetched because of the GitHub rate limit.
+	RateLimitedPullRequestErrorCode PullRequestErrorCode = "RATE_LIMITED"
+
+	// InvalidURLPullRequestErrorCode is the code of the pull request URLs that could not be parsed.
+	InvalidURLPullRequestErrorCode PullRequestErrorCode = "INVALID_URL"
+
+	// InternalPullRequestErrorCode is the code of the other errors.
+	InternalPullRequestErrorCode PullRequestErrorCode = "INTERNAL"
+)
+
+// PullRequestError represents the error of a pull request that could not be fetched.
+type PullRequestError struct {
+	// Code is the machine-readable code of the error.
+	Code PullRequestErrorCode
+
+	// Message is the human-readable message of the error.
+	Message string
+}
+
+// Error implements the error interface.
+func (e *PullRequestError) Error() string {
+	return e.Message
+}
+
+// PullRequestState represents the state of a pull request.
+type PullRequestState string
+
+const (
+	// OpenPullRequestState is the state of the pull requests still in progress.
+	OpenPullRequestState PullRequestState = "OPEN"
+
+	// ClosedPullRequestState is the state of the pull requests closed without being merged.
+	ClosedPullRequestState PullRequestState = "CLOSED"
+
+	// MergedPullRequestState is the state of the merged pull requests.
+	MergedPullRequestState PullRequestState = "MERGED"
+)
+
+// IsOpen reports whether the pull request is still open.
+func (p *PullRequest) IsOpen() bool {
+	return p.State == OpenPullRequestState
+}
+
+// EndedAt returns the time the pull request was merged or closed, nil when it is still open.
+func (p *PullRequest) EndedAt() *time.Time {
+	if p.MergedAt != nil {
+		return p.MergedAt
+	}
+
+	if p.State == ClosedPullRequestState {
+		return p.ClosedAt
+	}
+
+	return nil
+}
+
+// Issue represents an internal issue.
+type Issue struct {
+	// Number is the unique number of the issue.
+	Number int
+
+	// Host is the GitHub host of the issue repository, e.g. `github.com`.
+	Host string
+
+	// Owner is the owner of the issue repository.
+	Owner string
+
+	// Repo is the repository name of the issue.
+	Repo string
+
+	// Title is the issue title.
+	Title string
+
+	// Body is the issue body.
+	Body string
+
+	// URL is the issue url.
+	URL string
+
+	// State is the issue state.
+	State IssueState
+
+	// CreatedAt is the issue created at time.
+	CreatedAt *time.Time
+
+	// ClosedAt is the issue closed at time, nil when it is open.
+	ClosedAt *time.Time
+
+	// Author is the issue author.
+	Author Author
+}
+
+// IssueState represents the state of an issue.
+type IssueState string
+
+const (
+	// OpenIssueState is the state of the issues still in progress.
+	OpenIssueState IssueState = "OPEN"
+
+	// ClosedIssueState is the state of the closed issues.
+	ClosedIssueState IssueState = "CLOSED"
+)
+
+// Release represents an internal release.
+type Release struct {
+	// TagName is the name of the git tag of the release.
+	TagName string
+
+	// Host is the host of the release repository, e.g. `github.com`.
+	Host string
+
+	// Owner is the owner of the release repository.
+	Owner string
+
+	// Repo is the repository name of the release.
+	Repo string
+
+	// Name is the release name.
+	Name string
+
+	// Description is the release description.
+	Description string
+
+	// URL is the release url.
+	URL string
+
+	// IsPrerelease reports whether the release is a pre-release.
+	IsPrerelease bool
+
+	// CreatedAt is the release created at time.
+	CreatedAt *time.Time
+
+	// PublishedAt is the release published at time, nil when it is a draft.
+	PublishedAt *time.Time
+
+	// Author is the release author.
+	Author Author
 }
 
 // Author represents the pull request author.
@@ -75,6 +293,41 @@ type Contributor struct {
 
 	// ProfileURL is the contributor profile URL.
 	ProfileURL string
+
+	// Name is the contributor name, set for co-authors from their commit trailer.
+	Name string
+
+	// Email is the contributor email, set for co-authors from their commit trailer.
+	Email string
+
+	// Role is the contributor role in the pull request.
+	Role ContributorRole
+}
+
+// ContributorRole represents the role of a contributor in a pull request.
+type ContributorRole string
+
+const (
+	// AuthorContributorRole is the role of the pull request author.
+	AuthorContributorRole ContributorRole = "AUTHOR"
+
+	// CoAuthorContributorRole is the role of the contributors credited by `Co-authored-by` commit trailers.
+	CoAuthorContributorRole ContributorRole = "CO_AUTHOR"
+
+	// ReviewerContributorRole is the role of the contributors that reviewed the pull request.
+	ReviewerContributorRole ContributorRole = "REVIEWER"
+
+	// CommenterContributorRole is the role of the remaining pull request participants.
+	CommenterContributorRole ContributorRole = "COMMENTER"
+)
+
+// DisplayName returns the contributor login, or the name when the login is unknown.
+func (c Contributor) DisplayName() string {
+	if c.Login != "" {
+		return c.Login
+	}
+
+	return c.Name
 }
 
 // Contributors represents slice of Contributors.
@@ -92,7 +345,7 @@ func (c *Contributors) FormattedContributors(formatContributorType FormatContrib
 		result := []string{}
 
 		for _, contributor := range *c {
-			result = append(result, contributor.Login)
+			result = append(result, contributor.DisplayName())
 		}
 
 		return strings.Join(result, ", ")
@@ -117,13 +370,23 @@ func (c *Contributors) FormattedContributors(formatContributorType FormatContrib
 	}
 }
 
-// FormattedIntervalDates formats and returns the created at and merged at dates.
+// FormattedIntervalDates formats and returns the created at and merged or closed at dates,
+// open pull requests are formatted as an interval up to the present.
 func (pr PullRequest) FormattedIntervalDates() string {
-	if pr.CreatedAt == nil || pr.MergedAt == nil {
+	if pr.CreatedAt == nil {
 		return ""
 	}
 
-	return fmt.Sprintf("%s - %s", pr.CreatedAt.String(), pr.MergedAt.String())
+	if pr.IsOpen() {
+		return fmt.Sprintf("%s - present", pr.CreatedAt.String())
+	}
+
+	endedAt := pr.EndedAt()
+	if endedAt == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%s - %s", pr.CreatedAt.String(), endedAt.String())
 }
 
 // PullRequests are a slice of pull requests.
@@ -134,6 +397,9 @@ type FindPullRequestParam struct {
 	// Number is the unique number parameter.
 	Number int
 
+	// Host is the GitHub host parameter.
+	Host string
+
 	// Owner is the owner parameter.
 	Owner string
 
@@ -146,3 +412,65 @@ type FindPullRequestParam struct {
 
 // FindPullRequestsParams are a slice of find pull requests parameters.
 type FindPullRequestsParams []FindPullRequestParam
+
+// RateLimit represents the GitHub API rate limit status.
+type RateLimit struct {
+	// Limit is the maximum number of points per hour.
+	Limit int
+
+	// Cost is the cost of the last query.
+	Cost int
+
+	// Remaining is the number of points remaining in the current window.
+	Remaining int
+
+	// Used is the number of points used in the current window.
+	Used int
+
+	// ResetAt is the time the current window resets.
+	ResetAt time.Time
+}
+
+// TrackedRepository represents a repository synced in the background.
+type TrackedRepository struct {
+	// ID is the tracked repository ID.
+	ID int
+
+	// Host is the repository host, github.com or a GitHub Enterprise Server host.
+	Host string
+
+	// Owner is the repository owner.
+	Owner string
+
+	// Repo is the repository name.
+	Repo string
+
+	// URL is the repository URL.
+	URL string
+
+	// TrackedAt is the time the repository was tracked.
+	TrackedAt time.Time
+
+	// LastSyncedAt is the time of the last sync, nil when it was never synced.
+	LastSyncedAt *time.Time
+
+	// LastSyncStatus is the status of the last sync.
+	LastSyncStatus SyncStatus
+
+	// LastSyncError is the error of the last sync, empty unless it failed.
+	LastSyncError string
+}
+
+// SyncStatus represents the status of a repository sync.
+type SyncStatus string
+
+const (
+	// PendingSyncStatus is the status of the repositories not synced yet.
+	PendingSyncStatus SyncStatus = "PENDING"
+
+	// SucceededSyncStatus is the status of the successful syncs.
+	SucceededSyncStatus SyncStatus = "SUCCEEDED"
+
+	// FailedSyncStatus is the status of the failed syncs.
+	FailedSyncStatus SyncStatus = "FAILED"
+)
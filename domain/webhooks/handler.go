@@ -0,0 +1,191 @@
+package webhooks
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chris-ramon/golang-scaffolding/config"
+	"github.com/chris-ramon/golang-scaffolding/domain/metrics"
+	"github.com/chris-ramon/golang-scaffolding/domain/metrics/github"
+)
+
+// maxPayloadSize is the maximum size of the GitHub webhook payloads.
+const maxPayloadSize = 25 << 20
+
+// refreshTimeout is the timeout of the repository refresh of a GitHub webhook event, which outlives the delivery
+// since GitHub closes the connection after 10 seconds.
+const refreshTimeout = 10 * time.Minute
+
+type Service interface {
+	RefreshRepository(ctx context.Context, params metrics.RefreshRepositoryParams) error
+	GeneratePullRequestsGantt(ctx context.Context, params metrics.GeneratePullRequestsGanttParams) (*metrics.GeneratePullRequestsGanttResult, error)
+}
+
+type handlers struct {
+	service Service
+
+	// secret is the secret the GitHub webhook payloads are signed with.
+	secret []byte
+
+	// regenerateGantt reports whether the Gantt artifacts are regenerated after a repository refresh.
+	regenerateGantt bool
+
+	// refreshes are the repository refreshes running in the background.
+	refreshes sync.WaitGroup
+}
+
+// gitHubEventPayload represents the fields shared by the handled GitHub webhook payloads.
+type gitHubEventPayload struct {
+	Action     string `json:"action"`
+	Repository struct {
+		Name    string `json:"name"`
+		HTMLURL string `json:"html_url"`
+		Owner   struct {
+			Login string `json:"login"`
+		} `json:"owner"`
+	} `json:"repository"`
+}
+
+// handledGitHubEvents are the GitHub webhook events refreshing the repository data.
+var handledGitHubEvents = map[string]bool{
+	"pull_request":        true,
+	"pull_request_review": true,
+	"release":             true,
+}
+
+func (h *handlers) PostGitHub() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if len(h.secret) == 0 {
+			log.Printf("failed to verify github webhook: secret not configured")
+			http.Error(w, "webhook secret not configured", http.StatusServiceUnavailable)
+			return
+		}
+
+		b, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxPayloadSize))
+		if err != nil {
+			log.Printf("failed to read request body: %v", err)
+			http.Error(w, "failed to read request body", http.StatusBadRequest)
+			return
+		}
+
+		if !validSignature(h.secret, b, r.Header.Get("X-Hub-Signature-256")) {
+			http.Error(w, "invalid signature", http.StatusUnauthorized)
+			return
+		}
+
+		event := r.Header.Get("X-GitHub-Event")
+		if !handledGitHubEvents[event] {
+			w.WriteHeader(http.StatusAccepted)
+			w.Write([]byte("ignored"))
+			return
+		}
+
+		var payload gitHubEventPayload
+		if err := json.Unmarshal(b, &payload); err != nil {
+			log.Printf("failed to json unmarshal request body: %v", err)
+			http.Error(w, "failed to json unmarshal request body", http.StatusBadRequest)
+			return
+		}
+
+		if payload.Repository.Owner.Login == "" || payload.Repository.Name == "" {
+			http.Error(w, "missing repository", http.StatusBadRequest)
+			return
+		}
+
+		ref, err := github.ParseRepoRef(payload.Repository.HTMLURL)
+		if err != nil {
+			http.Error(w, "invalid repository URL", http.StatusBadRequest)
+			return
+		}
+
+		// The refresh runs detached from the delivery, which GitHub cancels long before large repositories are synced.
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(r.Context()), refreshTimeout)
+
+		h.refreshes.Add(1)
+		go func() {
+			defer h.refreshes.Done()
+			defer cancel()
+
+			h.refresh(ctx, metrics.RefreshRepositoryParams{
+				Host:  ref.Host,
+				Owner: payload.Repository.Owner.Login,
+				Repo:  payload.Repository.Name,
+			}, payload.Repository.HTMLURL)
+		}()
+
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte("accepted"))
+	}
+}
+
+// refresh refreshes the given repository and regenerates its Gantt artifacts when configured to.
+func (h *handlers) refresh(ctx context.Context, params metrics.RefreshRepositoryParams, repositoryURL string) {
+	if err := h.service.RefreshRepository(ctx, params); err != nil {
+		log.Printf("failed to refresh repository %s/%s: %v", params.Owner, params.Repo, err)
+		return
+	}
+
+	if !h.regenerateGantt {
+		return
+	}
+
+	// The same parameters as the GraphQL `gantt` field without arguments, so the regenerated Gantt is the cached one.
+	_, err := h.service.GeneratePullRequestsGantt(ctx, metrics.GeneratePullRequestsGanttParams{
+		RepositoryURL: repositoryURL,
+		Limit:         metrics.DefaultGanttLimit,
+	})
+	if err != nil {
+		log.Printf("failed to regenerate gantt of %s: %v", repositoryURL, err)
+	}
+}
+
+// Shutdown waits for the repository refreshes running in the background, or until the given context is done.
+func (h *handlers) Shutdown(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		h.refreshes.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// validSignature reports whether the given `X-Hub-Signature-256` header is the HMAC-SHA256 of the payload.
+func validSignature(secret []byte, payload []byte, signature string) bool {
+	hexSignature, ok := strings.CutPrefix(signature, "sha256=")
+	if !ok {
+		return false
+	}
+
+	expected, err := hex.DecodeString(hexSignature)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+
+	return hmac.Equal(mac.Sum(nil), expected)
+}
+
+func NewHandlers(service Service, gitHubConfig *config.GitHubConfig) *handlers {
+	return &handlers{
+		service:         service,
+		secret:          []byte(gitHubConfig.WebhookSecret),
+		regenerateGantt: gitHubConfig.WebhookRegenerateGantt,
+	}
+}
@@ -0,0 +1,143 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/chris-ramon/golang-scaffolding/config"
+	"github.com/chris-ramon/golang-scaffolding/domain/metrics"
+)
+
+const testSecret = "test-secret"
+
+type mockService struct {
+	mu        sync.Mutex
+	refreshed []metrics.RefreshRepositoryParams
+	gantts    []metrics.GeneratePullRequestsGanttParams
+}
+
+func (m *mockService) RefreshRepository(ctx context.Context, params metrics.RefreshRepositoryParams) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	m.refreshed = append(m.refreshed, params)
+	return nil
+}
+
+func (m *mockService) GeneratePullRequestsGantt(ctx context.Context, params metrics.GeneratePullRequestsGanttParams) (*metrics.GeneratePullRequestsGanttResult, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.gantts = append(m.gantts, params)
+	return &metrics.GeneratePullRequestsGanttResult{}, nil
+}
+
+// sign returns the `X-Hub-Signature-256` header value of the given payload.
+func sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestPostGitHub(t *testing.T) {
+	testCases := []struct {
+		name               string
+		event              string
+		fixture            string
+		secret             string
+		expectedStatus     int
+		expectedRefreshed  int
+		expectedGanttCalls int
+	}{
+		{name: "pull request", event: "pull_request", fixture: "pull_request.json", secret: testSecret, expectedStatus: http.StatusAccepted, expectedRefreshed: 1, expectedGanttCalls: 1},
+		{name: "pull request review", event: "pull_request_review", fixture: "pull_request_review.json", secret: testSecret, expectedStatus: http.StatusAccepted, expectedRefreshed: 1, expectedGanttCalls: 1},
+		{name: "release", event: "release", fixture: "release.json", secret: testSecret, expectedStatus: http.StatusAccepted, expectedRefreshed: 1, expectedGanttCalls: 1},
+		{name: "ignored event", event: "push", fixture: "push.json", secret: testSecret, expectedStatus: http.StatusAccepted},
+		{name: "invalid signature", event: "pull_request", fixture: "pull_request.json", secret: "wrong-secret", expectedStatus: http.StatusUnauthorized},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			payload, err := os.ReadFile(filepath.Join("testdata", tc.fixture))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			service := &mockService{}
+			handlers := NewHandlers(service, &config.GitHubConfig{
+				WebhookSecret:          testSecret,
+				WebhookRegenerateGantt: true,
+			})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			req := httptest.NewRequest(http.MethodPost, "/webhooks/github", bytes.NewReader(payload)).WithContext(ctx)
+			req.Header.Set("X-GitHub-Event", tc.event)
+			req.Header.Set("X-Hub-Signature-256", sign(tc.secret, payload))
+			rec := httptest.NewRecorder()
+
+			handlers.PostGitHub()(rec, req)
+
+			// GitHub closing the delivery must not abort the refresh.
+			cancel()
+			if err := handlers.Shutdown(context.Background()); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if rec.Code != tc.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tc.expectedStatus, rec.Code, rec.Body.String())
+			}
+
+			if len(service.refreshed) != tc.expectedRefreshed {
+				t.Fatalf("expected %d refreshes, got %d", tc.expectedRefreshed, len(service.refreshed))
+			}
+
+			if tc.expectedRefreshed > 0 {
+				refreshed := service.refreshed[0]
+				if refreshed.Owner != "graphql-go" || refreshed.Repo != "graphql" {
+					t.Errorf("expected graphql-go/graphql to be refreshed, got %s/%s", refreshed.Owner, refreshed.Repo)
+				}
+			}
+
+			if len(service.gantts) != tc.expectedGanttCalls {
+				t.Fatalf("expected %d gantt regenerations, got %d", tc.expectedGanttCalls, len(service.gantts))
+			}
+
+			if tc.expectedGanttCalls > 0 && service.gantts[0].RepositoryURL != "https://github.com/graphql-go/graphql" {
+				t.Errorf("expected gantt of the repository URL, got %s", service.gantts[0].RepositoryURL)
+			}
+
+			if tc.expectedGanttCalls > 0 && service.gantts[0].Limit != metrics.DefaultGanttLimit {
+				t.Errorf("expected gantt of the default limit %d, got %d", metrics.DefaultGanttLimit, service.gantts[0].Limit)
+			}
+		})
+	}
+}
+
+func TestPostGitHubWithoutSecret(t *testing.T) {
+	service := &mockService{}
+	handlers := NewHandlers(service, &config.GitHubConfig{})
+
+	payload := []byte(`{}`)
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/github", bytes.NewReader(payload))
+	req.Header.Set("X-GitHub-Event", "pull_request")
+	req.Header.Set("X-Hub-Signature-256", sign("", payload))
+	rec := httptest.NewRecorder()
+
+	handlers.PostGitHub()(rec, req)
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected unsigned webhooks to be rejected without secret, got status %d", rec.Code)
+	}
+}
@@ -0,0 +1,29 @@
+package webhooks
+
+import (
+	"net/http"
+
+	"github.com/chris-ramon/golang-scaffolding/pkg/route"
+)
+
+type Handlers interface {
+	PostGitHub() http.HandlerFunc
+}
+
+type routes struct {
+	handlers Handlers
+}
+
+func (r *routes) All() []route.Route {
+	return []route.Route{
+		route.Route{
+			HTTPMethod: "POST",
+			Path:       "/webhooks/github",
+			Handler:    r.handlers.PostGitHub(),
+		},
+	}
+}
+
+func NewRoutes(handlers Handlers) *routes {
+	return &routes{handlers: handlers}
+}
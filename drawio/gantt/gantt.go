@@ -56,15 +56,18 @@ type MxCell struct {
 	Parent     string      `xml:"parent,attr,omitempty"`
 	Vertex     string      `xml:"vertex,attr,omitempty"`
 	Edge       string      `xml:"edge,attr,omitempty"`
+	Source     string      `xml:"source,attr,omitempty"`
+	Target     string      `xml:"target,attr,omitempty"`
 	MxGeometry *MxGeometry `xml:"mxGeometry,omitempty"`
 }
 
 // MxGeometry represents the geometry (position and size) of a cell
 type MxGeometry struct {
-	XMLName xml.Name `xml:"mxGeometry"`
-	X       string   `xml:"x,attr,omitempty"`
-	Y       string   `xml:"y,attr,omitempty"`
-	Width   string   `xml:"width,attr,omitempty"`
-	Height  string   `xml:"height,attr,omitempty"`
-	As      string   `xml:"as,attr,omitempty"`
+	XMLName  xml.Name `xml:"mxGeometry"`
+	X        string   `xml:"x,attr,omitempty"`
+	Y        string   `xml:"y,attr,omitempty"`
+	Width    string   `xml:"width,attr,omitempty"`
+	Height   string   `xml:"height,attr,omitempty"`
+	Relative string   `xml:"relative,attr,omitempty"`
+	As       string   `xml:"as,attr,omitempty"`
 }
@@ -1,10 +1,16 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
 	"slices"
+	"syscall"
+	"time"
 
 	cachePkg "github.com/chris-ramon/golang-scaffolding/cache"
 	"github.com/chris-ramon/golang-scaffolding/config"
@@ -15,9 +21,14 @@ import (
 	"github.com/chris-ramon/golang-scaffolding/domain/metrics"
 	"github.com/chris-ramon/golang-scaffolding/domain/solutions"
 	"github.com/chris-ramon/golang-scaffolding/domain/users"
+	"github.com/chris-ramon/golang-scaffolding/domain/webhooks"
 	"github.com/chris-ramon/golang-scaffolding/pkg/jwt"
 )
 
+// shutdownTimeout is the maximum duration of the graceful shutdown, the requests in flight and the repository
+// refreshes of the GitHub webhooks being interrupted past it.
+const shutdownTimeout = time.Minute
+
 func main() {
 	handleErr := func(err error) {
 		log.Fatal(err)
@@ -67,12 +78,20 @@ func main() {
 		handleErr(err)
 	}
 
-	HTTPClient := &http.Client{}
-	metricsService, err := metrics.NewService(cache, HTTPClient)
+	metricsRepo := metrics.NewRepo(db)
+	metricsService, err := metrics.NewService(cache, metricsRepo, conf)
 	if err != nil {
 		handleErr(err)
 	}
 
+	if conf.MetricsConfig.SyncInterval > 0 {
+		scheduler := metrics.NewScheduler(metricsService, conf.MetricsConfig.SyncInterval)
+		go scheduler.Run(context.Background())
+	}
+
+	webhooksHandlers := webhooks.NewHandlers(metricsService, conf.GitHubConfig)
+	webhooksRoutes := webhooks.NewRoutes(webhooksHandlers)
+
 	gqlHandlers, err := gql.NewHandlers(authService, usersService, solutionService, metricsService)
 	if err != nil {
 		handleErr(err)
@@ -91,6 +110,7 @@ func main() {
 		gqlRoutes.All(),
 		adminRoutes.All(),
 		usersRoutes.All(),
+		webhooksRoutes.All(),
 	)
 
 	router := http.NewServeMux()
@@ -99,6 +119,30 @@ func main() {
 		router.HandleFunc(fmt.Sprintf("%s %s", r.HTTPMethod, r.Path), r.Handler)
 	}
 
-	log.Printf("server running on port :%s", conf.Port)
-	log.Println(http.ListenAndServe(fmt.Sprintf(":%s", conf.Port), router))
+	server := &http.Server{Addr: fmt.Sprintf(":%s", conf.Port), Handler: router}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		log.Printf("server running on port :%s", conf.Port)
+		if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+			handleErr(err)
+		}
+	}()
+
+	<-ctx.Done()
+	log.Println("shutting down server")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("failed to shut down server: %v", err)
+	}
+
+	// The webhook deliveries have all returned, so no other refresh starts while waiting.
+	if err := webhooksHandlers.Shutdown(shutdownCtx); err != nil {
+		log.Printf("failed to wait for the github webhook refreshes: %v", err)
+	}
 }
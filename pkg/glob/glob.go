@@ -0,0 +1,80 @@
+package glob
+
+import (
+	"errors"
+	"path"
+	"strings"
+)
+
+// ErrEmptyPattern is the error of the empty patterns.
+var ErrEmptyPattern = errors.New("empty glob pattern")
+
+// Validate returns an error when the given pattern is malformed.
+func Validate(pattern string) error {
+	if strings.Trim(pattern, "/") == "" {
+		return ErrEmptyPattern
+	}
+
+	for _, segment := range segments(pattern) {
+		if _, err := path.Match(segment, ""); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Match reports whether the given slash-separated file path, or one of its parent directories, matches the
+// given pattern. Each pattern segment follows the `path.Match` syntax and a `**` segment matches any number of
+// directories, e.g. `services/billing` and `services/*/api/**/*.go`. Malformed patterns match nothing.
+func Match(pattern string, name string) bool {
+	if Validate(pattern) != nil {
+		return false
+	}
+
+	return match(segments(pattern), segments(name))
+}
+
+// MatchAny reports whether one of the given file paths matches one of the given patterns.
+func MatchAny(patterns []string, names []string) bool {
+	for _, name := range names {
+		for _, pattern := range patterns {
+			if Match(pattern, name) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// match reports whether the leading name segments match the pattern segments.
+func match(pattern []string, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if match(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(name) == 0 {
+			return false
+		}
+
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+
+		pattern, name = pattern[1:], name[1:]
+	}
+
+	return true
+}
+
+// segments returns the segments of the given slash-separated path.
+func segments(p string) []string {
+	return strings.Split(strings.Trim(p, "/"), "/")
+}
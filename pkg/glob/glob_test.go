@@ -0,0 +1,62 @@
+package glob
+
+import (
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	testCases := []struct {
+		name     string
+		pattern  string
+		path     string
+		expected bool
+	}{
+		{name: "exact file", pattern: "services/api/main.go", path: "services/api/main.go", expected: true},
+		{name: "parent directory", pattern: "services/api", path: "services/api/handlers/users.go", expected: true},
+		{name: "trailing slash", pattern: "services/api/", path: "services/api/main.go", expected: true},
+		{name: "sibling directory", pattern: "services/api", path: "services/apigateway/main.go", expected: false},
+		{name: "star segment", pattern: "services/*/main.go", path: "services/billing/main.go", expected: true},
+		{name: "star does not cross directories", pattern: "services/*.go", path: "services/billing/main.go", expected: false},
+		{name: "double star", pattern: "services/**/*.go", path: "services/billing/internal/db/db.go", expected: true},
+		{name: "double star matching no directory", pattern: "services/**/*.go", path: "services/main.go", expected: true},
+		{name: "leading double star", pattern: "**/README.md", path: "libs/auth/README.md", expected: true},
+		{name: "trailing double star", pattern: "libs/**", path: "libs/auth/token.go", expected: true},
+		{name: "other extension", pattern: "services/**/*.go", path: "services/billing/README.md", expected: false},
+		{name: "malformed pattern", pattern: "services/[", path: "services/[", expected: false},
+		{name: "empty pattern", pattern: "", path: "main.go", expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Match(tc.pattern, tc.path); got != tc.expected {
+				t.Errorf("Match(%q, %q) = %v, expected %v", tc.pattern, tc.path, got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestMatchAny(t *testing.T) {
+	files := []string{"README.md", "libs/auth/token.go"}
+
+	if !MatchAny([]string{"services/billing", "libs/auth"}, files) {
+		t.Errorf("expected libs/auth/token.go to match")
+	}
+
+	if MatchAny([]string{"services/billing"}, files) || MatchAny(nil, files) {
+		t.Errorf("expected no file to match")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := Validate("services/**/*.go"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if err := Validate("services/["); err == nil {
+		t.Errorf("expected an error for a malformed pattern")
+	}
+
+	if err := Validate("/"); err != ErrEmptyPattern {
+		t.Errorf("expected ErrEmptyPattern, got %v", err)
+	}
+}
@@ -0,0 +1,252 @@
+package httprecorder
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Mode is the mode of a recorder.
+type Mode string
+
+const (
+	// RecordMode forwards the requests and records their responses to fixture files.
+	RecordMode Mode = "record"
+
+	// ReplayMode answers the requests with their recorded responses, without forwarding them.
+	ReplayMode Mode = "replay"
+)
+
+// Redacted replaces the scrubbed secrets of the recorded exchanges.
+const Redacted = "REDACTED"
+
+// tokenRegexp matches the GitHub tokens: personal access, OAuth, user-to-server, server-to-server and
+// fine-grained personal access tokens.
+var tokenRegexp = regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{20,}|github_pat_[A-Za-z0-9_]{20,})\b`)
+
+// secretHeaders are the headers not recorded.
+var secretHeaders = []string{"Authorization", "Cookie", "Set-Cookie", "Private-Token", "Proxy-Authorization"}
+
+// secretQueryParams are the query parameters scrubbed from the recorded URLs.
+var secretQueryParams = []string{"access_token", "private_token", "token", "client_secret"}
+
+// Recorder records HTTP exchanges to fixture files and replays them deterministically. A request is matched
+// by its method, scrubbed URL and body, repeated requests are replayed in the order they were recorded.
+type Recorder struct {
+	// Dir is the directory of the fixture files.
+	Dir string
+
+	// Mode is the recorder mode.
+	Mode Mode
+
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+// Fixture represents a recorded HTTP exchange.
+type Fixture struct {
+	Request  FixtureRequest  `json:"request"`
+	Response FixtureResponse `json:"response"`
+}
+
+// FixtureRequest represents a recorded HTTP request.
+type FixtureRequest struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	Body   string `json:"body,omitempty"`
+}
+
+// FixtureResponse represents a recorded HTTP response.
+type FixtureResponse struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header"`
+	Body       string      `json:"body"`
+}
+
+// Transport returns a transport recording or replaying the exchanges of the given transport, which is not
+// called when replaying.
+func (r *Recorder) Transport(base http.RoundTripper) http.RoundTripper {
+	return &transport{recorder: r, base: base}
+}
+
+type transport struct {
+	recorder *Recorder
+	base     http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		b, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		body = b
+	}
+
+	fixtureRequest := FixtureRequest{
+		Method: req.Method,
+		URL:    scrubURL(req.URL),
+		Body:   Scrub(string(body)),
+	}
+
+	switch t.recorder.Mode {
+	case ReplayMode:
+		return t.recorder.replay(req, fixtureRequest)
+	case RecordMode:
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		return t.recorder.record(req, fixtureRequest, t.base)
+	default:
+		return nil, fmt.Errorf("unknown recorder mode %q", t.recorder.Mode)
+	}
+}
+
+// record forwards the given request and records its response as the next call of the request.
+func (r *Recorder) record(req *http.Request, fixtureRequest FixtureRequest, base http.RoundTripper) (*http.Response, error) {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	header := resp.Header.Clone()
+	for _, h := range secretHeaders {
+		header.Del(h)
+	}
+
+	fixture := Fixture{
+		Request: fixtureRequest,
+		Response: FixtureResponse{
+			StatusCode: resp.StatusCode,
+			Header:     header,
+			Body:       Scrub(string(body)),
+		},
+	}
+
+	data, err := json.MarshalIndent(fixture, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	path := r.path(fixtureRequest, r.nextCall(fixtureRequest))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return nil, err
+	}
+
+	// The caller reads the response as it was received, only the fixture is scrubbed.
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return resp, nil
+}
+
+// replay returns the recorded response of the next call of the given request, the last recorded one once
+// every recorded call was replayed.
+func (r *Recorder) replay(req *http.Request, fixtureRequest FixtureRequest) (*http.Response, error) {
+	call := r.nextCall(fixtureRequest)
+
+	var data []byte
+	for ; call >= 0; call-- {
+		d, err := os.ReadFile(r.path(fixtureRequest, call))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		data = d
+		break
+	}
+
+	if data == nil {
+		return nil, fmt.Errorf("no recorded response for %s %s in %s", fixtureRequest.Method, fixtureRequest.URL, r.path(fixtureRequest, 0))
+	}
+
+	fixture := Fixture{}
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return nil, err
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", fixture.Response.StatusCode, http.StatusText(fixture.Response.StatusCode)),
+		StatusCode:    fixture.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        fixture.Response.Header,
+		Body:          io.NopCloser(strings.NewReader(fixture.Response.Body)),
+		ContentLength: int64(len(fixture.Response.Body)),
+		Request:       req,
+	}, nil
+}
+
+// nextCall returns the index of the next call of the given request.
+func (r *Recorder) nextCall(fixtureRequest FixtureRequest) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.calls == nil {
+		r.calls = map[string]int{}
+	}
+
+	key := fixtureKey(fixtureRequest)
+	call := r.calls[key]
+	r.calls[key] = call + 1
+
+	return call
+}
+
+// path returns the fixture file of the given call of the given request.
+func (r *Recorder) path(fixtureRequest FixtureRequest, call int) string {
+	return filepath.Join(r.Dir, fmt.Sprintf("%s-%s-%d.json", strings.ToLower(fixtureRequest.Method), fixtureKey(fixtureRequest), call))
+}
+
+// fixtureKey returns the key identifying the given request.
+func fixtureKey(fixtureRequest FixtureRequest) string {
+	sum := sha256.Sum256([]byte(fixtureRequest.Method + "\n" + fixtureRequest.URL + "\n" + fixtureRequest.Body))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Scrub replaces the GitHub tokens of the given text.
+func Scrub(s string) string {
+	return tokenRegexp.ReplaceAllString(s, Redacted)
+}
+
+// scrubURL returns the given URL with its secret query parameters replaced.
+func scrubURL(u *url.URL) string {
+	scrubbed := *u
+	scrubbed.User = nil
+
+	query := scrubbed.Query()
+	for _, param := range secretQueryParams {
+		if query.Has(param) {
+			query.Set(param, Redacted)
+		}
+	}
+	scrubbed.RawQuery = query.Encode()
+
+	return Scrub(scrubbed.String())
+}
@@ -0,0 +1,61 @@
+package trailers
+
+import (
+	"regexp"
+	"strings"
+)
+
+// coAuthoredByRegexp matches `Co-authored-by: Name <email>` commit trailers.
+var coAuthoredByRegexp = regexp.MustCompile(`(?mi)^\s*co-authored-by:\s*(.*?)\s*<([^<>\s]+)>\s*$`)
+
+// noReplyEmailSuffix is the suffix of the GitHub no-reply email addresses.
+const noReplyEmailSuffix = "@users.noreply.github.com"
+
+// CoAuthor represents a `Co-authored-by` commit trailer.
+type CoAuthor struct {
+	// Name is the co-author name.
+	Name string
+
+	// Email is the co-author email.
+	Email string
+}
+
+// Login returns the GitHub login of the co-author when the email is a GitHub no-reply address,
+// e.g. `12345+octocat@users.noreply.github.com` or `octocat@users.noreply.github.com`.
+func (c CoAuthor) Login() string {
+	email := strings.ToLower(c.Email)
+	if !strings.HasSuffix(email, noReplyEmailSuffix) {
+		return ""
+	}
+
+	local := c.Email[:len(c.Email)-len(noReplyEmailSuffix)]
+	if _, login, found := strings.Cut(local, "+"); found {
+		return login
+	}
+
+	return local
+}
+
+// CoAuthors parses and returns the `Co-authored-by` trailers of the given commit message,
+// duplicated emails are returned only once.
+func CoAuthors(message string) []CoAuthor {
+	result := []CoAuthor{}
+	seen := map[string]bool{}
+
+	for _, match := range coAuthoredByRegexp.FindAllStringSubmatch(message, -1) {
+		coAuthor := CoAuthor{
+			Name:  strings.TrimSpace(match[1]),
+			Email: strings.TrimSpace(match[2]),
+		}
+
+		key := strings.ToLower(coAuthor.Email)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		result = append(result, coAuthor)
+	}
+
+	return result
+}
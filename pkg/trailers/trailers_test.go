@@ -0,0 +1,69 @@
+package trailers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCoAuthors(t *testing.T) {
+	testCases := []struct {
+		name     string
+		message  string
+		expected []CoAuthor
+	}{
+		{
+			name:     "no trailers",
+			message:  "Fix typo\n\nSome details.",
+			expected: []CoAuthor{},
+		},
+		{
+			name:    "single trailer",
+			message: "Add feature\n\nCo-authored-by: Jane Doe <jane@example.com>",
+			expected: []CoAuthor{
+				{Name: "Jane Doe", Email: "jane@example.com"},
+			},
+		},
+		{
+			name:    "multiple trailers with mixed case and duplicates",
+			message: "Add feature\n\nco-authored-by: Jane Doe <jane@example.com>\nCo-Authored-By: octocat <1+octocat@users.noreply.github.com>\nCo-authored-by: Jane <JANE@example.com>",
+			expected: []CoAuthor{
+				{Name: "Jane Doe", Email: "jane@example.com"},
+				{Name: "octocat", Email: "1+octocat@users.noreply.github.com"},
+			},
+		},
+		{
+			name:     "trailer without email",
+			message:  "Add feature\n\nCo-authored-by: Jane Doe",
+			expected: []CoAuthor{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := CoAuthors(tc.message)
+			if !reflect.DeepEqual(result, tc.expected) {
+				t.Fatalf("expected: %v, got: %v", tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestCoAuthorLogin(t *testing.T) {
+	testCases := []struct {
+		email    string
+		expected string
+	}{
+		{email: "12345+octocat@users.noreply.github.com", expected: "octocat"},
+		{email: "octocat@users.noreply.github.com", expected: "octocat"},
+		{email: "octocat@example.com", expected: ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.email, func(t *testing.T) {
+			login := CoAuthor{Email: tc.email}.Login()
+			if login != tc.expected {
+				t.Fatalf("expected: %v, got: %v", tc.expected, login)
+			}
+		})
+	}
+}